@@ -0,0 +1,95 @@
+// Package bootstrap initializes the OpenTelemetry tracing for chi apps, it
+// sets up the OTLP exporter, the resource detection & the propagators, and
+// returns the options for the otelchi middleware using them, e.g:
+//
+//	shutdown, opts, err := bootstrap.InitTracing(ctx, "my-service")
+//	if err != nil {
+//		log.Fatalf("unable to initialize tracing due: %v", err)
+//	}
+//	defer shutdown()
+//
+//	r := chi.NewRouter()
+//	r.Use(otelchi.Middleware("my-service", append(opts, otelchi.WithChiRoutes(r))...))
+//
+// The package lives in its own module, so the exporter dependencies are not
+// pulled by the apps only importing otelchi.
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/riandyrn/otelchi"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// InitTracing initializes the tracer provider exporting the spans of the
+// given service through the OTLP HTTP exporter, which is configured from the
+// standard `OTEL_EXPORTER_OTLP_*` environment variables. The tracer provider
+// & the W3C trace context and baggage propagators are set as the global ones
+// unless `WithoutGlobal` is used.
+//
+// The returned shutdown function flushes the buffered spans & releases the
+// exporter, it should be called before the app exits. The returned options
+// make the otelchi middleware use the initialized tracer provider &
+// propagators regardless of the global ones.
+func InitTracing(ctx context.Context, serviceName string, opts ...Option) (shutdown func(), middlewareOpts []otelchi.Option, err error) {
+	cfg := config{shutdownTimeout: defaultShutdownTimeout}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+
+	// create the exporter, by default the otlp exporter loads the endpoint
+	// from the environment variables
+	exporter := cfg.exporter
+	if exporter == nil {
+		var clientOpts []otlptracehttp.Option
+		if cfg.insecure {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to initialize exporter due: %w", err)
+		}
+	}
+
+	res, err := newResource(ctx, serviceName, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to detect resource due: %w", err)
+	}
+
+	// initialize tracer provider
+	providerOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	}
+	if cfg.sampler != nil {
+		providerOpts = append(providerOpts, sdktrace.WithSampler(cfg.sampler))
+	}
+	tp := sdktrace.NewTracerProvider(providerOpts...)
+	propagators := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+	// set tracer provider and propagator globally, this is to ensure all
+	// instrumentation library could run well
+	if !cfg.skipGlobal {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(propagators)
+	}
+
+	shutdown = func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.shutdownTimeout)
+		defer cancel()
+		if err := tp.Shutdown(ctx); err != nil {
+			log.Printf("unable to shutdown tracer provider due: %v", err)
+		}
+	}
+	middlewareOpts = []otelchi.Option{
+		otelchi.WithTracerProvider(tp),
+		otelchi.WithPropagators(propagators),
+	}
+	return shutdown, middlewareOpts, nil
+}
@@ -0,0 +1,74 @@
+package bootstrap_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/riandyrn/otelchi/bootstrap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInitTracing(t *testing.T) {
+	t.Setenv("K8S_POD_NAME", "my-service-7d9f")
+	t.Setenv("POD_NAMESPACE", "default")
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.environment=test")
+
+	globalProvider := otel.GetTracerProvider()
+	exporter := keepingExporter{tracetest.NewInMemoryExporter()}
+	shutdown, opts, err := bootstrap.InitTracing(
+		context.Background(),
+		"my-service",
+		bootstrap.WithExporter(exporter),
+		bootstrap.WithResourceAttributes(attribute.String("service.version", "1.0.0")),
+		bootstrap.WithoutGlobal(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, globalProvider, otel.GetTracerProvider(), "the global tracer provider must not be set")
+
+	// serve the request through the middleware using the returned options
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware("my-service", append(opts, otelchi.WithChiRoutes(router))...))
+	router.Get("/user/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user/123", nil))
+
+	// the buffered spans are flushed on shutdown
+	shutdown()
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "/user/{id}", spans[0].Name)
+
+	// ensure the resource is detected
+	res := spans[0].Resource
+	for _, want := range []attribute.KeyValue{
+		attribute.String("service.name", "my-service"),
+		attribute.String("service.version", "1.0.0"),
+		attribute.String("k8s.pod.name", "my-service-7d9f"),
+		attribute.String("k8s.namespace.name", "default"),
+		attribute.String("deployment.environment", "test"),
+		attribute.String("telemetry.sdk.language", "go"),
+	} {
+		value, ok := res.Set().Value(want.Key)
+		assert.True(t, ok, "missing resource attribute %s", want.Key)
+		assert.Equal(t, want.Value, value)
+	}
+	_, ok := res.Set().Value("host.name")
+	assert.True(t, ok, "missing resource attribute host.name")
+}
+
+// keepingExporter is the in-memory exporter keeping the exported spans on
+// shutdown, so they could be inspected after the buffered spans are flushed.
+type keepingExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (keepingExporter) Shutdown(context.Context) error {
+	return nil
+}
@@ -0,0 +1,88 @@
+package bootstrap
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// defaultShutdownTimeout is the default time given to the tracer provider to
+// flush the buffered spans when the shutdown function is called.
+const defaultShutdownTimeout = 5 * time.Second
+
+// config is used to configure the tracing initialized by `InitTracing`.
+type config struct {
+	exporter           sdktrace.SpanExporter
+	insecure           bool
+	sampler            sdktrace.Sampler
+	resourceAttributes []attribute.KeyValue
+	shutdownTimeout    time.Duration
+	skipGlobal         bool
+}
+
+// Option specifies the tracing initialization options.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (o optionFunc) apply(c *config) {
+	o(c)
+}
+
+// WithExporter specifies the span exporter used instead of the OTLP HTTP
+// exporter, e.g. the stdout exporter for local development or the in-memory
+// exporter for tests.
+func WithExporter(exporter sdktrace.SpanExporter) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.exporter = exporter
+	})
+}
+
+// WithInsecure makes the OTLP HTTP exporter connect to the collector without
+// TLS, e.g. when the collector runs as a sidecar. The collector endpoint is
+// loaded from the `OTEL_EXPORTER_OTLP_ENDPOINT` (or
+// `OTEL_EXPORTER_OTLP_TRACES_ENDPOINT`) environment variable.
+func WithInsecure() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.insecure = true
+	})
+}
+
+// WithSampler specifies the sampler of the tracer provider. If none is
+// specified, the sampler is configured from the `OTEL_TRACES_SAMPLER`
+// environment variable, which defaults to the parent based always on
+// sampler.
+func WithSampler(sampler sdktrace.Sampler) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.sampler = sampler
+	})
+}
+
+// WithResourceAttributes adds the given attributes to the detected resource,
+// they take precedence over the detected ones.
+func WithResourceAttributes(attrs ...attribute.KeyValue) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.resourceAttributes = append(cfg.resourceAttributes, attrs...)
+	})
+}
+
+// WithShutdownTimeout specifies the time given to the tracer provider to
+// flush the buffered spans when the shutdown function is called. The default
+// timeout is 5 seconds.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.shutdownTimeout = timeout
+	})
+}
+
+// WithoutGlobal prevents the tracer provider & the propagators from being set
+// as the global ones, so they are only used by the returned middleware
+// options.
+func WithoutGlobal() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.skipGlobal = true
+	})
+}
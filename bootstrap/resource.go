@@ -0,0 +1,75 @@
+package bootstrap
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// k8sEnvVars maps the Kubernetes resource attributes to the environment
+// variables they are read from, the variables are usually populated by the
+// downward API in the pod spec, e.g:
+//
+//	env:
+//	  - name: K8S_POD_NAME
+//	    valueFrom:
+//	      fieldRef:
+//	        fieldPath: metadata.name
+var k8sEnvVars = []struct {
+	Key  attribute.Key
+	Envs []string
+}{
+	{Key: semconv.K8SPodNameKey, Envs: []string{"K8S_POD_NAME", "POD_NAME"}},
+	{Key: semconv.K8SPodUIDKey, Envs: []string{"K8S_POD_UID", "POD_UID"}},
+	{Key: semconv.K8SNamespaceNameKey, Envs: []string{"K8S_NAMESPACE_NAME", "POD_NAMESPACE"}},
+	{Key: semconv.K8SNodeNameKey, Envs: []string{"K8S_NODE_NAME", "NODE_NAME"}},
+	{Key: semconv.K8SDeploymentNameKey, Envs: []string{"K8S_DEPLOYMENT_NAME"}},
+	{Key: semconv.K8SClusterNameKey, Envs: []string{"K8S_CLUSTER_NAME"}},
+}
+
+// k8sDetector is the resource detector reading the Kubernetes attributes from
+// the environment variables listed in k8sEnvVars.
+type k8sDetector struct{}
+
+// Detect implements `resource.Detector`.
+func (k8sDetector) Detect(context.Context) (*resource.Resource, error) {
+	var attrs []attribute.KeyValue
+	for _, envVar := range k8sEnvVars {
+		for _, env := range envVar.Envs {
+			if value := os.Getenv(env); len(value) > 0 {
+				attrs = append(attrs, envVar.Key.String(value))
+				break
+			}
+		}
+	}
+	if len(attrs) == 0 {
+		return resource.Empty(), nil
+	}
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...), nil
+}
+
+// newResource returns the resource describing the service, it is detected
+// from the host, the container, the Kubernetes environment variables & the
+// `OTEL_RESOURCE_ATTRIBUTES` environment variable.
+func newResource(ctx context.Context, serviceName string, cfg config) (*resource.Resource, error) {
+	attrs := append([]attribute.KeyValue{semconv.ServiceName(serviceName)}, cfg.resourceAttributes...)
+	res, err := resource.New(
+		ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithContainer(),
+		resource.WithDetectors(k8sDetector{}),
+		resource.WithFromEnv(),
+		resource.WithAttributes(attrs...),
+	)
+	// the partial resource is still usable when some detectors fail, e.g.
+	// the container ID is not available outside of the container
+	if res != nil {
+		return res, nil
+	}
+	return nil, err
+}
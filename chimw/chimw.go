@@ -0,0 +1,140 @@
+// Package chimw provides the wrappers of chi stock middlewares annotating
+// the span of the request with their behavior, e.g. whether the request has
+// timed out or been throttled. The span is taken from the request context, so
+// the wrappers must be installed after the tracing middleware, e.g:
+//
+//	router.Use(otelchi.Middleware("my-server"))
+//	router.Use(chimw.Timeout(5 * time.Second))
+//	router.Use(chimw.Throttle(100))
+//	router.Use(chimw.Compress(5))
+package chimw
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/riandyrn/otelchi"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TimeoutKey is the attribute key marking the request which deadline
+	// set by [Timeout] is exceeded.
+	TimeoutKey = attribute.Key("http.server.timeout")
+
+	// ThrottleQueuedEventName is the name of the span event recorded when
+	// the request queued by [Throttle] starts being processed.
+	ThrottleQueuedEventName = "http.server.throttle.queued"
+
+	// ThrottleWaitKey is the attribute key of the time spent by the request
+	// in the queue of [Throttle], in milliseconds.
+	ThrottleWaitKey = attribute.Key("http.server.throttle.wait_ms")
+
+	// ThrottleRejectedKey is the attribute key marking the request rejected
+	// by [Throttle] since the capacity is exceeded or it waited too long in
+	// the queue.
+	ThrottleRejectedKey = attribute.Key("http.server.throttle.rejected")
+
+	// ResponseEncodingKey is the attribute key of the encoding of the
+	// response compressed by [Compress], e.g. `gzip`.
+	ResponseEncodingKey = attribute.Key("http.response.encoding")
+)
+
+// Timeout wraps chi `middleware.Timeout`, the span of the request which
+// deadline is exceeded is marked with `http.server.timeout=true` attribute.
+func Timeout(timeout time.Duration) func(next http.Handler) http.Handler {
+	timeoutMiddleware := middleware.Timeout(timeout)
+	return func(next http.Handler) http.Handler {
+		return timeoutMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+			if r.Context().Err() == context.DeadlineExceeded {
+				oteltrace.SpanFromContext(r.Context()).SetAttributes(TimeoutKey.Bool(true))
+			}
+		}))
+	}
+}
+
+// Throttle wraps chi `middleware.Throttle`, see [ThrottleWithOpts] for the
+// recorded span event & attributes.
+func Throttle(limit int) func(next http.Handler) http.Handler {
+	return ThrottleWithOpts(middleware.ThrottleOpts{Limit: limit, BacklogTimeout: 60 * time.Second})
+}
+
+// ThrottleBacklog wraps chi `middleware.ThrottleBacklog`, see
+// [ThrottleWithOpts] for the recorded span event & attributes.
+func ThrottleBacklog(limit, backlogLimit int, backlogTimeout time.Duration) func(next http.Handler) http.Handler {
+	return ThrottleWithOpts(middleware.ThrottleOpts{Limit: limit, BacklogLimit: backlogLimit, BacklogTimeout: backlogTimeout})
+}
+
+// ThrottleWithOpts wraps chi `middleware.ThrottleWithOpts`. When the request
+// is queued since the limit is reached, the `http.server.throttle.queued`
+// span event holding the time spent in the queue is recorded once the
+// request starts being processed. The span of the request rejected by the
+// throttler is marked with `http.server.throttle.rejected=true` attribute.
+func ThrottleWithOpts(opts middleware.ThrottleOpts) func(next http.Handler) http.Handler {
+	throttleMiddleware := middleware.ThrottleWithOpts(opts)
+
+	// the number of requests being processed, it is used for detecting
+	// whether the incoming request is going to be queued, the limit is
+	// shared by every handler wrapped by the throttler
+	var processing atomic.Int64
+
+	return func(next http.Handler) http.Handler {
+		throttled := throttleMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			processing.Add(1)
+			defer processing.Add(-1)
+
+			state := r.Context().Value(throttleStateCtxKey{}).(*throttleState)
+			state.processed = true
+			if state.queued {
+				wait := time.Since(state.start)
+				oteltrace.SpanFromContext(r.Context()).AddEvent(ThrottleQueuedEventName, oteltrace.WithAttributes(
+					ThrottleWaitKey.Float64(float64(wait)/float64(time.Millisecond)),
+				))
+			}
+			next.ServeHTTP(w, r)
+		}))
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			state := &throttleState{
+				start:  time.Now(),
+				queued: processing.Load() >= int64(opts.Limit),
+			}
+			throttled.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), throttleStateCtxKey{}, state)))
+			if !state.processed {
+				oteltrace.SpanFromContext(r.Context()).SetAttributes(ThrottleRejectedKey.Bool(true))
+			}
+		})
+	}
+}
+
+type throttleStateCtxKey struct{}
+
+// throttleState tracks the request passing through the throttler.
+type throttleState struct {
+	start     time.Time
+	queued    bool
+	processed bool
+}
+
+// Compress wraps chi `middleware.Compress`, the encoding of the compressed
+// response is recorded as `http.response.encoding` attribute. The compression
+// middleware is also wrapped by `otelchi.WrapCompress`, so the size of the
+// response body before compression is recorded when the tracing middleware
+// is created with `otelchi.WithCompressionAttributes`.
+func Compress(level int, types ...string) func(next http.Handler) http.Handler {
+	compressMiddleware := otelchi.WrapCompress(middleware.Compress(level, types...))
+	return func(next http.Handler) http.Handler {
+		compressed := compressMiddleware(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			compressed.ServeHTTP(w, r)
+			if encoding := w.Header().Get("Content-Encoding"); len(encoding) > 0 {
+				oteltrace.SpanFromContext(r.Context()).SetAttributes(ResponseEncodingKey.String(encoding))
+			}
+		})
+	}
+}
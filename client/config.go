@@ -0,0 +1,104 @@
+package client
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// config is used to configure the transport.
+type config struct {
+	base           http.RoundTripper
+	tracerProvider oteltrace.TracerProvider
+	propagators    propagation.TextMapPropagator
+	peerServices   map[string]string
+	baggageMembers map[string]struct{}
+}
+
+// Option specifies instrumentation configuration options.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (o optionFunc) apply(c *config) {
+	o(c)
+}
+
+// newConfig returns the transport configuration with the given options
+// applied on top of the defaults.
+func newConfig(opts ...Option) config {
+	cfg := config{}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	if cfg.base == nil {
+		cfg.base = http.DefaultTransport
+	}
+	if cfg.tracerProvider == nil {
+		cfg.tracerProvider = otel.GetTracerProvider()
+	}
+	if cfg.propagators == nil {
+		cfg.propagators = otel.GetTextMapPropagator()
+	}
+	return cfg
+}
+
+// WithBase specifies the round tripper actually sending the requests. If none
+// is specified, `http.DefaultTransport` is used.
+func WithBase(base http.RoundTripper) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.base = base
+	})
+}
+
+// WithTracerProvider specifies a tracer provider to use for creating a tracer.
+// If none is specified, the global provider is used.
+func WithTracerProvider(provider oteltrace.TracerProvider) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.tracerProvider = provider
+	})
+}
+
+// WithPropagators specifies propagators to use for injecting the span context
+// into the outgoing requests. If none are specified, global ones will be
+// used.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.propagators = propagators
+	})
+}
+
+// WithPeerServices specifies the registry of the services called through the
+// transport, it maps the host (e.g. `back-svc`) or the host & port (e.g.
+// `back-svc:8091`) of the request URL to the name of the service recorded as
+// `peer.service` attribute. The host & port entry takes precedence over the
+// host one.
+func WithPeerServices(services map[string]string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.peerServices = make(map[string]string, len(services))
+		for host, service := range services {
+			cfg.peerServices[host] = service
+		}
+	})
+}
+
+// WithBaggageMembers specifies the baggage members propagated to the called
+// services, e.g. the tenant ID extracted by the server middleware from the
+// incoming request. Only the selected members found in the request context
+// are written into the `baggage` header, the other members stay within the
+// service. The members are propagated even when the configured propagators
+// don't include the baggage propagator.
+func WithBaggageMembers(keys ...string) Option {
+	return optionFunc(func(cfg *config) {
+		if cfg.baggageMembers == nil {
+			cfg.baggageMembers = make(map[string]struct{}, len(keys))
+		}
+		for _, key := range keys {
+			cfg.baggageMembers[key] = struct{}{}
+		}
+	})
+}
@@ -0,0 +1,180 @@
+// Package client provides the instrumented `http.RoundTripper` for calling
+// other services from the handlers traced by otelchi, so the client spans are
+// parented to the server span of the request and the trace context is
+// propagated to the called services, e.g:
+//
+//	httpClient := &http.Client{
+//		Transport: client.NewTransport(
+//			client.WithPeerServices(map[string]string{"back-svc": "back-svc"}),
+//			client.WithBaggageMembers("tenant.id"),
+//		),
+//	}
+package client
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/riandyrn/otelchi"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope name of the tracer used by the
+// transport.
+const tracerName = "github.com/riandyrn/otelchi/client"
+
+// transport is the instrumented `http.RoundTripper` returned by
+// `NewTransport`.
+type transport struct {
+	config
+
+	tracer oteltrace.Tracer
+}
+
+// NewTransport returns the `http.RoundTripper` creating the client span for
+// every request sent through it, similar to `otelhttp.Transport`. The span is
+// named after the request method, it records the stable HTTP client semantic
+// conventions attributes & the `peer.service` attribute resolved from the
+// registry set by `WithPeerServices`. The span is ended once the response
+// headers are received, the response body is not awaited.
+//
+// The span context & the baggage members selected by `WithBaggageMembers` are
+// injected into the outgoing request headers, the original request is never
+// modified.
+func NewTransport(opts ...Option) http.RoundTripper {
+	cfg := newConfig(opts...)
+	return &transport{
+		config: cfg,
+		tracer: cfg.tracerProvider.Tracer(
+			tracerName,
+			oteltrace.WithInstrumentationVersion(otelchi.Version()),
+		),
+	}
+}
+
+// RoundTrip implements `http.RoundTripper`.
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx, span := t.tracer.Start(
+		r.Context(),
+		r.Method,
+		oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+		oteltrace.WithAttributes(t.requestAttributes(r)...),
+	)
+	defer span.End()
+
+	if t.baggageMembers != nil {
+		ctx = baggage.ContextWithBaggage(ctx, t.selectedBaggage(baggage.FromContext(ctx)))
+	}
+	r = r.Clone(ctx)
+	t.propagators.Inject(ctx, propagation.HeaderCarrier(r.Header))
+	if t.baggageMembers != nil {
+		propagation.Baggage{}.Inject(ctx, propagation.HeaderCarrier(r.Header))
+	}
+
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, "")
+	}
+	return resp, nil
+}
+
+// requestAttributes returns the attributes of the client span known before
+// the request is sent.
+func (t *transport) requestAttributes(r *http.Request) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(r.Method),
+		semconv.URLFull(redactedURL(r.URL)),
+	}
+	host, port := splitHostPort(r)
+	if len(host) > 0 {
+		attrs = append(attrs, semconv.ServerAddress(host))
+	}
+	if port > 0 {
+		attrs = append(attrs, semconv.ServerPort(port))
+	}
+	if service, ok := t.peerService(host, port); ok {
+		attrs = append(attrs, semconv.PeerService(service))
+	}
+	return attrs
+}
+
+// peerService returns the name of the called service from the registry set
+// by `WithPeerServices`, the host & port entry takes precedence over the host
+// one.
+func (t *transport) peerService(host string, port int) (string, bool) {
+	if len(t.peerServices) == 0 || len(host) == 0 {
+		return "", false
+	}
+	if port > 0 {
+		if service, ok := t.peerServices[net.JoinHostPort(host, strconv.Itoa(port))]; ok {
+			return service, true
+		}
+	}
+	service, ok := t.peerServices[host]
+	return service, ok
+}
+
+// selectedBaggage returns the baggage containing only the members selected by
+// `WithBaggageMembers`.
+func (t *transport) selectedBaggage(bag baggage.Baggage) baggage.Baggage {
+	var members []baggage.Member
+	for _, member := range bag.Members() {
+		if _, ok := t.baggageMembers[member.Key()]; ok {
+			members = append(members, member)
+		}
+	}
+	selected, err := baggage.New(members...)
+	if err != nil {
+		return baggage.Baggage{}
+	}
+	return selected
+}
+
+// splitHostPort returns the host & the port the request is sent to, the port
+// is derived from the URL scheme when it is not set explicitly.
+func splitHostPort(r *http.Request) (string, int) {
+	hostPort := r.URL.Host
+	if len(hostPort) == 0 {
+		hostPort = r.Host
+	}
+	host, portStr, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		host, portStr = hostPort, ""
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port <= 0 {
+		switch r.URL.Scheme {
+		case "http":
+			port = 80
+		case "https":
+			port = 443
+		default:
+			port = 0
+		}
+	}
+	return host, port
+}
+
+// redactedURL returns the given URL without the user credentials, so they
+// are never recorded in the span.
+func redactedURL(u *url.URL) string {
+	if u.User == nil {
+		return u.String()
+	}
+	redacted := *u
+	redacted.User = nil
+	return redacted.String()
+}
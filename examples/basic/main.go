@@ -53,9 +53,9 @@ func main() {
 	r := chi.NewRouter()
 	r.Use(
 		otelchi.Middleware(serverName, otelchi.WithChiRoutes(r)),
-		otelchimetric.NewRequestDurationMillis(baseCfg),
-		otelchimetric.NewRequestInFlight(baseCfg),
-		otelchimetric.NewResponseSizeBytes(baseCfg),
+		otelchimetric.MustNewRequestDurationMillis(baseCfg),
+		otelchimetric.MustNewRequestInFlight(baseCfg),
+		otelchimetric.MustNewResponseSizeBytes(baseCfg),
 	)
 	r.HandleFunc("/users/{id:[0-9]+}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		id := chi.URLParam(r, "id")
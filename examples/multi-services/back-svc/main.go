@@ -10,7 +10,9 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/riandyrn/otelchi"
+	"github.com/riandyrn/otelchi/bootstrap"
 	"github.com/riandyrn/otelchi/examples/multi-services/utils"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -20,14 +22,19 @@ const (
 )
 
 func main() {
-	// init tracer provider
-	tracer, err := utils.NewTracer(serviceName)
+	// initialize tracing, notice that here we are using insecure option
+	// because we just want to export the trace locally, also notice that
+	// here we don't set any endpoint because by default the otel will load
+	// the endpoint from the environment variable `OTEL_EXPORTER_OTLP_ENDPOINT`
+	shutdown, otelchiOpts, err := bootstrap.InitTracing(context.Background(), serviceName, bootstrap.WithInsecure())
 	if err != nil {
-		log.Fatalf("unable to initialize tracer provider due: %v", err)
+		log.Fatalf("unable to initialize tracing due: %v", err)
 	}
+	defer shutdown()
+	tracer := otel.Tracer(serviceName)
 	// define router
 	r := chi.NewRouter()
-	r.Use(otelchi.Middleware(serviceName, otelchi.WithChiRoutes(r)))
+	r.Use(otelchi.Middleware(serviceName, append(otelchiOpts, otelchi.WithChiRoutes(r))...))
 	r.Get("/", utils.HealthCheckHandler)
 	r.Get("/name", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(generateName(r.Context(), tracer)))
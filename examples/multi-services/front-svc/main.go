@@ -10,8 +10,10 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/riandyrn/otelchi"
+	"github.com/riandyrn/otelchi/bootstrap"
+	"github.com/riandyrn/otelchi/client"
 	"github.com/riandyrn/otelchi/examples/multi-services/utils"
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -22,15 +24,28 @@ const (
 	serviceName          = "front-svc"
 )
 
+// httpClient is the http client instrumented by otelchi, the calls to the
+// back service are recorded as client spans with `peer.service=back-svc`.
+var httpClient = &http.Client{
+	Transport: client.NewTransport(
+		client.WithPeerServices(map[string]string{"back-svc": "back-svc"}),
+	),
+}
+
 func main() {
-	// initialize tracer
-	tracer, err := utils.NewTracer(serviceName)
+	// initialize tracing, notice that here we are using insecure option
+	// because we just want to export the trace locally, also notice that
+	// here we don't set any endpoint because by default the otel will load
+	// the endpoint from the environment variable `OTEL_EXPORTER_OTLP_ENDPOINT`
+	shutdown, otelchiOpts, err := bootstrap.InitTracing(context.Background(), serviceName, bootstrap.WithInsecure())
 	if err != nil {
-		log.Fatalf("unable to initialize tracer due: %v", err)
+		log.Fatalf("unable to initialize tracing due: %v", err)
 	}
+	defer shutdown()
+	tracer := otel.Tracer(serviceName)
 	// define router
 	r := chi.NewRouter()
-	r.Use(otelchi.Middleware(serviceName, otelchi.WithChiRoutes(r)))
+	r.Use(otelchi.Middleware(serviceName, append(otelchiOpts, otelchi.WithChiRoutes(r))...))
 	r.Get("/", utils.HealthCheckHandler)
 	r.Get("/greet", func(w http.ResponseWriter, r *http.Request) {
 		name, err := getRandomName(r.Context(), tracer)
@@ -56,7 +71,14 @@ func getRandomName(ctx context.Context, tracer trace.Tracer) (string, error) {
 
 	// call back service, notice that here we call the service using instrumented
 	// http client
-	resp, err := otelhttp.Get(ctx, os.Getenv(envKeyBackServiceURL)+"/name")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, os.Getenv(envKeyBackServiceURL)+"/name", nil)
+	if err != nil {
+		err = fmt.Errorf("unable to create http request due: %w", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		err = fmt.Errorf("unable to execute http request due: %w", err)
 		span.RecordError(err)
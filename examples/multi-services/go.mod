@@ -2,16 +2,16 @@ module github.com/riandyrn/otelchi/examples/multi-services
 
 go 1.22.0
 
-replace github.com/riandyrn/otelchi => ../../
+replace (
+	github.com/riandyrn/otelchi => ../../
+	github.com/riandyrn/otelchi/bootstrap => ../../bootstrap
+)
 
 require (
 	github.com/go-chi/chi/v5 v5.1.0
 	github.com/riandyrn/otelchi v0.11.0
-	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.56.0
+	github.com/riandyrn/otelchi/bootstrap v0.0.0
 	go.opentelemetry.io/otel v1.32.0
-	go.opentelemetry.io/otel/exporters/otlp/otlptrace v1.32.0
-	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.32.0
-	go.opentelemetry.io/otel/sdk v1.32.0
 	go.opentelemetry.io/otel/trace v1.32.0
 )
 
@@ -22,7 +22,11 @@ require (
 	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.23.0 // indirect
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace v1.32.0 // indirect
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.32.0 // indirect
+	go.opentelemetry.io/otel/log v0.8.0 // indirect
 	go.opentelemetry.io/otel/metric v1.32.0 // indirect
+	go.opentelemetry.io/otel/sdk v1.32.0 // indirect
 	go.opentelemetry.io/proto/otlp v1.3.1 // indirect
 	golang.org/x/net v0.30.0 // indirect
 	golang.org/x/sys v0.27.0 // indirect
@@ -0,0 +1,32 @@
+// Package attrfilter provides the attribute filtering shared by otelchi
+// tracing middleware and metric recorders.
+package attrfilter
+
+import "go.opentelemetry.io/otel/attribute"
+
+// Fn returns true when the attribute should be kept.
+type Fn func(attribute.KeyValue) bool
+
+// Apply returns the attributes kept by the filter. The given slice is
+// returned as it is when the filter is nil or all attributes are kept,
+// otherwise a new slice is returned so the given one is never modified.
+func Apply(keep Fn, attrs []attribute.KeyValue) []attribute.KeyValue {
+	if keep == nil {
+		return attrs
+	}
+	for i, attr := range attrs {
+		if keep(attr) {
+			continue
+		}
+		// copy the kept attributes only once the first one is dropped
+		filtered := make([]attribute.KeyValue, i, len(attrs)-1)
+		copy(filtered, attrs[:i])
+		for _, attr := range attrs[i+1:] {
+			if keep(attr) {
+				filtered = append(filtered, attr)
+			}
+		}
+		return filtered
+	}
+	return attrs
+}
@@ -0,0 +1,60 @@
+// Package cardinality provides guard for limiting the number of distinct
+// attribute values emitted by the instrumentation.
+package cardinality
+
+import "sync"
+
+// DefaultFallback is the value used in place of the values exceeding the
+// limit when no fallback is specified.
+const DefaultFallback = "_other_"
+
+// Limiter limits the number of distinct values, the values seen after the
+// limit is reached are collapsed into the fallback value. It is safe for
+// concurrent use.
+type Limiter struct {
+	max      int
+	fallback string
+
+	mu   sync.RWMutex
+	seen map[string]struct{}
+}
+
+// New returns a new limiter allowing at most max distinct values. If the
+// fallback is empty, DefaultFallback is used.
+func New(max int, fallback string) *Limiter {
+	if len(fallback) == 0 {
+		fallback = DefaultFallback
+	}
+	return &Limiter{
+		max:      max,
+		fallback: fallback,
+		seen:     map[string]struct{}{},
+	}
+}
+
+// Limit returns the given value if it has been seen before or the limit is
+// not reached yet, otherwise it returns the fallback value. A nil limiter
+// returns the value as it is.
+func (l *Limiter) Limit(value string) string {
+	if l == nil {
+		return value
+	}
+
+	l.mu.RLock()
+	_, ok := l.seen[value]
+	l.mu.RUnlock()
+	if ok {
+		return value
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.seen[value]; ok {
+		return value
+	}
+	if len(l.seen) >= l.max {
+		return l.fallback
+	}
+	l.seen[value] = struct{}{}
+	return value
+}
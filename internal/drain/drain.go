@@ -0,0 +1,64 @@
+// Package drain holds the process-wide draining state shared by otelchi
+// tracing middleware and metric recorders.
+package drain
+
+import (
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Key is the attribute key used for marking requests served while the server
+// is draining.
+const Key = attribute.Key("server.draining")
+
+// InFlightKey is the attribute key of the number of requests in flight when
+// the request served while the server is draining is started.
+const InFlightKey = attribute.Key("server.draining.requests_inflight")
+
+var (
+	draining       atomic.Bool
+	inFlight       atomic.Int64
+	inFlightAtMark atomic.Int64
+)
+
+// Mark marks the server as draining, the number of requests in flight is
+// snapshotted when the server was not draining before.
+func Mark() {
+	if draining.CompareAndSwap(false, true) {
+		inFlightAtMark.Store(inFlight.Load())
+	}
+}
+
+// Unmark marks the server as no longer draining.
+func Unmark() {
+	draining.Store(false)
+}
+
+// Active returns true when the server is draining.
+func Active() bool {
+	return draining.Load()
+}
+
+// Begin registers the request in flight, it returns the number of requests
+// in flight including the registered one. Every call must be paired with
+// the call to End.
+func Begin() int64 {
+	return inFlight.Add(1)
+}
+
+// End unregisters the request in flight registered by Begin.
+func End() {
+	inFlight.Add(-1)
+}
+
+// InFlight returns the number of requests currently in flight.
+func InFlight() int64 {
+	return inFlight.Load()
+}
+
+// InFlightAtMark returns the number of requests in flight when the server
+// was marked as draining.
+func InFlightAtMark() int64 {
+	return inFlightAtMark.Load()
+}
@@ -0,0 +1,42 @@
+// Package health holds the health-check endpoint detection shared by otelchi
+// tracing middleware and metric recorders.
+package health
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Key is the attribute key used for marking health-check requests.
+const Key = attribute.Key("http.route.health_check")
+
+// DefaultPaths are the health-check endpoint paths used when no paths are
+// specified.
+var DefaultPaths = []string{"/healthz", "/livez", "/readyz", "/ping"}
+
+// Paths is the set of health-check endpoint paths.
+type Paths map[string]struct{}
+
+// NewPaths returns the set of the given paths, DefaultPaths is used when no
+// paths are given.
+func NewPaths(paths ...string) Paths {
+	if len(paths) == 0 {
+		paths = DefaultPaths
+	}
+	set := make(Paths, len(paths))
+	for _, path := range paths {
+		set[path] = struct{}{}
+	}
+	return set
+}
+
+// Match returns true when the request targets any of the paths. It is safe
+// to call on a nil set.
+func (p Paths) Match(r *http.Request) bool {
+	if len(p) == 0 {
+		return false
+	}
+	_, ok := p[r.URL.Path]
+	return ok
+}
@@ -0,0 +1,52 @@
+// Package routeattr holds the registry of the static attributes per route
+// pattern shared by otelchi tracing middleware and metric recorders.
+package routeattr
+
+import (
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Registry holds the static attributes keyed by route pattern.
+type Registry []entry
+
+type entry struct {
+	pattern string
+	attrs   []attribute.KeyValue
+}
+
+// New returns the registry of the given attributes keyed by route pattern,
+// the patterns are sorted so the lookup doesn't depend on the map order.
+func New(attrs map[string][]attribute.KeyValue) Registry {
+	registry := make(Registry, 0, len(attrs))
+	for pattern, kv := range attrs {
+		registry = append(registry, entry{pattern: pattern, attrs: kv})
+	}
+	sort.Slice(registry, func(i, j int) bool {
+		return registry[i].pattern < registry[j].pattern
+	})
+	return registry
+}
+
+// Lookup returns the attributes of the given route pattern. The pattern
+// ending with `/*` also matches every route under the prefix, the exact match
+// takes precedence over the prefix match, otherwise the first registered
+// pattern is used.
+func (r Registry) Lookup(routePattern string) []attribute.KeyValue {
+	if len(routePattern) == 0 {
+		return nil
+	}
+	var prefixMatch []attribute.KeyValue
+	for _, e := range r {
+		if e.pattern == routePattern {
+			return e.attrs
+		}
+		if prefixMatch == nil && strings.HasSuffix(e.pattern, "/*") &&
+			strings.HasPrefix(routePattern, strings.TrimSuffix(e.pattern, "*")) {
+			prefixMatch = e.attrs
+		}
+	}
+	return prefixMatch
+}
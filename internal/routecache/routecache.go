@@ -0,0 +1,157 @@
+// Package routecache provides the route pattern cache shared by otelchi
+// tracing middleware and metric recorders.
+package routecache
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Cache caches the chi route patterns resolved by the request method & path,
+// it is safe for concurrent use.
+//
+// The cache holds at most size entries, it is purged entirely once it is
+// full. This keeps the memory bounded when the paths contain identifiers
+// (e.g. `/users/123`) while keeping the hot paths cached.
+type Cache struct {
+	size int
+
+	mu      sync.RWMutex
+	entries map[key]entry
+}
+
+type key struct {
+	method string
+	path   string
+}
+
+type entry struct {
+	pattern string
+	matched bool
+}
+
+// New returns a new cache holding at most size entries.
+func New(size int) *Cache {
+	return &Cache{
+		size:    size,
+		entries: make(map[key]entry),
+	}
+}
+
+// Resolve returns the route pattern matched by the given method & path in
+// the routes, it returns false when there is no matching route. The result
+// is served from the cache when available. It is safe to call on a nil
+// cache, in which case the routes are always matched.
+func (c *Cache) Resolve(routes chi.Routes, method, path string) (string, bool) {
+	if c == nil || c.size <= 0 {
+		return match(routes, method, path)
+	}
+
+	k := key{method: method, path: path}
+	c.mu.RLock()
+	e, ok := c.entries[k]
+	c.mu.RUnlock()
+	if ok {
+		return e.pattern, e.matched
+	}
+
+	pattern, matched := match(routes, method, path)
+	c.mu.Lock()
+	if len(c.entries) >= c.size {
+		c.entries = make(map[key]entry)
+	}
+	c.entries[k] = entry{pattern: pattern, matched: matched}
+	c.mu.Unlock()
+
+	return pattern, matched
+}
+
+// ResolveRequest returns the route pattern matched by the given request in
+// the routes, it returns false when there is no matching route.
+//
+// When the routes are mounted as a sub-router (e.g. through chi `Mount`), the
+// request is matched against the path relative to the mount point & the
+// returned pattern includes the mount prefix, e.g. `/api/v1/users/{id}`
+// instead of `/users/{id}`.
+func (c *Cache) ResolveRequest(routes chi.Routes, r *http.Request) (string, bool) {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || len(rctx.RoutePath) == 0 {
+		return c.Resolve(routes, r.Method, r.URL.Path)
+	}
+
+	pattern, matched := c.Resolve(routes, r.Method, rctx.RoutePath)
+	if !matched {
+		return "", false
+	}
+	return joinMountPatterns(rctx.RoutePatterns, pattern), true
+}
+
+// joinMountPatterns joins the given pattern with the mount patterns found in
+// the leading route patterns, the mount patterns are the ones ending with
+// `/*`.
+func joinMountPatterns(routePatterns []string, pattern string) string {
+	n := 0
+	for n < len(routePatterns) && strings.HasSuffix(routePatterns[n], "/*") {
+		n++
+	}
+	if n == 0 {
+		return pattern
+	}
+
+	// reuse the chi logic for joining the route patterns
+	patterns := make([]string, 0, n+1)
+	patterns = append(patterns, routePatterns[:n]...)
+	patterns = append(patterns, pattern)
+	rctx := chi.Context{RoutePatterns: patterns}
+	return rctx.RoutePattern()
+}
+
+// Invalidate removes all entries, it should be called when the routes are
+// changed.
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	c.entries = make(map[key]entry)
+	c.mu.Unlock()
+}
+
+// Entry is the cached route pattern of the request method & path.
+type Entry struct {
+	Method  string
+	Path    string
+	Pattern string
+	Matched bool
+}
+
+// Entries returns the snapshot of the cached entries in no particular order.
+func (c *Cache) Entries() []Entry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entries := make([]Entry, 0, len(c.entries))
+	for k, e := range c.entries {
+		entries = append(entries, Entry{
+			Method:  k.method,
+			Path:    k.path,
+			Pattern: e.pattern,
+			Matched: e.matched,
+		})
+	}
+	return entries
+}
+
+// Len returns the number of cached entries.
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+func match(routes chi.Routes, method, path string) (string, bool) {
+	rctx := chi.NewRouteContext()
+	if !routes.Match(rctx, method, path) {
+		return "", false
+	}
+	return rctx.RoutePattern(), true
+}
@@ -0,0 +1,39 @@
+// Package routenorm holds the route pattern normalization shared by otelchi
+// tracing middleware and metric recorders.
+package routenorm
+
+import "strings"
+
+// StripRegexps returns the given chi route pattern without the regular
+// expression constraints of its parameters, e.g. `/users/{id:[0-9]+}` becomes
+// `/users/{id}`. The braces nested in the expressions (e.g. `{id:[0-9]{3}}`)
+// are taken into account.
+func StripRegexps(pattern string) string {
+	if !strings.Contains(pattern, ":") {
+		return pattern
+	}
+
+	var b strings.Builder
+	b.Grow(len(pattern))
+	depth := 0
+	inRegexp := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				inRegexp = false
+			}
+		case c == ':' && depth == 1 && !inRegexp:
+			inRegexp = true
+			continue
+		}
+		if !inRegexp || (c == '}' && depth == 0) {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,145 @@
+// Package semconvutil provides helpers for building attributes conforming to
+// the stable HTTP semantic conventions.
+package semconvutil
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// HTTPServerRequestMetrics returns the attributes of the given request that
+// are recommended for the HTTP server metrics by the stable semantic
+// conventions. The server parameter is the primary server name, if it is empty
+// the request host will be used instead.
+func HTTPServerRequestMetrics(server string, req *http.Request) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 6)
+	attrs = append(attrs, HTTPMethod(req.Method))
+	attrs = append(attrs, URLScheme(req))
+
+	host, port := ServerHostPort(server, req)
+	if len(host) > 0 {
+		attrs = append(attrs, semconv.ServerAddress(host))
+	}
+	if port > 0 {
+		attrs = append(attrs, semconv.ServerPort(port))
+	}
+
+	name, version := NetworkProtocol(req.Proto)
+	if len(name) > 0 {
+		attrs = append(attrs, semconv.NetworkProtocolName(name))
+	}
+	if len(version) > 0 {
+		attrs = append(attrs, semconv.NetworkProtocolVersion(version))
+	}
+
+	return attrs
+}
+
+// HTTPServerRequest returns the attributes of the given request that are
+// recommended for the HTTP server span by the stable semantic conventions,
+// except `client.address` which depends on the client address resolution
+// configured by the caller. The server parameter is the primary server name,
+// if it is empty the request host will be used instead.
+func HTTPServerRequest(server string, req *http.Request) []attribute.KeyValue {
+	attrs := HTTPServerRequestMetrics(server, req)
+	if method := HTTPMethod(req.Method); method == semconv.HTTPRequestMethodOther {
+		attrs = append(attrs, semconv.HTTPRequestMethodOriginal(req.Method))
+	}
+	if len(req.URL.Path) > 0 {
+		attrs = append(attrs, semconv.URLPath(req.URL.Path))
+	}
+
+	peer, peerPort := splitHostPort(req.RemoteAddr)
+	if len(peer) > 0 {
+		attrs = append(attrs, semconv.NetworkPeerAddress(peer))
+		if peerPort > 0 {
+			attrs = append(attrs, semconv.NetworkPeerPort(peerPort))
+		}
+	}
+
+	if userAgent := req.UserAgent(); len(userAgent) > 0 {
+		attrs = append(attrs, semconv.UserAgentOriginal(userAgent))
+	}
+
+	return attrs
+}
+
+// HTTPMethod returns the `http.request.method` attribute, the methods not
+// known by the semantic conventions are recorded as `_OTHER`.
+func HTTPMethod(method string) attribute.KeyValue {
+	switch method {
+	case "":
+		return semconv.HTTPRequestMethodGet
+	case http.MethodConnect:
+		return semconv.HTTPRequestMethodConnect
+	case http.MethodDelete:
+		return semconv.HTTPRequestMethodDelete
+	case http.MethodGet:
+		return semconv.HTTPRequestMethodGet
+	case http.MethodHead:
+		return semconv.HTTPRequestMethodHead
+	case http.MethodOptions:
+		return semconv.HTTPRequestMethodOptions
+	case http.MethodPatch:
+		return semconv.HTTPRequestMethodPatch
+	case http.MethodPost:
+		return semconv.HTTPRequestMethodPost
+	case http.MethodPut:
+		return semconv.HTTPRequestMethodPut
+	case http.MethodTrace:
+		return semconv.HTTPRequestMethodTrace
+	default:
+		return semconv.HTTPRequestMethodOther
+	}
+}
+
+// URLScheme returns the `url.scheme` attribute of the given request.
+func URLScheme(req *http.Request) attribute.KeyValue {
+	if req.TLS != nil {
+		return semconv.URLScheme("https")
+	}
+	return semconv.URLScheme("http")
+}
+
+// ServerHostPort returns the host & port of the server handling the request,
+// the server parameter is prioritized over the request host. The returned port
+// is -1 when it is unknown.
+func ServerHostPort(server string, req *http.Request) (string, int) {
+	if len(server) == 0 {
+		return splitHostPort(req.Host)
+	}
+	host, port := splitHostPort(server)
+	if port < 0 {
+		_, port = splitHostPort(req.Host)
+	}
+	return host, port
+}
+
+// NetworkProtocol returns the name & version of the given protocol, e.g.
+// `HTTP/1.1` is returned as `http` & `1.1`.
+func NetworkProtocol(proto string) (string, string) {
+	name, version, found := strings.Cut(proto, "/")
+	if !found {
+		return "", ""
+	}
+	return strings.ToLower(name), version
+}
+
+// splitHostPort splits the given address into host & port, the port is -1
+// when it is not present or invalid.
+func splitHostPort(hostport string) (string, int) {
+	host, portStr, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return hostport, -1
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 0 {
+		return host, -1
+	}
+	return host, port
+}
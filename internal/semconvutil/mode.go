@@ -0,0 +1,64 @@
+package semconvutil
+
+import (
+	"os"
+	"strings"
+)
+
+// Mode determines which version of the HTTP semantic conventions is used, it
+// is shared by otelchi tracing middleware & metric recorders.
+type Mode int
+
+const (
+	// ModeOld uses the semantic conventions v1.20.0.
+	ModeOld Mode = iota
+	// ModeNew uses the stable HTTP semantic conventions.
+	ModeNew
+	// ModeDual uses both old & stable HTTP semantic conventions.
+	ModeDual
+)
+
+// StabilityOptInEnv is the environment variable used by OpenTelemetry
+// instrumentations for opting in the stable HTTP semantic conventions.
+const StabilityOptInEnv = "OTEL_SEMCONV_STABILITY_OPT_IN"
+
+// ModeFromEnv returns the semantic conventions mode selected by the
+// `OTEL_SEMCONV_STABILITY_OPT_IN` environment variable: `http` selects
+// ModeNew, `http/dup` selects ModeDual, otherwise ModeOld is returned.
+func ModeFromEnv() Mode {
+	mode := ModeOld
+	for _, v := range strings.Split(os.Getenv(StabilityOptInEnv), ",") {
+		switch strings.TrimSpace(v) {
+		case "http/dup":
+			// `http/dup` takes precedence over `http`
+			return ModeDual
+		case "http":
+			mode = ModeNew
+		}
+	}
+	return mode
+}
+
+// String returns the name of the semantic conventions mode.
+func (m Mode) String() string {
+	switch m {
+	case ModeOld:
+		return "old"
+	case ModeNew:
+		return "new"
+	case ModeDual:
+		return "dual"
+	default:
+		return "unknown"
+	}
+}
+
+// EmitsOld returns true when the old semantic conventions are used.
+func (m Mode) EmitsOld() bool {
+	return m == ModeOld || m == ModeDual
+}
+
+// EmitsNew returns true when the stable semantic conventions are used.
+func (m Mode) EmitsNew() bool {
+	return m == ModeNew || m == ModeDual
+}
@@ -0,0 +1,30 @@
+// Package servername provides server name holder which could be updated
+// safely at runtime.
+package servername
+
+import "sync/atomic"
+
+// Name holds the server name, it is safe for concurrent use.
+type Name struct {
+	v atomic.Pointer[string]
+}
+
+// New returns a new server name holder initialized with the given name.
+func New(name string) *Name {
+	n := &Name{}
+	n.Set(name)
+	return n
+}
+
+// Set updates the server name.
+func (n *Name) Set(name string) {
+	n.v.Store(&name)
+}
+
+// Get returns the current server name.
+func (n *Name) Get() string {
+	if v := n.v.Load(); v != nil {
+		return *v
+	}
+	return ""
+}
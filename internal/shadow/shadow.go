@@ -0,0 +1,23 @@
+// Package shadow holds the shadow (mirrored) traffic detection shared by
+// otelchi tracing middleware and metric recorders.
+package shadow
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Key is the attribute key used for marking shadow requests.
+const Key = attribute.Key("request.shadow")
+
+// DefaultHeader is the request header used for detecting shadow requests
+// when no custom detection function is specified.
+const DefaultHeader = "X-Shadow-Request"
+
+// FromDefaultHeader returns true when the request has `X-Shadow-Request: true`
+// header.
+func FromDefaultHeader(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get(DefaultHeader), "true")
+}
@@ -0,0 +1,351 @@
+// Package metric provides the metric recorders for chi router.
+//
+// The implementation lives in package otelchimetric, this package only holds
+// the aliases of its types & constants and the functions calling it, so the
+// existing code importing otelchi/metric keeps working. The metrics are still
+// recorded under the `github.com/riandyrn/otelchi/metric` instrumentation
+// scope. New code could import otelchimetric directly.
+package metric
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchicore"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+// BaseConfig is an alias of [otelchimetric.BaseConfig].
+type BaseConfig = otelchimetric.BaseConfig
+
+// Option is an alias of [otelchimetric.Option].
+type Option = otelchimetric.Option
+
+// Clock is an alias of [otelchimetric.Clock].
+type Clock = otelchimetric.Clock
+
+// RecorderOption is an alias of [otelchimetric.RecorderOption].
+type RecorderOption = otelchimetric.RecorderOption
+
+// SemconvMode is an alias of [otelchimetric.SemconvMode].
+type SemconvMode = otelchimetric.SemconvMode
+
+const (
+	// ScopeName is an alias of [otelchimetric.ScopeName].
+	ScopeName = otelchimetric.ScopeName
+	// RequestBodyReadErrorEventName is an alias of [otelchimetric.RequestBodyReadErrorEventName].
+	RequestBodyReadErrorEventName = otelchimetric.RequestBodyReadErrorEventName
+	// SemconvOld is an alias of [otelchimetric.SemconvOld].
+	SemconvOld = otelchimetric.SemconvOld
+	// SemconvNew is an alias of [otelchimetric.SemconvNew].
+	SemconvNew = otelchimetric.SemconvNew
+	// SemconvDual is an alias of [otelchimetric.SemconvDual].
+	SemconvDual = otelchimetric.SemconvDual
+	// ThrottledKey is an alias of [otelchimetric.ThrottledKey].
+	ThrottledKey = otelchimetric.ThrottledKey
+	// RetryAfterKey is an alias of [otelchimetric.RetryAfterKey].
+	RetryAfterKey = otelchimetric.RetryAfterKey
+)
+
+// NewActiveRequests calls [otelchimetric.NewActiveRequests].
+func NewActiveRequests(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewActiveRequests(cfg)
+}
+
+// MustNewActiveRequests calls [otelchimetric.MustNewActiveRequests].
+func MustNewActiveRequests(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewActiveRequests(cfg)
+}
+
+// NewActiveRequestsByRoute calls [otelchimetric.NewActiveRequestsByRoute].
+func NewActiveRequestsByRoute(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewActiveRequestsByRoute(cfg)
+}
+
+// MustNewActiveRequestsByRoute calls [otelchimetric.MustNewActiveRequestsByRoute].
+func MustNewActiveRequestsByRoute(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewActiveRequestsByRoute(cfg)
+}
+
+// NewAllMiddlewares calls [otelchimetric.NewAllMiddlewares].
+func NewAllMiddlewares(cfg BaseConfig) ([]func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewAllMiddlewares(cfg)
+}
+
+// MustNewAllMiddlewares calls [otelchimetric.MustNewAllMiddlewares].
+func MustNewAllMiddlewares(cfg BaseConfig) []func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewAllMiddlewares(cfg)
+}
+
+// NewRequestBodySize calls [otelchimetric.NewRequestBodySize].
+func NewRequestBodySize(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewRequestBodySize(cfg)
+}
+
+// MustNewRequestBodySize calls [otelchimetric.MustNewRequestBodySize].
+func MustNewRequestBodySize(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewRequestBodySize(cfg)
+}
+
+// NewResponseBodySize calls [otelchimetric.NewResponseBodySize].
+func NewResponseBodySize(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewResponseBodySize(cfg)
+}
+
+// MustNewResponseBodySize calls [otelchimetric.MustNewResponseBodySize].
+func MustNewResponseBodySize(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewResponseBodySize(cfg)
+}
+
+// WithMeterProvider calls [otelchimetric.WithMeterProvider].
+func WithMeterProvider(provider otelmetric.MeterProvider) Option {
+	return otelchimetric.WithMeterProvider(provider)
+}
+
+// WithMeter calls [otelchimetric.WithMeter].
+func WithMeter(meter otelmetric.Meter) Option {
+	return otelchimetric.WithMeter(meter)
+}
+
+// WithAttributes calls [otelchimetric.WithAttributes].
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return otelchimetric.WithAttributes(attrs...)
+}
+
+// WithSchemaURL calls [otelchimetric.WithSchemaURL].
+func WithSchemaURL(schemaURL string) Option {
+	return otelchimetric.WithSchemaURL(schemaURL)
+}
+
+// WithScopeAttributes calls [otelchimetric.WithScopeAttributes].
+func WithScopeAttributes(attrs ...attribute.KeyValue) Option {
+	return otelchimetric.WithScopeAttributes(attrs...)
+}
+
+// WithDynamicServerName calls [otelchimetric.WithDynamicServerName].
+func WithDynamicServerName(name *otelchicore.ServerName) Option {
+	return otelchimetric.WithDynamicServerName(name)
+}
+
+// WithMaxRouteCardinality calls [otelchimetric.WithMaxRouteCardinality].
+func WithMaxRouteCardinality(n int, fallback string) Option {
+	return otelchimetric.WithMaxRouteCardinality(n, fallback)
+}
+
+// WithServerNameFn calls [otelchimetric.WithServerNameFn].
+func WithServerNameFn(fn func(r *http.Request) string) Option {
+	return otelchimetric.WithServerNameFn(fn)
+}
+
+// WithClock calls [otelchimetric.WithClock].
+func WithClock(clock Clock) Option {
+	return otelchimetric.WithClock(clock)
+}
+
+// WithTimeNow calls [otelchimetric.WithTimeNow].
+func WithTimeNow(now func() time.Time) Option {
+	return otelchimetric.WithTimeNow(now)
+}
+
+// WithShadowTraffic calls [otelchimetric.WithShadowTraffic].
+func WithShadowTraffic(fn func(r *http.Request) bool, exclude bool) Option {
+	return otelchimetric.WithShadowTraffic(fn, exclude)
+}
+
+// WithHealthEndpointsFiltered calls [otelchimetric.WithHealthEndpointsFiltered].
+func WithHealthEndpointsFiltered(paths ...string) Option {
+	return otelchimetric.WithHealthEndpointsFiltered(paths...)
+}
+
+// WithChiRoutes calls [otelchimetric.WithChiRoutes].
+func WithChiRoutes(routes chi.Routes) Option {
+	return otelchimetric.WithChiRoutes(routes)
+}
+
+// WithRouteCache calls [otelchimetric.WithRouteCache].
+func WithRouteCache(cache *otelchicore.RouteCache) Option {
+	return otelchimetric.WithRouteCache(cache)
+}
+
+// WithAttributeFilter calls [otelchimetric.WithAttributeFilter].
+func WithAttributeFilter(fn func(attribute.KeyValue) bool) Option {
+	return otelchimetric.WithAttributeFilter(fn)
+}
+
+// WithMetricNamePrefix calls [otelchimetric.WithMetricNamePrefix].
+func WithMetricNamePrefix(prefix string) Option {
+	return otelchimetric.WithMetricNamePrefix(prefix)
+}
+
+// WithMetricAttributesFn calls [otelchimetric.WithMetricAttributesFn].
+func WithMetricAttributesFn(fn func(r *http.Request) []attribute.KeyValue) Option {
+	return otelchimetric.WithMetricAttributesFn(fn)
+}
+
+// WithMountPrefix calls [otelchimetric.WithMountPrefix].
+func WithMountPrefix(prefix string) Option {
+	return otelchimetric.WithMountPrefix(prefix)
+}
+
+// WithNotFoundRouteLabel calls [otelchimetric.WithNotFoundRouteLabel].
+func WithNotFoundRouteLabel(label string) Option {
+	return otelchimetric.WithNotFoundRouteLabel(label)
+}
+
+// WithRouteAttributes calls [otelchimetric.WithRouteAttributes].
+func WithRouteAttributes(attrs map[string][]attribute.KeyValue) Option {
+	return otelchimetric.WithRouteAttributes(attrs)
+}
+
+// WithRoutePatternNormalizer calls [otelchimetric.WithRoutePatternNormalizer].
+func WithRoutePatternNormalizer(fn func(pattern string) string) Option {
+	return otelchimetric.WithRoutePatternNormalizer(fn)
+}
+
+// StripRouteRegexps calls [otelchimetric.StripRouteRegexps].
+func StripRouteRegexps(pattern string) string {
+	return otelchimetric.StripRouteRegexps(pattern)
+}
+
+// NewBaseConfig calls [otelchimetric.NewBaseConfig].
+func NewBaseConfig(serverName string, opts ...Option) BaseConfig {
+	return otelchimetric.NewBaseConfig(serverName, opts...)
+}
+
+// NewDrainingInFlight calls [otelchimetric.NewDrainingInFlight].
+func NewDrainingInFlight(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewDrainingInFlight(cfg)
+}
+
+// MustNewDrainingInFlight calls [otelchimetric.MustNewDrainingInFlight].
+func MustNewDrainingInFlight(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewDrainingInFlight(cfg)
+}
+
+// NewErrorRate calls [otelchimetric.NewErrorRate].
+func NewErrorRate(cfg BaseConfig, opts ...RecorderOption) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewErrorRate(cfg, opts...)
+}
+
+// MustNewErrorRate calls [otelchimetric.MustNewErrorRate].
+func MustNewErrorRate(cfg BaseConfig, opts ...RecorderOption) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewErrorRate(cfg, opts...)
+}
+
+// WithExplicitBucketBoundaries calls [otelchimetric.WithExplicitBucketBoundaries].
+func WithExplicitBucketBoundaries(bounds ...float64) RecorderOption {
+	return otelchimetric.WithExplicitBucketBoundaries(bounds...)
+}
+
+// WithStatusClass calls [otelchimetric.WithStatusClass].
+func WithStatusClass() RecorderOption {
+	return otelchimetric.WithStatusClass()
+}
+
+// WithErrorStatusThreshold calls [otelchimetric.WithErrorStatusThreshold].
+func WithErrorStatusThreshold(status int) RecorderOption {
+	return otelchimetric.WithErrorStatusThreshold(status)
+}
+
+// NewRequestBodyRead calls [otelchimetric.NewRequestBodyRead].
+func NewRequestBodyRead(cfg BaseConfig, opts ...RecorderOption) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewRequestBodyRead(cfg, opts...)
+}
+
+// MustNewRequestBodyRead calls [otelchimetric.MustNewRequestBodyRead].
+func MustNewRequestBodyRead(cfg BaseConfig, opts ...RecorderOption) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewRequestBodyRead(cfg, opts...)
+}
+
+// NewRequestCounter calls [otelchimetric.NewRequestCounter].
+func NewRequestCounter(cfg BaseConfig, opts ...RecorderOption) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewRequestCounter(cfg, opts...)
+}
+
+// MustNewRequestCounter calls [otelchimetric.MustNewRequestCounter].
+func MustNewRequestCounter(cfg BaseConfig, opts ...RecorderOption) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewRequestCounter(cfg, opts...)
+}
+
+// NewRequestDurationMillis calls [otelchimetric.NewRequestDurationMillis].
+func NewRequestDurationMillis(cfg BaseConfig, opts ...RecorderOption) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewRequestDurationMillis(cfg, opts...)
+}
+
+// MustNewRequestDurationMillis calls [otelchimetric.MustNewRequestDurationMillis].
+func MustNewRequestDurationMillis(cfg BaseConfig, opts ...RecorderOption) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewRequestDurationMillis(cfg, opts...)
+}
+
+// NewRequestDurationSeconds calls [otelchimetric.NewRequestDurationSeconds].
+func NewRequestDurationSeconds(cfg BaseConfig, opts ...RecorderOption) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewRequestDurationSeconds(cfg, opts...)
+}
+
+// MustNewRequestDurationSeconds calls [otelchimetric.MustNewRequestDurationSeconds].
+func MustNewRequestDurationSeconds(cfg BaseConfig, opts ...RecorderOption) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewRequestDurationSeconds(cfg, opts...)
+}
+
+// NewRequestSizeBytes calls [otelchimetric.NewRequestSizeBytes].
+func NewRequestSizeBytes(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewRequestSizeBytes(cfg)
+}
+
+// MustNewRequestSizeBytes calls [otelchimetric.MustNewRequestSizeBytes].
+func MustNewRequestSizeBytes(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewRequestSizeBytes(cfg)
+}
+
+// NewRequestInFlight calls [otelchimetric.NewRequestInFlight].
+func NewRequestInFlight(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewRequestInFlight(cfg)
+}
+
+// MustNewRequestInFlight calls [otelchimetric.MustNewRequestInFlight].
+func MustNewRequestInFlight(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewRequestInFlight(cfg)
+}
+
+// NewResponseSizeBytes calls [otelchimetric.NewResponseSizeBytes].
+func NewResponseSizeBytes(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewResponseSizeBytes(cfg)
+}
+
+// MustNewResponseSizeBytes calls [otelchimetric.MustNewResponseSizeBytes].
+func MustNewResponseSizeBytes(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewResponseSizeBytes(cfg)
+}
+
+// WithSemconvVersion calls [otelchimetric.WithSemconvVersion].
+func WithSemconvVersion(mode SemconvMode) Option {
+	return otelchimetric.WithSemconvVersion(mode)
+}
+
+// NewRequestDuration calls [otelchimetric.NewRequestDuration].
+func NewRequestDuration(cfg BaseConfig, opts ...RecorderOption) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewRequestDuration(cfg, opts...)
+}
+
+// MustNewRequestDuration calls [otelchimetric.MustNewRequestDuration].
+func MustNewRequestDuration(cfg BaseConfig, opts ...RecorderOption) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewRequestDuration(cfg, opts...)
+}
+
+// NewThrottleObserver calls [otelchimetric.NewThrottleObserver].
+func NewThrottleObserver(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	return otelchimetric.NewThrottleObserver(cfg)
+}
+
+// MustNewThrottleObserver calls [otelchimetric.MustNewThrottleObserver].
+func MustNewThrottleObserver(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return otelchimetric.MustNewThrottleObserver(cfg)
+}
+
+// Version calls [otelchimetric.Version].
+func Version() string {
+	return otelchimetric.Version()
+}
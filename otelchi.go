@@ -0,0 +1,769 @@
+// Package otelchi provides the tracing middleware for chi router.
+//
+// The implementation lives in package otelchitrace, this package only holds
+// the aliases of its types & constants and the functions calling it, so the
+// existing code importing otelchi keeps working. New code could import
+// otelchitrace directly.
+package otelchi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchitrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/log"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// ClientIPStrategy is an alias of [otelchitrace.ClientIPStrategy].
+type ClientIPStrategy = otelchitrace.ClientIPStrategy
+
+// ClientIPConfig is an alias of [otelchitrace.ClientIPConfig].
+type ClientIPConfig = otelchitrace.ClientIPConfig
+
+// Clock is an alias of [otelchitrace.Clock].
+type Clock = otelchitrace.Clock
+
+// Option is an alias of [otelchitrace.Option].
+type Option = otelchitrace.Option
+
+// Filter is an alias of [otelchitrace.Filter].
+type Filter = otelchitrace.Filter
+
+// RouteFilter is an alias of [otelchitrace.RouteFilter].
+type RouteFilter = otelchitrace.RouteFilter
+
+// TraceHeaderConfig is an alias of [otelchitrace.TraceHeaderConfig].
+type TraceHeaderConfig = otelchitrace.TraceHeaderConfig
+
+// DebugReport is an alias of [otelchitrace.DebugReport].
+type DebugReport = otelchitrace.DebugReport
+
+// MiddlewareDebugInfo is an alias of [otelchitrace.MiddlewareDebugInfo].
+type MiddlewareDebugInfo = otelchitrace.MiddlewareDebugInfo
+
+// RouteCacheInfo is an alias of [otelchitrace.RouteCacheInfo].
+type RouteCacheInfo = otelchitrace.RouteCacheInfo
+
+// DrainSnapshot is an alias of [otelchitrace.DrainSnapshot].
+type DrainSnapshot = otelchitrace.DrainSnapshot
+
+// Config is an alias of [otelchitrace.Config].
+type Config = otelchitrace.Config
+
+// PropagationMode is an alias of [otelchitrace.PropagationMode].
+type PropagationMode = otelchitrace.PropagationMode
+
+// QueryRecordingMode is an alias of [otelchitrace.QueryRecordingMode].
+type QueryRecordingMode = otelchitrace.QueryRecordingMode
+
+// ResponseInfo is an alias of [otelchitrace.ResponseInfo].
+type ResponseInfo = otelchitrace.ResponseInfo
+
+// RouteCache is an alias of [otelchitrace.RouteCache].
+type RouteCache = otelchitrace.RouteCache
+
+// RouteOption is an alias of [otelchitrace.RouteOption].
+type RouteOption = otelchitrace.RouteOption
+
+// SemconvMode is an alias of [otelchitrace.SemconvMode].
+type SemconvMode = otelchitrace.SemconvMode
+
+// ServerName is an alias of [otelchitrace.ServerName].
+type ServerName = otelchitrace.ServerName
+
+// WebsocketSpanMode is an alias of [otelchitrace.WebsocketSpanMode].
+type WebsocketSpanMode = otelchitrace.WebsocketSpanMode
+
+const (
+	// RequestAbortedKey is an alias of [otelchitrace.RequestAbortedKey].
+	RequestAbortedKey = otelchitrace.RequestAbortedKey
+	// RequestAbortedEventName is an alias of [otelchitrace.RequestAbortedEventName].
+	RequestAbortedEventName = otelchitrace.RequestAbortedEventName
+	// TruncatedAttributesKey is an alias of [otelchitrace.TruncatedAttributesKey].
+	TruncatedAttributesKey = otelchitrace.TruncatedAttributesKey
+	// BatchItemIndexKey is an alias of [otelchitrace.BatchItemIndexKey].
+	BatchItemIndexKey = otelchitrace.BatchItemIndexKey
+	// DefaultRouteCardinalityFallback is an alias of [otelchitrace.DefaultRouteCardinalityFallback].
+	DefaultRouteCardinalityFallback = otelchitrace.DefaultRouteCardinalityFallback
+	// ClientIPStrategyXForwardedFor is an alias of [otelchitrace.ClientIPStrategyXForwardedFor].
+	ClientIPStrategyXForwardedFor = otelchitrace.ClientIPStrategyXForwardedFor
+	// ClientIPStrategyForwarded is an alias of [otelchitrace.ClientIPStrategyForwarded].
+	ClientIPStrategyForwarded = otelchitrace.ClientIPStrategyForwarded
+	// ClientIPStrategyForwardedThenXForwardedFor is an alias of [otelchitrace.ClientIPStrategyForwardedThenXForwardedFor].
+	ClientIPStrategyForwardedThenXForwardedFor = otelchitrace.ClientIPStrategyForwardedThenXForwardedFor
+	// ClientIPStrategyRemoteAddr is an alias of [otelchitrace.ClientIPStrategyRemoteAddr].
+	ClientIPStrategyRemoteAddr = otelchitrace.ClientIPStrategyRemoteAddr
+	// ResponseUncompressedSizeKey is an alias of [otelchitrace.ResponseUncompressedSizeKey].
+	ResponseUncompressedSizeKey = otelchitrace.ResponseUncompressedSizeKey
+	// DefaultTraceIDResponseHeaderKey is an alias of [otelchitrace.DefaultTraceIDResponseHeaderKey].
+	DefaultTraceIDResponseHeaderKey = otelchitrace.DefaultTraceIDResponseHeaderKey
+	// DefaultTraceSampledResponseHeaderKey is an alias of [otelchitrace.DefaultTraceSampledResponseHeaderKey].
+	DefaultTraceSampledResponseHeaderKey = otelchitrace.DefaultTraceSampledResponseHeaderKey
+	// ServerDrainingKey is an alias of [otelchitrace.ServerDrainingKey].
+	ServerDrainingKey = otelchitrace.ServerDrainingKey
+	// ServerDrainingInFlightKey is an alias of [otelchitrace.ServerDrainingInFlightKey].
+	ServerDrainingInFlightKey = otelchitrace.ServerDrainingInFlightKey
+	// GraphQLOperationNameKey is an alias of [otelchitrace.GraphQLOperationNameKey].
+	GraphQLOperationNameKey = otelchitrace.GraphQLOperationNameKey
+	// GraphQLOperationTypeKey is an alias of [otelchitrace.GraphQLOperationTypeKey].
+	GraphQLOperationTypeKey = otelchitrace.GraphQLOperationTypeKey
+	// RedactedHeaderValue is an alias of [otelchitrace.RedactedHeaderValue].
+	RedactedHeaderValue = otelchitrace.RedactedHeaderValue
+	// HealthCheckKey is an alias of [otelchitrace.HealthCheckKey].
+	HealthCheckKey = otelchitrace.HealthCheckKey
+	// DefaultIdempotencyKeyHeader is an alias of [otelchitrace.DefaultIdempotencyKeyHeader].
+	DefaultIdempotencyKeyHeader = otelchitrace.DefaultIdempotencyKeyHeader
+	// IdempotencyKeyKey is an alias of [otelchitrace.IdempotencyKeyKey].
+	IdempotencyKeyKey = otelchitrace.IdempotencyKeyKey
+	// IdempotencyRetryKey is an alias of [otelchitrace.IdempotencyRetryKey].
+	IdempotencyRetryKey = otelchitrace.IdempotencyRetryKey
+	// ForcedSamplingKey is an alias of [otelchitrace.ForcedSamplingKey].
+	ForcedSamplingKey = otelchitrace.ForcedSamplingKey
+	// HeadersReadEventName is an alias of [otelchitrace.HeadersReadEventName].
+	HeadersReadEventName = otelchitrace.HeadersReadEventName
+	// FirstByteWrittenEventName is an alias of [otelchitrace.FirstByteWrittenEventName].
+	FirstByteWrittenEventName = otelchitrace.FirstByteWrittenEventName
+	// ResponseCompleteEventName is an alias of [otelchitrace.ResponseCompleteEventName].
+	ResponseCompleteEventName = otelchitrace.ResponseCompleteEventName
+	// MethodOverrideKey is an alias of [otelchitrace.MethodOverrideKey].
+	MethodOverrideKey = otelchitrace.MethodOverrideKey
+	// DefaultMethodOverrideHeader is an alias of [otelchitrace.DefaultMethodOverrideHeader].
+	DefaultMethodOverrideHeader = otelchitrace.DefaultMethodOverrideHeader
+	// MiddlewareDurationKey is an alias of [otelchitrace.MiddlewareDurationKey].
+	MiddlewareDurationKey = otelchitrace.MiddlewareDurationKey
+	// HandlerDurationKey is an alias of [otelchitrace.HandlerDurationKey].
+	HandlerDurationKey = otelchitrace.HandlerDurationKey
+	// WriteDurationKey is an alias of [otelchitrace.WriteDurationKey].
+	WriteDurationKey = otelchitrace.WriteDurationKey
+	// ProblemTitleKey is an alias of [otelchitrace.ProblemTitleKey].
+	ProblemTitleKey = otelchitrace.ProblemTitleKey
+	// ProblemDetailKey is an alias of [otelchitrace.ProblemDetailKey].
+	ProblemDetailKey = otelchitrace.ProblemDetailKey
+	// PropagationFirstValid is an alias of [otelchitrace.PropagationFirstValid].
+	PropagationFirstValid = otelchitrace.PropagationFirstValid
+	// PropagationMergeBaggage is an alias of [otelchitrace.PropagationMergeBaggage].
+	PropagationMergeBaggage = otelchitrace.PropagationMergeBaggage
+	// QueryRecordingOff is an alias of [otelchitrace.QueryRecordingOff].
+	QueryRecordingOff = otelchitrace.QueryRecordingOff
+	// QueryRecordingRedacted is an alias of [otelchitrace.QueryRecordingRedacted].
+	QueryRecordingRedacted = otelchitrace.QueryRecordingRedacted
+	// QueryRecordingFull is an alias of [otelchitrace.QueryRecordingFull].
+	QueryRecordingFull = otelchitrace.QueryRecordingFull
+	// QueueDurationKey is an alias of [otelchitrace.QueueDurationKey].
+	QueueDurationKey = otelchitrace.QueueDurationKey
+	// DefaultRequestStartHeader is an alias of [otelchitrace.DefaultRequestStartHeader].
+	DefaultRequestStartHeader = otelchitrace.DefaultRequestStartHeader
+	// RedirectEventName is an alias of [otelchitrace.RedirectEventName].
+	RedirectEventName = otelchitrace.RedirectEventName
+	// RedirectLocationKey is an alias of [otelchitrace.RedirectLocationKey].
+	RedirectLocationKey = otelchitrace.RedirectLocationKey
+	// RequestIDKey is an alias of [otelchitrace.RequestIDKey].
+	RequestIDKey = otelchitrace.RequestIDKey
+	// DefaultRequestIDHeader is an alias of [otelchitrace.DefaultRequestIDHeader].
+	DefaultRequestIDHeader = otelchitrace.DefaultRequestIDHeader
+	// DefaultRouteCacheSize is an alias of [otelchitrace.DefaultRouteCacheSize].
+	DefaultRouteCacheSize = otelchitrace.DefaultRouteCacheSize
+	// RouteOriginalKey is an alias of [otelchitrace.RouteOriginalKey].
+	RouteOriginalKey = otelchitrace.RouteOriginalKey
+	// SemconvOld is an alias of [otelchitrace.SemconvOld].
+	SemconvOld = otelchitrace.SemconvOld
+	// SemconvNew is an alias of [otelchitrace.SemconvNew].
+	SemconvNew = otelchitrace.SemconvNew
+	// SemconvDual is an alias of [otelchitrace.SemconvDual].
+	SemconvDual = otelchitrace.SemconvDual
+	// ShadowRequestKey is an alias of [otelchitrace.ShadowRequestKey].
+	ShadowRequestKey = otelchitrace.ShadowRequestKey
+	// DefaultShadowRequestHeader is an alias of [otelchitrace.DefaultShadowRequestHeader].
+	DefaultShadowRequestHeader = otelchitrace.DefaultShadowRequestHeader
+	// SlogTraceIDKey is an alias of [otelchitrace.SlogTraceIDKey].
+	SlogTraceIDKey = otelchitrace.SlogTraceIDKey
+	// SlogSpanIDKey is an alias of [otelchitrace.SlogSpanIDKey].
+	SlogSpanIDKey = otelchitrace.SlogSpanIDKey
+	// SlogRouteKey is an alias of [otelchitrace.SlogRouteKey].
+	SlogRouteKey = otelchitrace.SlogRouteKey
+	// SSEFlushEventName is an alias of [otelchitrace.SSEFlushEventName].
+	SSEFlushEventName = otelchitrace.SSEFlushEventName
+	// SSEBytesSentKey is an alias of [otelchitrace.SSEBytesSentKey].
+	SSEBytesSentKey = otelchitrace.SSEBytesSentKey
+	// SSEFlushCountKey is an alias of [otelchitrace.SSEFlushCountKey].
+	SSEFlushCountKey = otelchitrace.SSEFlushCountKey
+	// WebsocketUpgradeKey is an alias of [otelchitrace.WebsocketUpgradeKey].
+	WebsocketUpgradeKey = otelchitrace.WebsocketUpgradeKey
+	// WebsocketEndOnUpgrade is an alias of [otelchitrace.WebsocketEndOnUpgrade].
+	WebsocketEndOnUpgrade = otelchitrace.WebsocketEndOnUpgrade
+	// WebsocketEndOnClose is an alias of [otelchitrace.WebsocketEndOnClose].
+	WebsocketEndOnClose = otelchitrace.WebsocketEndOnClose
+)
+
+// WithAttributeFilter calls [otelchitrace.WithAttributeFilter].
+func WithAttributeFilter(fn func(attribute.KeyValue) bool) Option {
+	return otelchitrace.WithAttributeFilter(fn)
+}
+
+// WithAttributeValueLengthLimit calls [otelchitrace.WithAttributeValueLengthLimit].
+func WithAttributeValueLengthLimit(n int) Option {
+	return otelchitrace.WithAttributeValueLengthLimit(n)
+}
+
+// LinkFromCarrier calls [otelchitrace.LinkFromCarrier].
+func LinkFromCarrier(span oteltrace.Span, carrier propagation.TextMapCarrier, attrs ...attribute.KeyValue) bool {
+	return otelchitrace.LinkFromCarrier(span, carrier, attrs...)
+}
+
+// WithBatchLinksFromHeaders calls [otelchitrace.WithBatchLinksFromHeaders].
+func WithBatchLinksFromHeaders(headers ...string) Option {
+	return otelchitrace.WithBatchLinksFromHeaders(headers...)
+}
+
+// WithBatchLinksFromJSON calls [otelchitrace.WithBatchLinksFromJSON].
+func WithBatchLinksFromJSON(field string, maxBytes int) Option {
+	return otelchitrace.WithBatchLinksFromJSON(field, maxBytes)
+}
+
+// WithBodyCapture calls [otelchitrace.WithBodyCapture].
+func WithBodyCapture(maxBytes int, contentTypes ...string) Option {
+	return otelchitrace.WithBodyCapture(maxBytes, contentTypes...)
+}
+
+// WithBodyRedactFn calls [otelchitrace.WithBodyRedactFn].
+func WithBodyRedactFn(fn func(contentType string, body []byte) []byte) Option {
+	return otelchitrace.WithBodyRedactFn(fn)
+}
+
+// WithMaxRouteCardinality calls [otelchitrace.WithMaxRouteCardinality].
+func WithMaxRouteCardinality(n int, fallback string) Option {
+	return otelchitrace.WithMaxRouteCardinality(n, fallback)
+}
+
+// WithClientIP calls [otelchitrace.WithClientIP].
+func WithClientIP(cfg ClientIPConfig) Option {
+	return otelchitrace.WithClientIP(cfg)
+}
+
+// WithClock calls [otelchitrace.WithClock].
+func WithClock(clock Clock) Option {
+	return otelchitrace.WithClock(clock)
+}
+
+// WithTimeNow calls [otelchitrace.WithTimeNow].
+func WithTimeNow(now func() time.Time) Option {
+	return otelchitrace.WithTimeNow(now)
+}
+
+// WithCompressionAttributes calls [otelchitrace.WithCompressionAttributes].
+func WithCompressionAttributes() Option {
+	return otelchitrace.WithCompressionAttributes()
+}
+
+// WrapCompress calls [otelchitrace.WrapCompress].
+func WrapCompress(compress func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return otelchitrace.WrapCompress(compress)
+}
+
+// WithPropagators calls [otelchitrace.WithPropagators].
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return otelchitrace.WithPropagators(propagators)
+}
+
+// WithCarrierFn calls [otelchitrace.WithCarrierFn].
+func WithCarrierFn(fn func(r *http.Request) propagation.TextMapCarrier) Option {
+	return otelchitrace.WithCarrierFn(fn)
+}
+
+// WithTracerProvider calls [otelchitrace.WithTracerProvider].
+func WithTracerProvider(provider oteltrace.TracerProvider) Option {
+	return otelchitrace.WithTracerProvider(provider)
+}
+
+// WithChiRoutes calls [otelchitrace.WithChiRoutes].
+func WithChiRoutes(routes chi.Routes) Option {
+	return otelchitrace.WithChiRoutes(routes)
+}
+
+// WithRequestMethodInSpanName calls [otelchitrace.WithRequestMethodInSpanName].
+func WithRequestMethodInSpanName(isActive bool) Option {
+	return otelchitrace.WithRequestMethodInSpanName(isActive)
+}
+
+// WithFilter calls [otelchitrace.WithFilter].
+func WithFilter(filter Filter) Option {
+	return otelchitrace.WithFilter(filter)
+}
+
+// WithRouteFilter calls [otelchitrace.WithRouteFilter].
+func WithRouteFilter(filter RouteFilter) Option {
+	return otelchitrace.WithRouteFilter(filter)
+}
+
+// WithTraceIDResponseHeader calls [otelchitrace.WithTraceIDResponseHeader].
+func WithTraceIDResponseHeader(headerKeyFunc func() string) Option {
+	return otelchitrace.WithTraceIDResponseHeader(headerKeyFunc)
+}
+
+// WithTraceResponseHeaders calls [otelchitrace.WithTraceResponseHeaders].
+func WithTraceResponseHeaders(cfg TraceHeaderConfig) Option {
+	return otelchitrace.WithTraceResponseHeaders(cfg)
+}
+
+// WithPublicEndpoint calls [otelchitrace.WithPublicEndpoint].
+func WithPublicEndpoint() Option {
+	return otelchitrace.WithPublicEndpoint()
+}
+
+// WithPublicEndpointFn calls [otelchitrace.WithPublicEndpointFn].
+func WithPublicEndpointFn(fn func(r *http.Request) bool) Option {
+	return otelchitrace.WithPublicEndpointFn(fn)
+}
+
+// WithInternalRequestFn calls [otelchitrace.WithInternalRequestFn].
+func WithInternalRequestFn(fn func(r *http.Request) bool) Option {
+	return otelchitrace.WithInternalRequestFn(fn)
+}
+
+// WithSpanKindFn calls [otelchitrace.WithSpanKindFn].
+func WithSpanKindFn(fn func(r *http.Request) oteltrace.SpanKind) Option {
+	return otelchitrace.WithSpanKindFn(fn)
+}
+
+// WithTLSClientIdentity calls [otelchitrace.WithTLSClientIdentity].
+func WithTLSClientIdentity(redactFn func(value string) string) Option {
+	return otelchitrace.WithTLSClientIdentity(redactFn)
+}
+
+// WithStaticAttributes calls [otelchitrace.WithStaticAttributes].
+func WithStaticAttributes(attrs ...attribute.KeyValue) Option {
+	return otelchitrace.WithStaticAttributes(attrs...)
+}
+
+// WithSchemaURL calls [otelchitrace.WithSchemaURL].
+func WithSchemaURL(schemaURL string) Option {
+	return otelchitrace.WithSchemaURL(schemaURL)
+}
+
+// WithScopeAttributes calls [otelchitrace.WithScopeAttributes].
+func WithScopeAttributes(attrs ...attribute.KeyValue) Option {
+	return otelchitrace.WithScopeAttributes(attrs...)
+}
+
+// WithSpanAttributesFn calls [otelchitrace.WithSpanAttributesFn].
+func WithSpanAttributesFn(fn func(r *http.Request) []attribute.KeyValue) Option {
+	return otelchitrace.WithSpanAttributesFn(fn)
+}
+
+// WithSpanStartOptionsFn calls [otelchitrace.WithSpanStartOptionsFn].
+func WithSpanStartOptionsFn(fn func(r *http.Request) []oteltrace.SpanStartOption) Option {
+	return otelchitrace.WithSpanStartOptionsFn(fn)
+}
+
+// WithSpanStatusFn calls [otelchitrace.WithSpanStatusFn].
+func WithSpanStatusFn(fn func(statusCode int) (codes.Code, string)) Option {
+	return otelchitrace.WithSpanStatusFn(fn)
+}
+
+// WithErrorHook calls [otelchitrace.WithErrorHook].
+func WithErrorHook(hook func(span oteltrace.Span, r *http.Request, statusCode int)) Option {
+	return otelchitrace.WithErrorHook(hook)
+}
+
+// WithDebugStats calls [otelchitrace.WithDebugStats].
+func WithDebugStats() Option {
+	return otelchitrace.WithDebugStats()
+}
+
+// DebugHandler calls [otelchitrace.DebugHandler].
+func DebugHandler() http.Handler {
+	return otelchitrace.DebugHandler()
+}
+
+// MarkDraining calls [otelchitrace.MarkDraining].
+func MarkDraining() {
+	otelchitrace.MarkDraining()
+}
+
+// UnmarkDraining calls [otelchitrace.UnmarkDraining].
+func UnmarkDraining() {
+	otelchitrace.UnmarkDraining()
+}
+
+// IsDraining calls [otelchitrace.IsDraining].
+func IsDraining() bool {
+	return otelchitrace.IsDraining()
+}
+
+// DrainStatus calls [otelchitrace.DrainStatus].
+func DrainStatus() DrainSnapshot {
+	return otelchitrace.DrainStatus()
+}
+
+// EnrichSpan calls [otelchitrace.EnrichSpan].
+func EnrichSpan(ctx context.Context, attrs ...attribute.KeyValue) {
+	otelchitrace.EnrichSpan(ctx, attrs...)
+}
+
+// WithPostRouteEnrichment calls [otelchitrace.WithPostRouteEnrichment].
+func WithPostRouteEnrichment(fn func(r *http.Request, span oteltrace.Span)) Option {
+	return otelchitrace.WithPostRouteEnrichment(fn)
+}
+
+// WithGraphQLSupport calls [otelchitrace.WithGraphQLSupport].
+func WithGraphQLSupport(path string) Option {
+	return otelchitrace.WithGraphQLSupport(path)
+}
+
+// NewHandler calls [otelchitrace.NewHandler].
+func NewHandler(h http.Handler, serverName string, opts ...Option) http.Handler {
+	return otelchitrace.NewHandler(h, serverName, opts...)
+}
+
+// WithWrappedHandlerSpan calls [otelchitrace.WithWrappedHandlerSpan].
+func WithWrappedHandlerSpan(enabled bool) Option {
+	return otelchitrace.WithWrappedHandlerSpan(enabled)
+}
+
+// WrapHandler calls [otelchitrace.WrapHandler].
+func WrapHandler(next http.Handler) http.Handler {
+	return otelchitrace.WrapHandler(next)
+}
+
+// WithCapturedRequestHeaders calls [otelchitrace.WithCapturedRequestHeaders].
+func WithCapturedRequestHeaders(headers ...string) Option {
+	return otelchitrace.WithCapturedRequestHeaders(headers...)
+}
+
+// WithCapturedResponseHeaders calls [otelchitrace.WithCapturedResponseHeaders].
+func WithCapturedResponseHeaders(headers ...string) Option {
+	return otelchitrace.WithCapturedResponseHeaders(headers...)
+}
+
+// WithHealthEndpointsFiltered calls [otelchitrace.WithHealthEndpointsFiltered].
+func WithHealthEndpointsFiltered(paths ...string) Option {
+	return otelchitrace.WithHealthEndpointsFiltered(paths...)
+}
+
+// WithIdempotencyKeyCapture calls [otelchitrace.WithIdempotencyKeyCapture].
+func WithIdempotencyKeyCapture(header string) Option {
+	return otelchitrace.WithIdempotencyKeyCapture(header)
+}
+
+// WithIdempotencyKeyUnhashed calls [otelchitrace.WithIdempotencyKeyUnhashed].
+func WithIdempotencyKeyUnhashed() Option {
+	return otelchitrace.WithIdempotencyKeyUnhashed()
+}
+
+// WithIdempotencyKeyRetryLinks calls [otelchitrace.WithIdempotencyKeyRetryLinks].
+func WithIdempotencyKeyRetryLinks(window time.Duration) Option {
+	return otelchitrace.WithIdempotencyKeyRetryLinks(window)
+}
+
+// WithLatencyForcedSampling calls [otelchitrace.WithLatencyForcedSampling].
+func WithLatencyForcedSampling(threshold time.Duration) Option {
+	return otelchitrace.WithLatencyForcedSampling(threshold)
+}
+
+// WithLifecycleEvents calls [otelchitrace.WithLifecycleEvents].
+func WithLifecycleEvents() Option {
+	return otelchitrace.WithLifecycleEvents()
+}
+
+// WithLowAllocationMode calls [otelchitrace.WithLowAllocationMode].
+func WithLowAllocationMode() Option {
+	return otelchitrace.WithLowAllocationMode()
+}
+
+// WithMethodOverride calls [otelchitrace.WithMethodOverride].
+func WithMethodOverride(headers ...string) Option {
+	return otelchitrace.WithMethodOverride(headers...)
+}
+
+// Middleware calls [otelchitrace.Middleware].
+func Middleware(serverName string, opts ...Option) func(next http.Handler) http.Handler {
+	return otelchitrace.Middleware(serverName, opts...)
+}
+
+// MiddlewareWithConfig calls [otelchitrace.MiddlewareWithConfig].
+func MiddlewareWithConfig(cfg Config, opts ...Option) (func(next http.Handler) http.Handler, error) {
+	return otelchitrace.MiddlewareWithConfig(cfg, opts...)
+}
+
+// WithMiddlewareSpans calls [otelchitrace.WithMiddlewareSpans].
+func WithMiddlewareSpans() Option {
+	return otelchitrace.WithMiddlewareSpans()
+}
+
+// WrapMiddleware calls [otelchitrace.WrapMiddleware].
+func WrapMiddleware(name string, mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return otelchitrace.WrapMiddleware(name, mw)
+}
+
+// WithMountPrefix calls [otelchitrace.WithMountPrefix].
+func WithMountPrefix(prefix string) Option {
+	return otelchitrace.WithMountPrefix(prefix)
+}
+
+// WithNetworkAttributes calls [otelchitrace.WithNetworkAttributes].
+func WithNetworkAttributes() Option {
+	return otelchitrace.WithNetworkAttributes()
+}
+
+// WithNotFoundRouteLabel calls [otelchitrace.WithNotFoundRouteLabel].
+func WithNotFoundRouteLabel(label string) Option {
+	return otelchitrace.WithNotFoundRouteLabel(label)
+}
+
+// NewTracedNotFoundHandler calls [otelchitrace.NewTracedNotFoundHandler].
+func NewTracedNotFoundHandler(h http.HandlerFunc, serverName string, opts ...Option) http.HandlerFunc {
+	return otelchitrace.NewTracedNotFoundHandler(h, serverName, opts...)
+}
+
+// NewTracedMethodNotAllowedHandler calls [otelchitrace.NewTracedMethodNotAllowedHandler].
+func NewTracedMethodNotAllowedHandler(h http.HandlerFunc, serverName string, opts ...Option) http.HandlerFunc {
+	return otelchitrace.NewTracedMethodNotAllowedHandler(h, serverName, opts...)
+}
+
+// WithOverheadMetric calls [otelchitrace.WithOverheadMetric].
+func WithOverheadMetric(provider otelmetric.MeterProvider) Option {
+	return otelchitrace.WithOverheadMetric(provider)
+}
+
+// WithMeterProvider calls [otelchitrace.WithMeterProvider].
+func WithMeterProvider(provider otelmetric.MeterProvider) Option {
+	return otelchitrace.WithMeterProvider(provider)
+}
+
+// WithPhaseTimings calls [otelchitrace.WithPhaseTimings].
+func WithPhaseTimings() Option {
+	return otelchitrace.WithPhaseTimings()
+}
+
+// WithProblemDetailsCapture calls [otelchitrace.WithProblemDetailsCapture].
+func WithProblemDetailsCapture(maxBytes int) Option {
+	return otelchitrace.WithProblemDetailsCapture(maxBytes)
+}
+
+// WithPropagatorsOrdered calls [otelchitrace.WithPropagatorsOrdered].
+func WithPropagatorsOrdered(propagators ...propagation.TextMapPropagator) Option {
+	return otelchitrace.WithPropagatorsOrdered(propagators...)
+}
+
+// WithPropagationMode calls [otelchitrace.WithPropagationMode].
+func WithPropagationMode(mode PropagationMode) Option {
+	return otelchitrace.WithPropagationMode(mode)
+}
+
+// WithTracePropagationOnly calls [otelchitrace.WithTracePropagationOnly].
+func WithTracePropagationOnly() Option {
+	return otelchitrace.WithTracePropagationOnly()
+}
+
+// WithQueryRecording calls [otelchitrace.WithQueryRecording].
+func WithQueryRecording(mode QueryRecordingMode) Option {
+	return otelchitrace.WithQueryRecording(mode)
+}
+
+// WithQueryRedactionDenylist calls [otelchitrace.WithQueryRedactionDenylist].
+func WithQueryRedactionDenylist(fragments ...string) Option {
+	return otelchitrace.WithQueryRedactionDenylist(fragments...)
+}
+
+// WithQueryRedactionAllowlist calls [otelchitrace.WithQueryRedactionAllowlist].
+func WithQueryRedactionAllowlist(names ...string) Option {
+	return otelchitrace.WithQueryRedactionAllowlist(names...)
+}
+
+// WithRequestQueueTimeMetric calls [otelchitrace.WithRequestQueueTimeMetric].
+func WithRequestQueueTimeMetric(header string) Option {
+	return otelchitrace.WithRequestQueueTimeMetric(header)
+}
+
+// WithRequestID calls [otelchitrace.WithRequestID].
+func WithRequestID(headerName string, generateIfMissing bool) Option {
+	return otelchitrace.WithRequestID(headerName, generateIfMissing)
+}
+
+// RequestIDFromContext calls [otelchitrace.RequestIDFromContext].
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	return otelchitrace.RequestIDFromContext(ctx)
+}
+
+// WithRequestLogging calls [otelchitrace.WithRequestLogging].
+func WithRequestLogging(loggerProvider log.LoggerProvider) Option {
+	return otelchitrace.WithRequestLogging(loggerProvider)
+}
+
+// WithResponseBodyStatusFn calls [otelchitrace.WithResponseBodyStatusFn].
+func WithResponseBodyStatusFn(maxBytes int, fn func(status int, body []byte) (codes.Code, string), contentTypes ...string) Option {
+	return otelchitrace.WithResponseBodyStatusFn(maxBytes, fn, contentTypes...)
+}
+
+// JSONErrorStatus calls [otelchitrace.JSONErrorStatus].
+func JSONErrorStatus(arg0 int, body []byte) (codes.Code, string) {
+	return otelchitrace.JSONErrorStatus(arg0, body)
+}
+
+// ResponseInfoFromContext calls [otelchitrace.ResponseInfoFromContext].
+func ResponseInfoFromContext(ctx context.Context) (ResponseInfo, bool) {
+	return otelchitrace.ResponseInfoFromContext(ctx)
+}
+
+// NewRouteCache calls [otelchitrace.NewRouteCache].
+func NewRouteCache(size int) *RouteCache {
+	return otelchitrace.NewRouteCache(size)
+}
+
+// WithRouteCache calls [otelchitrace.WithRouteCache].
+func WithRouteCache(cache *RouteCache) Option {
+	return otelchitrace.WithRouteCache(cache)
+}
+
+// RouteDisableTracing calls [otelchitrace.RouteDisableTracing].
+func RouteDisableTracing() RouteOption {
+	return otelchitrace.RouteDisableTracing()
+}
+
+// RouteSpanName calls [otelchitrace.RouteSpanName].
+func RouteSpanName(name string) RouteOption {
+	return otelchitrace.RouteSpanName(name)
+}
+
+// RouteAttributes calls [otelchitrace.RouteAttributes].
+func RouteAttributes(attrs ...attribute.KeyValue) RouteOption {
+	return otelchitrace.RouteAttributes(attrs...)
+}
+
+// WithRouteConfig calls [otelchitrace.WithRouteConfig].
+func WithRouteConfig(pattern string, opts ...RouteOption) Option {
+	return otelchitrace.WithRouteConfig(pattern, opts...)
+}
+
+// WithRouteAttributes calls [otelchitrace.WithRouteAttributes].
+func WithRouteAttributes(attrs map[string][]attribute.KeyValue) Option {
+	return otelchitrace.WithRouteAttributes(attrs)
+}
+
+// WithRoutePatternNormalizer calls [otelchitrace.WithRoutePatternNormalizer].
+func WithRoutePatternNormalizer(fn func(pattern string) string) Option {
+	return otelchitrace.WithRoutePatternNormalizer(fn)
+}
+
+// StripRouteRegexps calls [otelchitrace.StripRouteRegexps].
+func StripRouteRegexps(pattern string) string {
+	return otelchitrace.StripRouteRegexps(pattern)
+}
+
+// RoutePattern calls [otelchitrace.RoutePattern].
+func RoutePattern(ctx context.Context) (string, bool) {
+	return otelchitrace.RoutePattern(ctx)
+}
+
+// NewRouter calls [otelchitrace.NewRouter].
+func NewRouter(serverName string, opts ...Option) *chi.Mux {
+	return otelchitrace.NewRouter(serverName, opts...)
+}
+
+// WithRouterMiddlewares calls [otelchitrace.WithRouterMiddlewares].
+func WithRouterMiddlewares(middlewares ...func(http.Handler) http.Handler) Option {
+	return otelchitrace.WithRouterMiddlewares(middlewares...)
+}
+
+// WithSecondaryTracerProvider calls [otelchitrace.WithSecondaryTracerProvider].
+func WithSecondaryTracerProvider(provider oteltrace.TracerProvider) Option {
+	return otelchitrace.WithSecondaryTracerProvider(provider)
+}
+
+// WithSemconvVersion calls [otelchitrace.WithSemconvVersion].
+func WithSemconvVersion(mode SemconvMode) Option {
+	return otelchitrace.WithSemconvVersion(mode)
+}
+
+// NewServerName calls [otelchitrace.NewServerName].
+func NewServerName(name string) *ServerName {
+	return otelchitrace.NewServerName(name)
+}
+
+// WithDynamicServerName calls [otelchitrace.WithDynamicServerName].
+func WithDynamicServerName(name *ServerName) Option {
+	return otelchitrace.WithDynamicServerName(name)
+}
+
+// WithServerNameFn calls [otelchitrace.WithServerNameFn].
+func WithServerNameFn(fn func(r *http.Request) string) Option {
+	return otelchitrace.WithServerNameFn(fn)
+}
+
+// WithServerTimingHeader calls [otelchitrace.WithServerTimingHeader].
+func WithServerTimingHeader() Option {
+	return otelchitrace.WithServerTimingHeader()
+}
+
+// WithShadowTraffic calls [otelchitrace.WithShadowTraffic].
+func WithShadowTraffic(fn func(r *http.Request) bool) Option {
+	return otelchitrace.WithShadowTraffic(fn)
+}
+
+// SlogHandler calls [otelchitrace.SlogHandler].
+func SlogHandler(inner slog.Handler) slog.Handler {
+	return otelchitrace.SlogHandler(inner)
+}
+
+// WithSlogLogger calls [otelchitrace.WithSlogLogger].
+func WithSlogLogger(logger *slog.Logger) Option {
+	return otelchitrace.WithSlogLogger(logger)
+}
+
+// LoggerFromContext calls [otelchitrace.LoggerFromContext].
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	return otelchitrace.LoggerFromContext(ctx)
+}
+
+// WithSSEInstrumentation calls [otelchitrace.WithSSEInstrumentation].
+func WithSSEInstrumentation() Option {
+	return otelchitrace.WithSSEInstrumentation()
+}
+
+// WithDynamicTracerProvider calls [otelchitrace.WithDynamicTracerProvider].
+func WithDynamicTracerProvider() Option {
+	return otelchitrace.WithDynamicTracerProvider()
+}
+
+// WithTracerName calls [otelchitrace.WithTracerName].
+func WithTracerName(name string) Option {
+	return otelchitrace.WithTracerName(name)
+}
+
+// WithTracer calls [otelchitrace.WithTracer].
+func WithTracer(tracer oteltrace.Tracer) Option {
+	return otelchitrace.WithTracer(tracer)
+}
+
+// WithCapturedResponseTrailers calls [otelchitrace.WithCapturedResponseTrailers].
+func WithCapturedResponseTrailers(keys ...string) Option {
+	return otelchitrace.WithCapturedResponseTrailers(keys...)
+}
+
+// WithURLParamsAsAttributes calls [otelchitrace.WithURLParamsAsAttributes].
+func WithURLParamsAsAttributes(allowlist ...string) Option {
+	return otelchitrace.WithURLParamsAsAttributes(allowlist...)
+}
+
+// Version calls [otelchitrace.Version].
+func Version() string {
+	return otelchitrace.Version()
+}
+
+// SemVersion calls [otelchitrace.SemVersion].
+func SemVersion() string {
+	return otelchitrace.SemVersion()
+}
+
+// WithWebsocketSpanMode calls [otelchitrace.WithWebsocketSpanMode].
+func WithWebsocketSpanMode(mode WebsocketSpanMode) Option {
+	return otelchitrace.WithWebsocketSpanMode(mode)
+}
@@ -0,0 +1,56 @@
+// Package otelchicore provides the building blocks shared by the tracing
+// middleware (see package otelchitrace) & the metric recorders (see package
+// otelchimetric): the recording response writer, the route resolution & the
+// config types accepted by both of them.
+//
+// The package neither depends on the OpenTelemetry trace nor metric API, so
+// the tracing & the metric instrumentations could share it without depending
+// on each other.
+package otelchicore
+
+import (
+	"time"
+
+	"github.com/riandyrn/otelchi/internal/semconvutil"
+	"github.com/riandyrn/otelchi/internal/servername"
+)
+
+// Clock is the time source used by the instrumentations.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// ClockFunc adapts the function returning the current time into Clock.
+type ClockFunc func() time.Time
+
+// Now returns the current time returned by f.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SemconvMode determines which version of the HTTP semantic conventions is
+// emitted. The default mode is read from `OTEL_SEMCONV_STABILITY_OPT_IN`
+// environment variable (`http` for the stable conventions, `http/dup` for
+// both), falling back to the old (v1.20.0) conventions.
+type SemconvMode = semconvutil.Mode
+
+const (
+	// SemconvOld emits the old (v1.20.0) HTTP semantic conventions.
+	SemconvOld = semconvutil.ModeOld
+	// SemconvNew emits the stable (v1.26.0) HTTP semantic conventions.
+	SemconvNew = semconvutil.ModeNew
+	// SemconvDual emits both the old & the stable HTTP semantic conventions.
+	SemconvDual = semconvutil.ModeDual
+)
+
+// ServerName holds the server name which could be updated at runtime, it is
+// safe for concurrent use. The same holder could be shared by the tracing
+// middleware & the metric recorders.
+type ServerName = servername.Name
+
+// NewServerName returns a new server name holder initialized with the given
+// name. Use `Set` to update the name at runtime.
+func NewServerName(name string) *ServerName {
+	return servername.New(name)
+}
@@ -0,0 +1,259 @@
+package otelchicore
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/felixge/httpsnoop"
+)
+
+// ResponseHooks are the optional hooks called by `ResponseWriter` while the
+// response is written.
+type ResponseHooks struct {
+	// OnHeader is called once right before the response header is written.
+	OnHeader func()
+	// OnFirstByte is called once the first byte of the response is written.
+	OnFirstByte func()
+	// OnWrite is called with the bytes written into the response body.
+	OnWrite func(b []byte)
+	// OnFlush is called after the response is flushed.
+	OnFlush func()
+	// OnHijack is called after the connection is hijacked, the returned
+	// connection is handed to the handler instead.
+	OnHijack func(conn net.Conn) net.Conn
+	// Observer is notified after the status code or the number of bytes
+	// written is recorded.
+	Observer ResponseObserver
+}
+
+// ResponseObserver observes the response recorded by `ResponseWriter`.
+type ResponseObserver interface {
+	// ObserveResponse is called after the status code or the number of bytes
+	// written is recorded by the given writer.
+	ObserveResponse(rw *ResponseWriter)
+}
+
+// ResponseWriter records the status code & the number of bytes written into
+// the response. The wrapped writer returned by `Writer` preserves the
+// optional interfaces implemented by the original writer (http.Flusher,
+// http.Hijacker, http.Pusher, io.ReaderFrom) and exposes `Unwrap` method so
+// it could be used with http.ResponseController.
+//
+// The writers are pooled, so the one obtained through
+// `AcquireResponseWriter` must not be used after it is released, nor be
+// retained by the request context.
+type ResponseWriter struct {
+	// Hooks are the hooks called while the response is written, they are
+	// reset when the writer is released.
+	Hooks ResponseHooks
+	// WriteClock measures the time spent in the wrapped writer, the time is
+	// only measured when it is set.
+	WriteClock func() time.Time
+
+	writer        http.ResponseWriter
+	written       bool
+	status        int
+	writtenBytes  int64
+	writeDuration time.Duration
+	// hooks are the httpsnoop hooks wrapping the writer
+	hooks httpsnoop.Hooks
+}
+
+var responseWriterPool = &sync.Pool{
+	New: func() interface{} {
+		rw := &ResponseWriter{}
+		// the hooks only refer to the pooled writer, so they are created
+		// once instead of on every request
+		rw.hooks = rw.newHooks()
+		return rw
+	},
+}
+
+// AcquireResponseWriter returns the recording writer wrapping the given
+// writer from the pool, call `Release` once the request is completed.
+func AcquireResponseWriter(w http.ResponseWriter) *ResponseWriter {
+	rw := responseWriterPool.Get().(*ResponseWriter)
+	rw.written = false
+	rw.status = http.StatusOK
+	rw.writtenBytes = 0
+	rw.writeDuration = 0
+	rw.writer = httpsnoop.Wrap(w, rw.hooks)
+	return rw
+}
+
+// Release returns the writer into the pool.
+func (rw *ResponseWriter) Release() {
+	rw.writer = nil
+	rw.Hooks = ResponseHooks{}
+	rw.WriteClock = nil
+	responseWriterPool.Put(rw)
+}
+
+// Writer returns the wrapped writer which should be passed to the handler.
+func (rw *ResponseWriter) Writer() http.ResponseWriter {
+	return rw.writer
+}
+
+// Status returns the status code of the response, it is `http.StatusOK`
+// when the header has not been written explicitly.
+func (rw *ResponseWriter) Status() int {
+	return rw.status
+}
+
+// BytesWritten returns the number of bytes written into the response body.
+func (rw *ResponseWriter) BytesWritten() int64 {
+	return rw.writtenBytes
+}
+
+// Written returns true when either the header or the body of the response
+// has been written.
+func (rw *ResponseWriter) Written() bool {
+	return rw.written
+}
+
+// WriteDuration returns the time spent in the wrapped writer measured by
+// WriteClock.
+func (rw *ResponseWriter) WriteDuration() time.Duration {
+	return rw.writeDuration
+}
+
+// Complete should be called once the handler returns, it calls OnHeader
+// when the handler hasn't written the response since net/http writes the
+// response header after the handler returns.
+func (rw *ResponseWriter) Complete() {
+	rw.headerWritten()
+}
+
+// newHooks returns the httpsnoop hooks recording the response into rw.
+func (rw *ResponseWriter) newHooks() httpsnoop.Hooks {
+	return httpsnoop.Hooks{
+		Write: func(next httpsnoop.WriteFunc) httpsnoop.WriteFunc {
+			return func(b []byte) (int, error) {
+				if rw.WriteClock != nil {
+					defer rw.timeWrite(rw.WriteClock())
+				}
+				if !rw.written {
+					rw.written = true
+					rw.headerWritten()
+				}
+				n, err := next(b)
+				rw.writtenBytes += int64(n)
+				rw.observe()
+				if n > 0 {
+					rw.firstByteWritten()
+				}
+				if rw.Hooks.OnWrite != nil && n > 0 {
+					rw.Hooks.OnWrite(b[:n])
+				}
+				return n, err
+			}
+		},
+		Flush: func(next httpsnoop.FlushFunc) httpsnoop.FlushFunc {
+			return func() {
+				if rw.WriteClock != nil {
+					defer rw.timeWrite(rw.WriteClock())
+				}
+				// flushing implicitly writes the header with the default status
+				if !rw.written {
+					rw.written = true
+					rw.headerWritten()
+				}
+				rw.observe()
+				next()
+				rw.firstByteWritten()
+				if rw.Hooks.OnFlush != nil {
+					rw.Hooks.OnFlush()
+				}
+			}
+		},
+		ReadFrom: func(next httpsnoop.ReadFromFunc) httpsnoop.ReadFromFunc {
+			return func(src io.Reader) (int64, error) {
+				if rw.WriteClock != nil {
+					defer rw.timeWrite(rw.WriteClock())
+				}
+				if !rw.written {
+					rw.written = true
+					rw.headerWritten()
+				}
+				if rw.Hooks.OnWrite != nil {
+					src = io.TeeReader(src, writeHook(rw.Hooks.OnWrite))
+				}
+				n, err := next(src)
+				rw.writtenBytes += n
+				rw.observe()
+				if n > 0 {
+					rw.firstByteWritten()
+				}
+				return n, err
+			}
+		},
+		Hijack: func(next httpsnoop.HijackFunc) httpsnoop.HijackFunc {
+			return func() (net.Conn, *bufio.ReadWriter, error) {
+				conn, brw, err := next()
+				if err == nil && rw.Hooks.OnHijack != nil {
+					conn = rw.Hooks.OnHijack(conn)
+				}
+				return conn, brw, err
+			}
+		},
+		WriteHeader: func(next httpsnoop.WriteHeaderFunc) httpsnoop.WriteHeaderFunc {
+			return func(statusCode int) {
+				if rw.WriteClock != nil {
+					defer rw.timeWrite(rw.WriteClock())
+				}
+				if !rw.written {
+					rw.written = true
+					rw.headerWritten()
+					rw.status = statusCode
+					rw.observe()
+				}
+				next(statusCode)
+			}
+		},
+	}
+}
+
+// timeWrite adds the time elapsed since the given start of the write into
+// the time spent in the writer.
+func (rw *ResponseWriter) timeWrite(start time.Time) {
+	rw.writeDuration += rw.WriteClock().Sub(start)
+}
+
+// observe notifies the observer if any.
+func (rw *ResponseWriter) observe() {
+	if rw.Hooks.Observer != nil {
+		rw.Hooks.Observer.ObserveResponse(rw)
+	}
+}
+
+// headerWritten calls OnHeader before the response header is written.
+func (rw *ResponseWriter) headerWritten() {
+	if rw.Hooks.OnHeader == nil {
+		return
+	}
+	onHeader := rw.Hooks.OnHeader
+	rw.Hooks.OnHeader = nil
+	onHeader()
+}
+
+// firstByteWritten calls OnFirstByte on the first byte of the response.
+func (rw *ResponseWriter) firstByteWritten() {
+	if rw.Hooks.OnFirstByte == nil {
+		return
+	}
+	onFirstByte := rw.Hooks.OnFirstByte
+	rw.Hooks.OnFirstByte = nil
+	onFirstByte()
+}
+
+// writeHook adapts the write hook into io.Writer.
+type writeHook func(b []byte)
+
+func (h writeHook) Write(b []byte) (int, error) {
+	h(b)
+	return len(b), nil
+}
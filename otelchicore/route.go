@@ -0,0 +1,146 @@
+package otelchicore
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/internal/cardinality"
+	"github.com/riandyrn/otelchi/internal/routecache"
+	"github.com/riandyrn/otelchi/internal/routenorm"
+)
+
+// RouteCache caches the route patterns resolved from the routes, keyed by the
+// request method & path. It is safe for concurrent use.
+//
+// The same cache could be shared by the tracing middleware & the metric
+// recorders as long as they use the same routes. Call `Invalidate` when the
+// routes are changed after the requests are served.
+type RouteCache = routecache.Cache
+
+// DefaultRouteCacheSize is the recommended maximum number of entries of the
+// route cache.
+const DefaultRouteCacheSize = 1024
+
+// NewRouteCache returns a new route cache holding at most size entries, the
+// cache is purged entirely once it is full so the memory stays bounded when
+// the paths contain identifiers (e.g. `/users/123`).
+func NewRouteCache(size int) *RouteCache {
+	return routecache.New(size)
+}
+
+// RouteLimiter limits the number of distinct routes, the routes seen after
+// the limit is reached are collapsed into the fallback route. It is safe for
+// concurrent use.
+type RouteLimiter = cardinality.Limiter
+
+// DefaultRouteCardinalityFallback is the route used in place of the routes
+// exceeding the limit when no fallback is specified.
+const DefaultRouteCardinalityFallback = cardinality.DefaultFallback
+
+// NewRouteLimiter returns a new limiter allowing at most n distinct routes,
+// the routes exceeding the limit are collapsed into the fallback. If the
+// fallback is empty, `_other_` is used.
+func NewRouteLimiter(n int, fallback string) *RouteLimiter {
+	return cardinality.New(n, fallback)
+}
+
+// StripRouteRegexps returns the given chi route pattern without the regular
+// expression constraints of its parameters, e.g. `/users/{id:[0-9]+}` becomes
+// `/users/{id}`. It could be used as `RouteConfig.Normalize`.
+func StripRouteRegexps(pattern string) string {
+	return routenorm.StripRegexps(pattern)
+}
+
+// RouteConfig is the config of the route resolution, every field is
+// optional.
+type RouteConfig struct {
+	// Routes are the routes used for resolving the route pattern before the
+	// request is routed by chi.
+	Routes chi.Routes
+	// Cache caches the route patterns resolved from Routes.
+	Cache *RouteCache
+	// MountPrefix is the prefix added to the route pattern, it is useful when
+	// the router is mounted under a path which is not visible to chi, e.g.
+	// through `http.StripPrefix`.
+	MountPrefix string
+	// NotFoundLabel is the synthetic route pattern of the requests matching
+	// no route.
+	NotFoundLabel string
+	// Normalize normalizes the route pattern recorded as `http.route`, e.g.
+	// `StripRouteRegexps`.
+	Normalize func(pattern string) string
+	// Limiter limits the number of distinct `http.route` values.
+	Limiter *RouteLimiter
+}
+
+// RouteResolver resolves the route pattern of the requests & derives the
+// `http.route` value from it, so every instrumentation sharing the same
+// config records the same route. It is safe for concurrent use.
+type RouteResolver struct {
+	cfg RouteConfig
+}
+
+// NewRouteResolver returns a new route resolver using the given config.
+func NewRouteResolver(cfg RouteConfig) *RouteResolver {
+	return &RouteResolver{cfg: cfg}
+}
+
+// Config returns the config of the resolver.
+func (rr *RouteResolver) Config() RouteConfig {
+	return rr.cfg
+}
+
+// Match returns the route pattern matched by the given request in
+// `RouteConfig.Routes`, prefixed by the mount prefix. It could be called
+// before the request is routed by chi, it returns empty string when the
+// routes are not specified or no route matches the request.
+func (rr *RouteResolver) Match(r *http.Request) string {
+	if rr.cfg.Routes == nil {
+		return ""
+	}
+	pattern, _ := rr.cfg.Cache.ResolveRequest(rr.cfg.Routes, r)
+	return rr.mounted(pattern)
+}
+
+// Routed returns the route pattern of the given chi route context prefixed
+// by the mount prefix, the not found label is returned when chi has routed
+// the request without matching any route. It returns false when the request
+// is not routed by chi yet.
+func (rr *RouteResolver) Routed(rctx *chi.Context) (string, bool) {
+	if rctx == nil {
+		return "", false
+	}
+	// the route method is only set once chi starts routing, so the empty
+	// pattern means no route matches the request
+	pattern := rctx.RoutePattern()
+	if len(pattern) == 0 && (len(rctx.RouteMethod) == 0 || len(rr.cfg.NotFoundLabel) == 0) {
+		return "", false
+	}
+	return rr.Pattern(pattern), true
+}
+
+// Pattern returns the given route pattern resolved by chi prefixed by the
+// mount prefix, the not found label is returned when the pattern is empty.
+func (rr *RouteResolver) Pattern(chiPattern string) string {
+	if len(chiPattern) == 0 {
+		return rr.cfg.NotFoundLabel
+	}
+	return rr.mounted(chiPattern)
+}
+
+// HTTPRoute returns the `http.route` value of the given route pattern (e.g.
+// the one returned by `Pattern`), it is normalized & limited.
+func (rr *RouteResolver) HTTPRoute(pattern string) string {
+	if rr.cfg.Normalize != nil && len(pattern) > 0 {
+		pattern = rr.cfg.Normalize(pattern)
+	}
+	return rr.cfg.Limiter.Limit(pattern)
+}
+
+// mounted returns the given pattern prefixed by the mount prefix.
+func (rr *RouteResolver) mounted(pattern string) string {
+	if len(pattern) == 0 {
+		return pattern
+	}
+	return rr.cfg.MountPrefix + pattern
+}
@@ -0,0 +1,53 @@
+package otelchimetric
+
+import (
+	"fmt"
+	"net/http"
+
+	otelmetric "go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// NewActiveRequests is a metrics recorder for recording the number of requests
+// currently being processed as `http.server.active_requests` metric conforming
+// to the HTTP semantic conventions.
+func NewActiveRequests(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	// init metric, here we are using up down counter for capturing active requests
+	counter, err := cfg.resolveMeter().Int64UpDownCounter(
+		cfg.metricName(semconv.HTTPServerActiveRequestsName),
+		otelmetric.WithDescription(semconv.HTTPServerActiveRequestsDescription),
+		otelmetric.WithUnit(semconv.HTTPServerActiveRequestsUnit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s counter: %w", semconv.HTTPServerActiveRequestsName, err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request
+			if cfg.skipRecording(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// define metric attributes, route & status code are not known
+			// yet when the request starts
+			attrs := cfg.withAttributes(cfg.stableRequestAttributes(r, "", 0))
+
+			// increase the number of active requests
+			counter.Add(r.Context(), 1, attrs)
+
+			// execute next http handler
+			next.ServeHTTP(w, r)
+
+			// decrease the number of active requests
+			counter.Add(r.Context(), -1, attrs)
+		})
+	}, nil
+}
+
+// MustNewActiveRequests is like [NewActiveRequests] but panics when the metric instrument cannot
+// be created.
+func MustNewActiveRequests(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return must(NewActiveRequests(cfg))
+}
@@ -0,0 +1,136 @@
+package otelchimetric
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/felixge/httpsnoop"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// NewActiveRequestsByRoute is a metrics recorder for recording the number of
+// requests currently being processed as `http.server.active_requests` metric
+// like [NewActiveRequests], but the metric is keyed by the `http.route`
+// attribute as well. It should be used instead of [NewActiveRequests], not
+// along with it.
+//
+// The request is counted as soon as its route is known: when the request
+// starts if the route could be resolved from the routes specified through
+// [WithChiRoutes], otherwise once the handler starts writing the response.
+// The request completed without writing the response is not counted.
+func NewActiveRequestsByRoute(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	// init metric, here we are using up down counter for capturing active requests
+	counter, err := cfg.resolveMeter().Int64UpDownCounter(
+		cfg.metricName(semconv.HTTPServerActiveRequestsName),
+		otelmetric.WithDescription(semconv.HTTPServerActiveRequestsDescription),
+		otelmetric.WithUnit(semconv.HTTPServerActiveRequestsUnit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s counter: %w", semconv.HTTPServerActiveRequestsName, err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request
+			if cfg.skipRecording(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			req := &activeRouteRequest{cfg: cfg, counter: counter, r: r}
+
+			// count the request right away when the route is already known,
+			// otherwise defer it until the handler writes the response
+			if len(cfg.routePattern(r)) > 0 {
+				req.add()
+			} else {
+				w = req.wrap(w)
+			}
+
+			// execute next http handler
+			next.ServeHTTP(w, r)
+
+			// decrease the number of active requests
+			req.done()
+		})
+	}, nil
+}
+
+// MustNewActiveRequestsByRoute is like [NewActiveRequestsByRoute] but panics when the metric instrument cannot
+// be created.
+func MustNewActiveRequestsByRoute(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return must(NewActiveRequestsByRoute(cfg))
+}
+
+// activeRouteRequest tracks the active request counted once its route is
+// known.
+type activeRouteRequest struct {
+	cfg     BaseConfig
+	counter otelmetric.Int64UpDownCounter
+	r       *http.Request
+
+	once  sync.Once
+	added bool
+	attrs otelmetric.AddOption
+}
+
+// add increases the number of active requests, it only takes effect once.
+func (a *activeRouteRequest) add() {
+	a.once.Do(func() {
+		route := a.cfg.handledRoutePattern(a.r)
+		a.attrs = a.cfg.withAttributes(a.cfg.stableRequestAttributes(a.r, route, 0))
+		a.counter.Add(a.r.Context(), 1, a.attrs)
+		a.added = true
+	})
+}
+
+// done decreases the number of active requests if the request is counted.
+func (a *activeRouteRequest) done() {
+	// prevent the request from being counted after it is completed
+	a.once.Do(func() {})
+	if a.added {
+		a.counter.Add(a.r.Context(), -1, a.attrs)
+	}
+}
+
+// wrap returns the writer counting the request on the first response write,
+// the route is resolved by chi router by then.
+func (a *activeRouteRequest) wrap(w http.ResponseWriter) http.ResponseWriter {
+	return httpsnoop.Wrap(w, httpsnoop.Hooks{
+		Write: func(next httpsnoop.WriteFunc) httpsnoop.WriteFunc {
+			return func(b []byte) (int, error) {
+				a.add()
+				return next(b)
+			}
+		},
+		WriteHeader: func(next httpsnoop.WriteHeaderFunc) httpsnoop.WriteHeaderFunc {
+			return func(statusCode int) {
+				a.add()
+				next(statusCode)
+			}
+		},
+		Flush: func(next httpsnoop.FlushFunc) httpsnoop.FlushFunc {
+			return func() {
+				a.add()
+				next()
+			}
+		},
+		ReadFrom: func(next httpsnoop.ReadFromFunc) httpsnoop.ReadFromFunc {
+			return func(src io.Reader) (int64, error) {
+				a.add()
+				return next(src)
+			}
+		},
+		Hijack: func(next httpsnoop.HijackFunc) httpsnoop.HijackFunc {
+			return func() (net.Conn, *bufio.ReadWriter, error) {
+				a.add()
+				return next()
+			}
+		},
+	})
+}
@@ -0,0 +1,68 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestActiveRequestsByRoute(t *testing.T) {
+	for _, withChiRoutes := range []bool{false, true} {
+		// setup environment
+		reader := sdkmetric.NewManualReader()
+		provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+		router := chi.NewRouter()
+		opts := []otelchimetric.Option{otelchimetric.WithMeterProvider(provider)}
+		if withChiRoutes {
+			opts = append(opts, otelchimetric.WithChiRoutes(router))
+		}
+		baseCfg := otelchimetric.NewBaseConfig("test-server", opts...)
+		router.Use(otelchimetric.MustNewActiveRequestsByRoute(baseCfg))
+		router.Get("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+			// the request is counted before the response is written only
+			// when the route could be resolved beforehand
+			if withChiRoutes {
+				assert.Equal(t, map[string]int64{"/user/{id}": 1}, getActiveRequestsByRoute(t, reader))
+			} else {
+				assert.Empty(t, getActiveRequestsByRoute(t, reader))
+			}
+
+			w.WriteHeader(http.StatusOK)
+			assert.Equal(t, map[string]int64{"/user/{id}": 1}, getActiveRequestsByRoute(t, reader))
+		})
+
+		// execute request
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user/123", nil))
+
+		// the request is no longer active
+		assert.Equal(t, map[string]int64{"/user/{id}": 0}, getActiveRequestsByRoute(t, reader))
+	}
+}
+
+func getActiveRequestsByRoute(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+			for _, dp := range sum.DataPoints {
+				route, _ := dp.Attributes.Value("http.route")
+				counts[route.AsString()] += dp.Value
+			}
+		}
+	}
+	return counts
+}
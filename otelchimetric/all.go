@@ -0,0 +1,52 @@
+package otelchimetric
+
+import (
+	"net/http"
+)
+
+// NewAllMiddlewares returns all metrics recorders emitting the HTTP server
+// metrics defined by the HTTP semantic conventions:
+//
+//   - `http.server.active_requests` (see [NewActiveRequests])
+//   - `http.server.request.duration` (see [NewRequestDurationSeconds])
+//   - `http.server.request.body.size` (see [NewRequestBodySize])
+//   - `http.server.response.body.size` (see [NewResponseBodySize])
+//
+// The returned middlewares could be installed in a single call, e.g:
+//
+//	recorders, err := otelchimetric.NewAllMiddlewares(baseCfg)
+//	if err != nil {
+//		return fmt.Errorf("unable to create metric recorders due: %w", err)
+//	}
+//	r.Use(recorders...)
+func NewAllMiddlewares(cfg BaseConfig) ([]func(next http.Handler) http.Handler, error) {
+	constructors := []func(cfg BaseConfig) (func(next http.Handler) http.Handler, error){
+		NewActiveRequests,
+		func(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+			return NewRequestDurationSeconds(cfg)
+		},
+		NewRequestBodySize,
+		NewResponseBodySize,
+	}
+	recorders := make([]func(next http.Handler) http.Handler, 0, len(constructors))
+	for _, constructor := range constructors {
+		recorder, err := constructor(cfg)
+		if err != nil {
+			return nil, err
+		}
+		recorders = append(recorders, recorder)
+	}
+	return recorders, nil
+}
+
+// MustNewAllMiddlewares is like [NewAllMiddlewares] but panics when any of the
+// metric instruments cannot be created, e.g:
+//
+//	r.Use(otelchimetric.MustNewAllMiddlewares(baseCfg)...)
+func MustNewAllMiddlewares(cfg BaseConfig) []func(next http.Handler) http.Handler {
+	recorders, err := NewAllMiddlewares(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return recorders
+}
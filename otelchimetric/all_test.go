@@ -0,0 +1,68 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestNewAllMiddlewares(t *testing.T) {
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeterProvider(provider))
+
+	router := chi.NewRouter()
+	router.Use(otelchimetric.MustNewAllMiddlewares(baseCfg)...)
+	router.Post("/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+		_, _ = w.Write([]byte("!"))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("hello"))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	metrics := map[string]metricdata.Metrics{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+	require.Len(t, metrics, 4)
+
+	activeRequests, ok := metrics["http.server.active_requests"].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, activeRequests.DataPoints, 1)
+	assert.Equal(t, int64(0), activeRequests.DataPoints[0].Value)
+
+	duration, ok := metrics["http.server.request.duration"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, duration.DataPoints, 1)
+	assert.Equal(t, uint64(1), duration.DataPoints[0].Count)
+
+	requestSize, ok := metrics["http.server.request.body.size"].Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, requestSize.DataPoints, 1)
+	assert.Equal(t, int64(5), requestSize.DataPoints[0].Sum)
+
+	responseSize, ok := metrics["http.server.response.body.size"].Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, responseSize.DataPoints, 1)
+	assert.Equal(t, int64(6), responseSize.DataPoints[0].Sum)
+}
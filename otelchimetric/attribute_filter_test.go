@@ -0,0 +1,55 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestAttributeFilter(t *testing.T) {
+	// setup environment, the filter drops the route & the custom attribute
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig(
+		"test-server",
+		otelchimetric.WithMeterProvider(provider),
+		otelchimetric.WithAttributes(attribute.String("tenant.id", "acme")),
+		otelchimetric.WithAttributeFilter(func(kv attribute.KeyValue) bool {
+			return kv.Key != "http.route" && kv.Key != "tenant.id"
+		}),
+	)
+	router := chi.NewRouter()
+	router.Use(otelchimetric.MustNewRequestDurationSeconds(baseCfg))
+	router.Get("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute request
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user/123", nil))
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	hist, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+
+	// ensure the filtered attributes are dropped
+	attrs := hist.DataPoints[0].Attributes
+	assert.False(t, attrs.HasValue("http.route"))
+	assert.False(t, attrs.HasValue("tenant.id"))
+	assert.True(t, attrs.HasValue("http.request.method"))
+}
@@ -0,0 +1,142 @@
+package otelchimetric
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/riandyrn/otelchi/otelchicore"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// NewRequestBodySize is a metrics recorder for recording the size of the
+// request body as `http.server.request.body.size` metric conforming to the
+// HTTP semantic conventions. The size is taken from `Content-Length` header
+// when it is known, otherwise the number of bytes read by the handler is used.
+func NewRequestBodySize(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	// init metric, here we are using histogram for capturing request body size
+	histogram, err := cfg.resolveMeter().Int64Histogram(
+		cfg.metricName(semconv.HTTPServerRequestBodySizeName),
+		otelmetric.WithDescription(semconv.HTTPServerRequestBodySizeDescription),
+		otelmetric.WithUnit(semconv.HTTPServerRequestBodySizeUnit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s histogram: %w", semconv.HTTPServerRequestBodySizeName, err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request
+			if cfg.skipRecording(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// count the bytes read from the request body
+			body := wrapBody(r)
+
+			// get recording response writer
+			rrw := otelchicore.AcquireResponseWriter(w)
+			defer rrw.Release()
+
+			// execute next http handler
+			next.ServeHTTP(rrw.Writer(), r)
+
+			// record the request body size
+			histogram.Record(
+				r.Context(),
+				requestBodySize(r, body),
+				cfg.withAttributes(cfg.stableRequestAttributes(r, cfg.handledRoutePattern(r), rrw.Status())),
+			)
+		})
+	}, nil
+}
+
+// MustNewRequestBodySize is like [NewRequestBodySize] but panics when the metric instrument cannot
+// be created.
+func MustNewRequestBodySize(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return must(NewRequestBodySize(cfg))
+}
+
+// NewResponseBodySize is a metrics recorder for recording the size of the
+// response body as `http.server.response.body.size` metric conforming to the
+// HTTP semantic conventions.
+func NewResponseBodySize(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	// init metric, here we are using histogram for capturing response body size
+	histogram, err := cfg.resolveMeter().Int64Histogram(
+		cfg.metricName(semconv.HTTPServerResponseBodySizeName),
+		otelmetric.WithDescription(semconv.HTTPServerResponseBodySizeDescription),
+		otelmetric.WithUnit(semconv.HTTPServerResponseBodySizeUnit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s histogram: %w", semconv.HTTPServerResponseBodySizeName, err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request
+			if cfg.skipRecording(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// get recording response writer
+			rrw := otelchicore.AcquireResponseWriter(w)
+			defer rrw.Release()
+
+			// execute next http handler
+			next.ServeHTTP(rrw.Writer(), r)
+
+			// record the response body size
+			histogram.Record(
+				r.Context(),
+				rrw.BytesWritten(),
+				cfg.withAttributes(cfg.stableRequestAttributes(r, cfg.handledRoutePattern(r), rrw.Status())),
+			)
+		})
+	}, nil
+}
+
+// MustNewResponseBodySize is like [NewResponseBodySize] but panics when the metric instrument cannot
+// be created.
+func MustNewResponseBodySize(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return must(NewResponseBodySize(cfg))
+}
+
+// countingBody is a wrapper around request body that counts the number of
+// bytes read from it.
+type countingBody struct {
+	io.ReadCloser
+	readBytes int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.readBytes += int64(n)
+	return n, err
+}
+
+// wrapBody replaces the body of the given request with the counting one, it
+// returns nil when the request has no body.
+func wrapBody(r *http.Request) *countingBody {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	body := &countingBody{ReadCloser: r.Body}
+	r.Body = body
+	return body
+}
+
+// requestBodySize returns the size of the request body, it is taken from
+// `Content-Length` header when it is known, otherwise the number of bytes
+// read from the body is used.
+func requestBodySize(r *http.Request, body *countingBody) int64 {
+	if r.ContentLength >= 0 {
+		return r.ContentLength
+	}
+	if body == nil {
+		return 0
+	}
+	return body.readBytes
+}
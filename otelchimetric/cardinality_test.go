@@ -0,0 +1,54 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestBaseConfigWithMaxRouteCardinality(t *testing.T) {
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig(
+		"test-server",
+		otelchimetric.WithMeterProvider(provider),
+		otelchimetric.WithMaxRouteCardinality(1, "other"),
+	)
+
+	router := chi.NewRouter()
+	router.Use(otelchimetric.MustNewRequestCounter(baseCfg))
+	router.Get("/user/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	router.Get("/book/{title}", func(w http.ResponseWriter, r *http.Request) {})
+
+	for _, path := range []string{"/user/1", "/book/foo", "/user/2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	sum, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+
+	counts := map[string]int64{}
+	for _, dp := range sum.DataPoints {
+		route, ok := dp.Attributes.Value(attribute.Key("http.route"))
+		require.True(t, ok)
+		counts[route.AsString()] += dp.Value
+	}
+	assert.Equal(t, map[string]int64{"/user/{id}": 2, "other": 1}, counts)
+}
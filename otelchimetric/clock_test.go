@@ -0,0 +1,101 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// fakeClock is a clock which only moves when it is advanced explicitly.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestRequestDurationMillisWithClock(t *testing.T) {
+	// setup environment
+	clock := &fakeClock{now: time.Now()}
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeterProvider(provider), otelchimetric.WithClock(clock))
+	middleware := otelchimetric.MustNewRequestDurationMillis(baseCfg)
+
+	router := chi.NewRouter()
+	router.Use(middleware)
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		// simulate slow request without real sleep
+		clock.Advance(1500 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	hist, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+	assert.Equal(t, int64(1500), hist.DataPoints[0].Sum)
+}
+
+func TestRequestDurationMillisWithTimeNow(t *testing.T) {
+	// setup environment, the timestamps are replayed from the recorded ones
+	timestamps := []time.Time{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 0, 0, 0, int(250*time.Millisecond), time.UTC),
+	}
+	var i int
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeterProvider(provider), otelchimetric.WithTimeNow(func() time.Time {
+		now := timestamps[min(i, len(timestamps)-1)]
+		i++
+		return now
+	}))
+	router := chi.NewRouter()
+	router.Use(otelchimetric.MustNewRequestDurationMillis(baseCfg))
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	hist, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+	assert.Equal(t, int64(250), hist.DataPoints[0].Sum)
+}
@@ -0,0 +1,516 @@
+package otelchimetric
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/internal/attrfilter"
+	"github.com/riandyrn/otelchi/internal/drain"
+	"github.com/riandyrn/otelchi/internal/health"
+	"github.com/riandyrn/otelchi/internal/routeattr"
+	"github.com/riandyrn/otelchi/internal/semconvutil"
+	"github.com/riandyrn/otelchi/internal/shadow"
+	"github.com/riandyrn/otelchi/otelchicore"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/semconv/v1.20.0/httpconv"
+	semconvstable "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	// ScopeName is the instrumentation scope name of the meter, it is kept
+	// as the path of the former `otelchi/metric` package so the existing
+	// queries & dashboards filtering by scope keep working.
+	ScopeName = "github.com/riandyrn/otelchi/metric"
+)
+
+// BaseConfig is used to configure the metrics middleware.
+type BaseConfig struct {
+	// for initialization
+	meterProvider   otelmetric.MeterProvider
+	meter           otelmetric.Meter
+	attributes      []attribute.KeyValue
+	schemaURL       string
+	scopeAttributes []attribute.KeyValue
+	dynamicName     *otelchicore.ServerName
+	serverNameFn    func(r *http.Request) string
+	routeLimiter    *otelchicore.RouteLimiter
+	chiRoutes       chi.Routes
+	clock           Clock
+	shadowFn        func(r *http.Request) bool
+	excludeShadow   bool
+	healthPaths     health.Paths
+	routeCache      *otelchicore.RouteCache
+	attributeFilter attrfilter.Fn
+	namePrefix      string
+	attributesFn    func(r *http.Request) []attribute.KeyValue
+	mountPrefix     string
+	notFoundRoute   string
+	routeAttributes routeattr.Registry
+	routeNormalize  func(pattern string) string
+	semconvMode     semconvutil.Mode
+	globalMeter     func() otelmetric.Meter
+	routes          *otelchicore.RouteResolver
+
+	// actual config state
+	// Meter is the meter used by the recorders, it is nil when the global
+	// meter provider is used since the meter is resolved when the first
+	// recorder is created.
+	Meter      otelmetric.Meter
+	ServerName string
+}
+
+// Option specifies instrumentation configuration options.
+type Option interface {
+	apply(*BaseConfig)
+}
+
+type optionFunc func(*BaseConfig)
+
+func (o optionFunc) apply(c *BaseConfig) {
+	o(c)
+}
+
+// WithMeterProvider specifies a meter provider to use for creating a meter.
+// If none is specified, the global provider is used, the meter is obtained
+// from it once the first recorder is created rather than when the config is
+// created. So the config could be created before the SDK is initialized (e.g.
+// as package variable), the global meter forwards the measurements to the
+// meter provider set later.
+func WithMeterProvider(provider otelmetric.MeterProvider) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.meterProvider = provider
+	})
+}
+
+// WithMeter specifies the meter used for creating the metric instruments. When
+// it is set, the meter provider is ignored. This is useful when the meter has
+// already been created with custom options.
+func WithMeter(meter otelmetric.Meter) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.meter = meter
+	})
+}
+
+// WithAttributes adds the given attributes to every metric recorded by the
+// recorders using this config, e.g. `deployment.environment=production`.
+//
+// When this option is used multiple times, the attributes are accumulated.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.attributes = append(cfg.attributes, attrs...)
+	})
+}
+
+// WithSchemaURL overrides the semantic conventions schema URL set on the meter.
+// This option is useful when the attributes are pinned to a different semantic
+// conventions version.
+func WithSchemaURL(schemaURL string) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.schemaURL = schemaURL
+	})
+}
+
+// WithScopeAttributes adds instrumentation scope attributes to the meter, by
+// default the meter only has `service.name` scope attribute.
+func WithScopeAttributes(attrs ...attribute.KeyValue) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.scopeAttributes = append(cfg.scopeAttributes, attrs...)
+	})
+}
+
+// WithDynamicServerName specifies the server name holder (see
+// `otelchicore.NewServerName`) used for the server name attribute. When set,
+// the serverName parameter passed to [NewBaseConfig] is ignored and the
+// latest name stored in the holder is used for every request instead. The
+// same holder could be passed to the tracing middleware.
+func WithDynamicServerName(name *otelchicore.ServerName) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.dynamicName = name
+	})
+}
+
+// WithMaxRouteCardinality limits the number of distinct `http.route` values
+// recorded by the metric recorders to n. The routes seen after the limit is
+// reached are collapsed into the fallback route, protecting the metric
+// backend from cardinality explosion. If the fallback is empty, `_other_` is
+// used. The limit is shared by every recorder using the same [BaseConfig].
+func WithMaxRouteCardinality(n int, fallback string) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.routeLimiter = otelchicore.NewRouteLimiter(n, fallback)
+	})
+}
+
+// WithServerNameFn specifies the function used for deriving the server name
+// attribute per request, e.g. from the `Host` header for multi-domain
+// deployments. When the function returns an empty string, the server name
+// falls back to the one given to [WithDynamicServerName] or [NewBaseConfig].
+func WithServerNameFn(fn func(r *http.Request) string) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.serverNameFn = fn
+	})
+}
+
+// Clock is the time source used by the metric recorders, it is the same type
+// as `otelchitrace.Clock`.
+type Clock = otelchicore.Clock
+
+// WithClock specifies the time source used for measuring the request
+// duration. It is mainly useful for tests which need deterministic durations
+// without real sleeps. If none is specified, the system clock is used.
+func WithClock(clock Clock) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.clock = clock
+	})
+}
+
+// WithTimeNow specifies the function returning the current time, it is the
+// shorthand of [WithClock] for the time source which is a plain function.
+func WithTimeNow(now func() time.Time) Option {
+	return WithClock(otelchicore.ClockFunc(now))
+}
+
+// WithShadowTraffic enables tagging the metrics of shadow (mirrored) requests
+// with `request.shadow=true` attribute. When exclude is true, the shadow
+// requests are not recorded at all, so traffic-mirroring rollouts don't
+// pollute the production latency & error rates.
+//
+// The fn is used for detecting the shadow requests. If it is set to `nil`,
+// requests having `X-Shadow-Request: true` header are considered as shadow
+// requests.
+func WithShadowTraffic(fn func(r *http.Request) bool, exclude bool) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		if fn == nil {
+			fn = shadow.FromDefaultHeader
+		}
+		cfg.shadowFn = fn
+		cfg.excludeShadow = exclude
+	})
+}
+
+// WithHealthEndpointsFiltered excludes the requests to the health-check
+// endpoints with the given paths from the metrics. If no paths are given,
+// `/healthz`, `/livez`, `/readyz` & `/ping` are used. The paths are matched
+// exactly against the request URL path.
+func WithHealthEndpointsFiltered(paths ...string) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.healthPaths = health.NewPaths(paths...)
+	})
+}
+
+// WithChiRoutes specifies the routes used by the application. The routes are
+// used for resolving the `http.route` attribute before the request is handled
+// by chi router, e.g. for [NewRequestInFlight] which records the metric
+// before the handler is executed. Without this option the route could only be
+// resolved after the request is handled.
+func WithChiRoutes(routes chi.Routes) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.chiRoutes = routes
+	})
+}
+
+// WithRouteCache specifies the cache used for resolving the route pattern
+// from the routes specified by [WithChiRoutes], which saves matching the
+// routes on every request. The cache could be shared with the tracing
+// middleware (see `otelchitrace.NewRouteCache`) as long as they use the same
+// routes.
+func WithRouteCache(cache *otelchicore.RouteCache) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.routeCache = cache
+	})
+}
+
+// WithAttributeFilter specifies the function deciding whether the attribute
+// is kept, it is applied to every attribute of the metrics recorded by the
+// recorders regardless of which option adds them. This allows platform teams
+// to centrally drop high-cardinality or PII attributes.
+func WithAttributeFilter(fn func(attribute.KeyValue) bool) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.attributeFilter = fn
+	})
+}
+
+// WithMetricNamePrefix specifies the prefix added to the name of every metric
+// recorded by the recorders, e.g. `myapp_` turns `request_duration_millis`
+// into `myapp_request_duration_millis`. This is useful for namespacing the
+// metrics of different applications.
+func WithMetricNamePrefix(prefix string) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.namePrefix = prefix
+	})
+}
+
+// WithMetricAttributesFn specifies a function invoked for every recorded
+// request, the returned attributes are added into the metrics of all
+// recorders. This is useful for attaching per-request labels such as tenant
+// ID uniformly. For constant attributes use [WithAttributes] instead.
+//
+// The function is invoked on the hot path, it is advised to make it simple
+// and fast. The returned attributes should have low cardinality since every
+// distinct value creates a new metric series.
+func WithMetricAttributesFn(fn func(r *http.Request) []attribute.KeyValue) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.attributesFn = fn
+	})
+}
+
+// WithMountPrefix specifies the prefix added to the recorded route pattern. It
+// is useful when the router is mounted under a path which is not visible to
+// chi, e.g. through `http.StripPrefix`. The prefix of the chi sub-router
+// mounted through `Mount` is detected automatically, so this option should
+// not be used for it.
+func WithMountPrefix(prefix string) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.mountPrefix = strings.TrimSuffix(prefix, "/")
+	})
+}
+
+// WithNotFoundRouteLabel specifies the synthetic route recorded as
+// `http.route` attribute when no route matches the request, e.g.
+// `WithNotFoundRouteLabel("{not_found}")`, so the not found rate could be
+// graphed by route. The label is only recorded by the recorders measuring
+// the handled requests, the in-flight requests are not labeled since the
+// route is not resolved yet.
+func WithNotFoundRouteLabel(label string) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.notFoundRoute = label
+	})
+}
+
+// WithRouteAttributes adds the static attributes (e.g. owner team, API tier,
+// deprecation flag) to the metrics of the routes keyed by chi route pattern.
+// The pattern ending with `/*` also matches every route under the prefix, the
+// exact match takes precedence over the prefix match. The attributes are only
+// added once the route is resolved.
+//
+// When this option is used multiple times, the attributes are accumulated.
+func WithRouteAttributes(attrs map[string][]attribute.KeyValue) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.routeAttributes = append(cfg.routeAttributes, routeattr.New(attrs)...)
+	})
+}
+
+// WithRoutePatternNormalizer specifies the function normalizing the route
+// pattern recorded as `http.route` attribute, e.g. [StripRouteRegexps] for
+// removing the regular expression constraints which some backends can't
+// handle (`/users/{id:[0-9]+}` becomes `/users/{id}`). The route attributes
+// (see [WithRouteAttributes]) are matched against the normalized pattern.
+func WithRoutePatternNormalizer(fn func(pattern string) string) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.routeNormalize = fn
+	})
+}
+
+// StripRouteRegexps returns the given chi route pattern without the regular
+// expression constraints of its parameters, e.g. `/users/{id:[0-9]+}` becomes
+// `/users/{id}`. It could be used with [WithRoutePatternNormalizer].
+func StripRouteRegexps(pattern string) string {
+	return otelchicore.StripRouteRegexps(pattern)
+}
+
+// NewBaseConfig returns the config shared by the metric recorders. The
+// serverName parameter should describe the name of the (virtual) server
+// handling the request.
+func NewBaseConfig(serverName string, opts ...Option) BaseConfig {
+	// init base config
+	cfg := BaseConfig{
+		ServerName:  serverName,
+		semconvMode: semconvutil.ModeFromEnv(),
+	}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	cfg.routes = otelchicore.NewRouteResolver(otelchicore.RouteConfig{
+		Routes:        cfg.chiRoutes,
+		Cache:         cfg.routeCache,
+		MountPrefix:   cfg.mountPrefix,
+		NotFoundLabel: cfg.notFoundRoute,
+		Normalize:     cfg.routeNormalize,
+		Limiter:       cfg.routeLimiter,
+	})
+
+	if cfg.meter != nil {
+		cfg.Meter = cfg.meter
+		return cfg
+	}
+
+	if len(cfg.schemaURL) == 0 {
+		cfg.schemaURL = semconv.SchemaURL
+	}
+	meterOpts := []otelmetric.MeterOption{
+		otelmetric.WithSchemaURL(cfg.schemaURL),
+		otelmetric.WithInstrumentationVersion(Version()),
+		otelmetric.WithInstrumentationAttributes(
+			append([]attribute.KeyValue{semconv.ServiceName(serverName)}, cfg.scopeAttributes...)...,
+		),
+	}
+
+	// resolve the global meter lazily, so the config could be created
+	// before the SDK is initialized
+	if cfg.meterProvider == nil {
+		cfg.globalMeter = sync.OnceValue(func() otelmetric.Meter {
+			return otel.GetMeterProvider().Meter(ScopeName, meterOpts...)
+		})
+		return cfg
+	}
+	cfg.Meter = cfg.meterProvider.Meter(ScopeName, meterOpts...)
+
+	return cfg
+}
+
+// resolveMeter returns the meter used for creating the metric instruments,
+// the global meter is obtained once it is first needed.
+func (cfg BaseConfig) resolveMeter() otelmetric.Meter {
+	if cfg.Meter == nil && cfg.globalMeter != nil {
+		return cfg.globalMeter()
+	}
+	return cfg.Meter
+}
+
+// now returns the current time according to the configured clock.
+func (cfg BaseConfig) now() time.Time {
+	if cfg.clock != nil {
+		return cfg.clock.Now()
+	}
+	return time.Now()
+}
+
+// isShadow returns true when the given request is a shadow request.
+func (cfg BaseConfig) isShadow(r *http.Request) bool {
+	return cfg.shadowFn != nil && cfg.shadowFn(r)
+}
+
+// skipRecording returns true when the given request should not be recorded.
+func (cfg BaseConfig) skipRecording(r *http.Request) bool {
+	return (cfg.excludeShadow && cfg.isShadow(r)) || cfg.healthPaths.Match(r)
+}
+
+// metricName returns the given metric name with the prefix specified through
+// [WithMetricNamePrefix].
+func (cfg BaseConfig) metricName(name string) string {
+	return cfg.namePrefix + name
+}
+
+// withAttributes returns the measurement option of the given attributes kept
+// by the filter specified through [WithAttributeFilter].
+func (cfg BaseConfig) withAttributes(attrs []attribute.KeyValue) otelmetric.MeasurementOption {
+	return otelmetric.WithAttributes(attrfilter.Apply(cfg.attributeFilter, attrs)...)
+}
+
+// serverName returns the effective server name of the config.
+func (cfg BaseConfig) serverName(r *http.Request) string {
+	if cfg.serverNameFn != nil {
+		if name := cfg.serverNameFn(r); len(name) > 0 {
+			return name
+		}
+	}
+	if cfg.dynamicName != nil {
+		return cfg.dynamicName.Get()
+	}
+	return cfg.ServerName
+}
+
+// requestAttributes returns the metric attributes describing the given request,
+// it is shared by all metric recorders. The `http.route` attribute is only
+// included when the route pattern has been resolved.
+//
+// Only low-cardinality attributes are included, the attributes identifying the
+// client (e.g. peer address, user agent) are excluded since they would explode
+// the number of metric series.
+func (cfg BaseConfig) requestAttributes(r *http.Request, route string) []attribute.KeyValue {
+	attrs := lowCardinalityAttributes(httpconv.ServerRequest(cfg.serverName(r), r))
+	if len(route) > 0 {
+		attrs = append(attrs, semconv.HTTPRoute(route))
+		attrs = append(attrs, cfg.routeAttributes.Lookup(route)...)
+	}
+	attrs = append(attrs, cfg.attributes...)
+	if cfg.attributesFn != nil {
+		attrs = append(attrs, cfg.attributesFn(r)...)
+	}
+	if drain.Active() {
+		attrs = append(attrs, drain.Key.Bool(true))
+	}
+	if cfg.isShadow(r) {
+		attrs = append(attrs, shadow.Key.Bool(true))
+	}
+	return attrs
+}
+
+// lowCardinalityKeys are the request attributes from semantic conventions
+// v1.20.0 which are safe to be used as metric attributes.
+var lowCardinalityKeys = map[attribute.Key]struct{}{
+	semconv.HTTPMethodKey:         {},
+	semconv.HTTPSchemeKey:         {},
+	semconv.NetProtocolNameKey:    {},
+	semconv.NetProtocolVersionKey: {},
+	semconv.NetHostNameKey:        {},
+	semconv.NetHostPortKey:        {},
+}
+
+func lowCardinalityAttributes(attrs []attribute.KeyValue) []attribute.KeyValue {
+	res := attrs[:0]
+	for _, attr := range attrs {
+		if _, ok := lowCardinalityKeys[attr.Key]; ok {
+			res = append(res, attr)
+		}
+	}
+	return res
+}
+
+// stableRequestAttributes returns the metric attributes describing the given
+// request conforming to the stable HTTP semantic conventions. The route &
+// status code are only known after the request is handled, so they are
+// passed explicitly.
+func (cfg BaseConfig) stableRequestAttributes(r *http.Request, route string, status int) []attribute.KeyValue {
+	attrs := semconvutil.HTTPServerRequestMetrics(cfg.serverName(r), r)
+	if len(route) > 0 {
+		attrs = append(attrs, semconvstable.HTTPRoute(route))
+		attrs = append(attrs, cfg.routeAttributes.Lookup(route)...)
+	}
+	if status > 0 {
+		attrs = append(attrs, semconvstable.HTTPResponseStatusCode(status))
+	}
+	attrs = append(attrs, cfg.attributes...)
+	if cfg.attributesFn != nil {
+		attrs = append(attrs, cfg.attributesFn(r)...)
+	}
+	if drain.Active() {
+		attrs = append(attrs, drain.Key.Bool(true))
+	}
+	if cfg.isShadow(r) {
+		attrs = append(attrs, shadow.Key.Bool(true))
+	}
+	return attrs
+}
+
+// routePattern returns the chi route pattern matched by the given request,
+// normalized by [WithRoutePatternNormalizer] & collapsed into the fallback
+// route when it exceeds the limit set by [WithMaxRouteCardinality].
+//
+// The pattern is taken from chi route context which is only available after
+// the request is handled by chi router, when it is not available yet the
+// pattern is resolved from the routes specified through [WithChiRoutes].
+func (cfg BaseConfig) routePattern(r *http.Request) string {
+	var pattern string
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && len(rctx.RoutePattern()) > 0 {
+		pattern = cfg.routes.Pattern(rctx.RoutePattern())
+	} else {
+		pattern = cfg.routes.Match(r)
+	}
+	return cfg.routes.HTTPRoute(pattern)
+}
+
+// handledRoutePattern returns the route pattern of the request which has been
+// handled, the label specified through [WithNotFoundRouteLabel] is returned
+// when no route matches the request.
+func (cfg BaseConfig) handledRoutePattern(r *http.Request) string {
+	if route := cfg.routePattern(r); len(route) > 0 {
+		return route
+	}
+	return cfg.notFoundRoute
+}
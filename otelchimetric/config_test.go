@@ -0,0 +1,204 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/riandyrn/otelchi/otelchitrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestBaseConfigWithMeterAndAttributes(t *testing.T) {
+	// setup environment, use custom meter instead of meter provider
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	meter := provider.Meter("custom-scope")
+
+	baseCfg := otelchimetric.NewBaseConfig(
+		"test-server",
+		otelchimetric.WithMeter(meter),
+		otelchimetric.WithAttributes(attribute.String("deployment.environment", "test")),
+	)
+	middleware := otelchimetric.MustNewRequestInFlight(baseCfg)
+
+	router := chi.NewRouter()
+	router.Use(middleware)
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, "custom-scope", rm.ScopeMetrics[0].Scope.Name)
+
+	sum, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+
+	v, ok := sum.DataPoints[0].Attributes.Value(attribute.Key("deployment.environment"))
+	require.True(t, ok)
+	assert.Equal(t, "test", v.AsString())
+}
+
+func TestBaseConfigWithDynamicServerName(t *testing.T) {
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	serverName := otelchitrace.NewServerName("before")
+	baseCfg := otelchimetric.NewBaseConfig(
+		"ignored",
+		otelchimetric.WithMeterProvider(provider),
+		otelchimetric.WithDynamicServerName(serverName),
+	)
+	middleware := otelchimetric.MustNewRequestDurationMillis(baseCfg)
+
+	router := chi.NewRouter()
+	router.Use(middleware)
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute requests before and after the server name is updated
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+	serverName.Set("after")
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	hist, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 2)
+
+	var names []string
+	for _, dp := range hist.DataPoints {
+		v, ok := dp.Attributes.Value(attribute.Key("net.host.name"))
+		require.True(t, ok)
+		names = append(names, v.AsString())
+	}
+	assert.ElementsMatch(t, []string{"before", "after"}, names)
+}
+
+func TestBaseConfigWithServerNameFn(t *testing.T) {
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig(
+		"fallback",
+		otelchimetric.WithMeterProvider(provider),
+		otelchimetric.WithServerNameFn(func(r *http.Request) string {
+			if r.Host == "example.com" {
+				return ""
+			}
+			return r.Host
+		}),
+	)
+	middleware := otelchimetric.MustNewRequestDurationMillis(baseCfg)
+
+	router := chi.NewRouter()
+	router.Use(middleware)
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute requests for different hosts
+	for _, host := range []string{"a.example.com", "example.com"} {
+		r := httptest.NewRequest(http.MethodGet, "/test", nil)
+		r.Host = host
+		router.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	hist, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 2)
+
+	var names []string
+	for _, dp := range hist.DataPoints {
+		v, ok := dp.Attributes.Value(attribute.Key("net.host.name"))
+		require.True(t, ok)
+		names = append(names, v.AsString())
+	}
+	assert.ElementsMatch(t, []string{"a.example.com", "fallback"}, names)
+}
+
+func TestBaseConfigLazyGlobalMeterProvider(t *testing.T) {
+	prevProvider := otel.GetMeterProvider()
+	defer otel.SetMeterProvider(prevProvider)
+
+	// create the config while the other global meter provider is set
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider())
+	baseCfg := otelchimetric.NewBaseConfig("test-server")
+
+	// swap the global meter provider, the recorder created afterwards uses
+	// the new provider
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	router := chi.NewRouter()
+	router.Use(otelchimetric.MustNewRequestCounter(baseCfg))
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	// ensure the metric is recorded by the global meter provider with the
+	// scope of the config
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, otelchimetric.ScopeName, rm.ScopeMetrics[0].Scope.Name)
+	assert.Equal(t, otelchimetric.Version(), rm.ScopeMetrics[0].Scope.Version)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+}
+
+func TestBaseConfigGlobalMeterResolvedOnce(t *testing.T) {
+	prevProvider := otel.GetMeterProvider()
+	defer otel.SetMeterProvider(prevProvider)
+
+	// create the first recorder, it resolves the global meter
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	baseCfg := otelchimetric.NewBaseConfig("test-server")
+	counter := otelchimetric.MustNewRequestCounter(baseCfg)
+
+	// swap the global meter provider, the recorders created afterwards keep
+	// using the same meter as the first one
+	otherReader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(otherReader)))
+
+	router := chi.NewRouter()
+	router.Use(counter, otelchimetric.MustNewResponseSizeBytes(baseCfg))
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	// ensure both metrics are recorded by the first meter provider
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Len(t, rm.ScopeMetrics[0].Metrics, 2)
+
+	require.NoError(t, otherReader.Collect(context.Background(), &rm))
+	assert.Empty(t, rm.ScopeMetrics)
+}
@@ -0,0 +1,94 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/riandyrn/otelchi/otelchitrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRequestDurationMillisDraining(t *testing.T) {
+	defer otelchitrace.UnmarkDraining()
+
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeterProvider(provider))
+	middleware := otelchimetric.MustNewRequestDurationMillis(baseCfg)
+
+	router := chi.NewRouter()
+	router.Use(middleware)
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute request before and after the server is marked as draining
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+	otelchitrace.MarkDraining()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	hist, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+
+	// ensure the draining request is recorded in separate data point
+	require.Len(t, hist.DataPoints, 2)
+	var drainingCount int
+	for _, dp := range hist.DataPoints {
+		if v, ok := dp.Attributes.Value(attribute.Key("server.draining")); ok && v.AsBool() {
+			drainingCount++
+		}
+	}
+	assert.Equal(t, 1, drainingCount)
+}
+
+func TestDrainingInFlight(t *testing.T) {
+	defer otelchitrace.UnmarkDraining()
+
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeterProvider(provider))
+	middleware := otelchimetric.MustNewDrainingInFlight(baseCfg)
+
+	// the metrics are collected while the request is in flight
+	var rm metricdata.ResourceMetrics
+	router := chi.NewRouter()
+	router.Use(middleware)
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+	})
+
+	// ensure the gauge is not observed when the server is not draining
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Empty(t, rm.ScopeMetrics)
+
+	// ensure the gauge reports the request in flight during draining
+	otelchitrace.MarkDraining()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	assert.Equal(t, "http.server.draining.requests_inflight", rm.ScopeMetrics[0].Metrics[0].Name)
+
+	gauge, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Gauge[int64])
+	require.True(t, ok)
+	require.Len(t, gauge.DataPoints, 1)
+	assert.Equal(t, int64(1), gauge.DataPoints[0].Value)
+	assert.Equal(t, attribute.NewSet(attribute.Bool("server.draining", true)), gauge.DataPoints[0].Attributes)
+}
@@ -0,0 +1,69 @@
+package otelchimetric
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/riandyrn/otelchi/internal/drain"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+const (
+	drainingInFlightName        = "http.server.draining.requests_inflight"
+	drainingInFlightDescription = "Number of HTTP server requests in flight while the server is draining."
+	drainingInFlightUnit        = "{request}"
+)
+
+// NewDrainingInFlight is a metrics recorder for observing the number of
+// requests in flight while the server is draining (i.e. after
+// `otelchitrace.MarkDraining` is called) as
+// `http.server.draining.requests_inflight` gauge. The gauge is only observed
+// during draining, so its data points mark the deployments & show how fast
+// the server drains, which helps correlating the 5xx spikes with the
+// requests cut off by the shutdown.
+func NewDrainingInFlight(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	var inFlight atomic.Int64
+	attrs := append([]attribute.KeyValue{drain.Key.Bool(true)}, cfg.attributes...)
+
+	// init metric, here we are using observable gauge for taking the
+	// snapshot of the requests in flight on every collection
+	_, err := cfg.resolveMeter().Int64ObservableGauge(
+		cfg.metricName(drainingInFlightName),
+		otelmetric.WithDescription(drainingInFlightDescription),
+		otelmetric.WithUnit(drainingInFlightUnit),
+		otelmetric.WithInt64Callback(func(_ context.Context, observer otelmetric.Int64Observer) error {
+			if drain.Active() {
+				observer.Observe(inFlight.Load(), cfg.withAttributes(attrs))
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s gauge: %w", drainingInFlightName, err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request
+			if cfg.skipRecording(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			inFlight.Add(1)
+			defer inFlight.Add(-1)
+
+			// execute next http handler
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// MustNewDrainingInFlight is like [NewDrainingInFlight] but panics when the metric instrument cannot
+// be created.
+func MustNewDrainingInFlight(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return must(NewDrainingInFlight(cfg))
+}
@@ -0,0 +1,74 @@
+package otelchimetric
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/riandyrn/otelchi/otelchicore"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+const (
+	errorsName        = "http.server.errors"
+	errorsDescription = "Number of HTTP server requests responded with error status code."
+	errorsUnit        = "{request}"
+)
+
+// NewErrorRate is a metrics recorder for counting the requests responded with
+// error status code as `http.server.errors` metric, keyed by `http.route`,
+// `http.request.method` & `http.response.status_class` (e.g. `5xx`)
+// attributes. The error rate (e.g. for SLO burn-rate alerts) could be computed
+// against [NewRequestCounter] without involving the duration histogram.
+//
+// By default the responses having status code >= 500 are counted, use
+// [WithErrorStatusThreshold] for changing the threshold.
+func NewErrorRate(cfg BaseConfig, opts ...RecorderOption) (func(next http.Handler) http.Handler, error) {
+	recorderCfg := newRecorderConfig(opts)
+	threshold := recorderCfg.errorStatusThreshold
+	if threshold <= 0 {
+		threshold = http.StatusInternalServerError
+	}
+
+	// init metric, here we are using counter for counting the error responses
+	counter, err := cfg.resolveMeter().Int64Counter(
+		cfg.metricName(errorsName),
+		otelmetric.WithDescription(errorsDescription),
+		otelmetric.WithUnit(errorsUnit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s counter: %w", errorsName, err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request
+			if cfg.skipRecording(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// get recording response writer
+			rrw := otelchicore.AcquireResponseWriter(w)
+			defer rrw.Release()
+
+			// execute next http handler
+			next.ServeHTTP(rrw.Writer(), r)
+
+			if rrw.Status() < threshold {
+				return
+			}
+
+			// count the error response, the route pattern is only available
+			// after the request is handled
+			attrs := cfg.stableRequestAttributes(r, cfg.handledRoutePattern(r), 0)
+			attrs = append(attrs, statusClassKey.String(statusClass(rrw.Status())))
+			counter.Add(r.Context(), 1, cfg.withAttributes(attrs))
+		})
+	}, nil
+}
+
+// MustNewErrorRate is like [NewErrorRate] but panics when the metric instrument cannot
+// be created.
+func MustNewErrorRate(cfg BaseConfig, opts ...RecorderOption) func(next http.Handler) http.Handler {
+	return must(NewErrorRate(cfg, opts...))
+}
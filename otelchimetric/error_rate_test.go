@@ -0,0 +1,90 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestErrorRate(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Options   []otelchimetric.RecorderOption
+		ExpCounts map[string]int64
+	}{
+		{
+			Name: "Default Threshold",
+			ExpCounts: map[string]int64{
+				"GET /fail/{id} 5xx": 2,
+				"GET /busy 5xx":      1,
+			},
+		},
+		{
+			Name:    "Client Errors Threshold",
+			Options: []otelchimetric.RecorderOption{otelchimetric.WithErrorStatusThreshold(http.StatusBadRequest)},
+			ExpCounts: map[string]int64{
+				"GET /fail/{id} 5xx": 2,
+				"GET /busy 5xx":      1,
+				"GET /missing 4xx":   1,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// setup environment
+			reader := sdkmetric.NewManualReader()
+			provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeterProvider(provider))
+
+			router := chi.NewRouter()
+			router.Use(otelchimetric.MustNewErrorRate(baseCfg, testCase.Options...))
+			router.Get("/fail/{id}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+			router.Get("/busy", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			})
+			router.Get("/missing", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			})
+			router.Get("/ok", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			// execute requests
+			for _, path := range []string{"/fail/1", "/fail/2", "/busy", "/missing", "/ok"} {
+				router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+			}
+
+			// ensure only the error responses are counted
+			var rm metricdata.ResourceMetrics
+			err := reader.Collect(context.Background(), &rm)
+			require.NoError(t, err)
+			require.Len(t, rm.ScopeMetrics, 1)
+			require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+
+			m := rm.ScopeMetrics[0].Metrics[0]
+			assert.Equal(t, "http.server.errors", m.Name)
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+			counts := map[string]int64{}
+			for _, dp := range sum.DataPoints {
+				method, _ := dp.Attributes.Value("http.request.method")
+				route, _ := dp.Attributes.Value("http.route")
+				class, _ := dp.Attributes.Value("http.response.status_class")
+				_, hasStatus := dp.Attributes.Value("http.response.status_code")
+				assert.False(t, hasStatus)
+				counts[method.AsString()+" "+route.AsString()+" "+class.AsString()] += dp.Value
+			}
+			assert.Equal(t, testCase.ExpCounts, counts)
+		})
+	}
+}
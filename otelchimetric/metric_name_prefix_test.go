@@ -0,0 +1,61 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetricNamePrefixAndAttributesFn(t *testing.T) {
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig(
+		"test-server",
+		otelchimetric.WithMeterProvider(provider),
+		otelchimetric.WithMetricNamePrefix("myapp_"),
+		otelchimetric.WithMetricAttributesFn(func(r *http.Request) []attribute.KeyValue {
+			return []attribute.KeyValue{attribute.String("tenant.id", r.Header.Get("X-Tenant-ID"))}
+		}),
+	)
+	router := chi.NewRouter()
+	router.Use(otelchimetric.MustNewRequestDurationSeconds(baseCfg))
+	router.Get("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute request
+	req := httptest.NewRequest(http.MethodGet, "/user/123", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+
+	// ensure the name is prefixed
+	m := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "myapp_http.server.request.duration", m.Name)
+
+	// ensure the attributes from the function are recorded
+	hist, ok := m.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+
+	v, ok := hist.DataPoints[0].Attributes.Value("tenant.id")
+	assert.True(t, ok)
+	assert.Equal(t, "acme", v.AsString())
+}
@@ -0,0 +1,77 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMountedSubrouterRoute(t *testing.T) {
+	testCases := []struct {
+		name  string
+		opts  []otelchimetric.Option
+		mount func(subrouter http.Handler) http.Handler
+	}{
+		{
+			name: "chi mount",
+			mount: func(subrouter http.Handler) http.Handler {
+				router := chi.NewRouter()
+				router.Mount("/api/v1", subrouter)
+				return router
+			},
+		},
+		{
+			name: "mount prefix",
+			opts: []otelchimetric.Option{otelchimetric.WithMountPrefix("/api/v1")},
+			mount: func(subrouter http.Handler) http.Handler {
+				mux := http.NewServeMux()
+				mux.Handle("/api/v1/", http.StripPrefix("/api/v1", subrouter))
+				return mux
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// setup environment
+			reader := sdkmetric.NewManualReader()
+			provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+			subrouter := chi.NewRouter()
+			baseCfg := otelchimetric.NewBaseConfig(
+				"test-server",
+				append(tc.opts, otelchimetric.WithMeterProvider(provider))...,
+			)
+			subrouter.Use(otelchimetric.MustNewRequestDurationSeconds(baseCfg))
+			subrouter.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			// execute request
+			handler := tc.mount(subrouter)
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/users/123", nil))
+
+			// read the recorded metrics
+			var rm metricdata.ResourceMetrics
+			err := reader.Collect(context.Background(), &rm)
+			require.NoError(t, err)
+			require.Len(t, rm.ScopeMetrics, 1)
+
+			hist, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64])
+			require.True(t, ok)
+			require.Len(t, hist.DataPoints, 1)
+
+			// ensure the route includes the mount prefix
+			route, ok := hist.DataPoints[0].Attributes.Value("http.route")
+			assert.True(t, ok)
+			assert.Equal(t, "/api/v1/users/{id}", route.AsString())
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package otelchimetric
+
+import (
+	"net/http"
+)
+
+// must returns the given recorder, it panics when the recorder couldn't be
+// created. It is used by the `Must` variants of the recorder constructors.
+func must(recorder func(next http.Handler) http.Handler, err error) func(next http.Handler) http.Handler {
+	if err != nil {
+		panic(err)
+	}
+	return recorder
+}
@@ -0,0 +1,52 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestNotFoundRouteLabel(t *testing.T) {
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	baseCfg := otelchimetric.NewBaseConfig(
+		"test-server",
+		otelchimetric.WithMeterProvider(provider),
+		otelchimetric.WithNotFoundRouteLabel("{not_found}"),
+	)
+
+	router := chi.NewRouter()
+	router.Use(otelchimetric.MustNewRequestCounter(baseCfg))
+	router.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute requests
+	for _, path := range []string{"/users/1", "/wp-admin/setup.php", "/.env"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// ensure the unmatched requests are counted with the synthetic route
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	sum, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	counts := map[string]int64{}
+	for _, dp := range sum.DataPoints {
+		route, _ := dp.Attributes.Value("http.route")
+		counts[route.AsString()] += dp.Value
+	}
+	assert.Equal(t, map[string]int64{"/users/{id}": 1, "{not_found}": 2}, counts)
+}
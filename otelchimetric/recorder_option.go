@@ -0,0 +1,66 @@
+package otelchimetric
+
+// recorderConfig is used to configure a single metrics recorder.
+type recorderConfig struct {
+	bucketBoundaries     []float64
+	statusClass          bool
+	errorStatusThreshold int
+}
+
+// RecorderOption specifies configuration options for a single metrics
+// recorder, unlike [Option] which applies to every recorder sharing the same
+// [BaseConfig].
+type RecorderOption interface {
+	applyRecorder(*recorderConfig)
+}
+
+type recorderOptionFunc func(*recorderConfig)
+
+func (o recorderOptionFunc) applyRecorder(c *recorderConfig) {
+	o(c)
+}
+
+// WithExplicitBucketBoundaries sets the bucket boundaries of the histogram
+// created by the recorder, the boundaries must be in the same unit as the
+// histogram (e.g. milliseconds for [NewRequestDurationMillis]). This avoids
+// the need of registering a view in the SDK for having custom buckets.
+func WithExplicitBucketBoundaries(bounds ...float64) RecorderOption {
+	return recorderOptionFunc(func(cfg *recorderConfig) {
+		cfg.bucketBoundaries = bounds
+	})
+}
+
+// WithStatusClass makes the recorder record the class of the response status
+// code as `http.response.status_class` attribute (e.g. `2xx`, `5xx`) instead
+// of the status code itself, it is only supported by [NewRequestCounter].
+func WithStatusClass() RecorderOption {
+	return recorderOptionFunc(func(cfg *recorderConfig) {
+		cfg.statusClass = true
+	})
+}
+
+// WithErrorStatusThreshold sets the minimum response status code counted as
+// error by [NewErrorRate], e.g. `http.StatusBadRequest` for counting the client
+// errors as well. The default threshold is `http.StatusInternalServerError`.
+func WithErrorStatusThreshold(status int) RecorderOption {
+	return recorderOptionFunc(func(cfg *recorderConfig) {
+		cfg.errorStatusThreshold = status
+	})
+}
+
+func newRecorderConfig(opts []RecorderOption) recorderConfig {
+	cfg := recorderConfig{}
+	for _, opt := range opts {
+		opt.applyRecorder(&cfg)
+	}
+	return cfg
+}
+
+// bucketBoundariesOr returns the bucket boundaries specified for the
+// recorder, the defaultBounds is returned when none is specified.
+func (cfg recorderConfig) bucketBoundariesOr(defaultBounds []float64) []float64 {
+	if cfg.bucketBoundaries != nil {
+		return cfg.bucketBoundaries
+	}
+	return defaultBounds
+}
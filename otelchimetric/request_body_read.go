@@ -0,0 +1,139 @@
+package otelchimetric
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	requestBodyReadDurationName        = "http.server.request.body.read_duration"
+	requestBodyReadDurationDescription = "Duration spent by the HTTP server handlers reading the request bodies."
+	requestBodyReadDurationUnit        = "s"
+
+	requestBodyReadErrorsName        = "http.server.request.body.read_errors"
+	requestBodyReadErrorsDescription = "Number of HTTP server requests whose body failed to be read (e.g. aborted by the client)."
+	requestBodyReadErrorsUnit        = "{request}"
+)
+
+// RequestBodyReadErrorEventName is the name of the span event recorded by
+// [NewRequestBodyRead] when the request body failed to be read, the event has
+// `exception.message` & `http.request.body.read_bytes` attributes.
+const RequestBodyReadErrorEventName = "http.request.body.read_error"
+
+// requestBodyReadBytesKey is the attribute key of the number of bytes read
+// before the request body failed to be read.
+const requestBodyReadBytesKey = attribute.Key("http.request.body.read_bytes")
+
+// NewRequestBodyRead is a metrics recorder for recording the duration spent by
+// the handler reading the request body as
+// `http.server.request.body.read_duration` metric (in seconds), and counting
+// the requests whose body failed to be read (e.g. the client aborted the
+// upload) as `http.server.request.body.read_errors` metric. Comparing the read
+// duration with the request duration helps diagnosing slow clients versus
+// slow handlers. The duration uses the bucket boundaries of
+// [NewRequestDurationSeconds] unless [WithExplicitBucketBoundaries] is used.
+//
+// The requests whose body is not read are not recorded. The span in the
+// request context (e.g. the one started by otelchi tracing middleware) gets
+// `http.request.body.read_error` event when the body failed to be read.
+func NewRequestBodyRead(cfg BaseConfig, opts ...RecorderOption) (func(next http.Handler) http.Handler, error) {
+	recorderCfg := newRecorderConfig(opts)
+
+	// init metrics, here we are using histogram for capturing the read
+	// duration & counter for counting the read failures
+	histogram, err := cfg.resolveMeter().Float64Histogram(
+		cfg.metricName(requestBodyReadDurationName),
+		otelmetric.WithDescription(requestBodyReadDurationDescription),
+		otelmetric.WithUnit(requestBodyReadDurationUnit),
+		otelmetric.WithExplicitBucketBoundaries(recorderCfg.bucketBoundariesOr(requestDurationBucketBoundaries)...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s histogram: %w", requestBodyReadDurationName, err)
+	}
+	counter, err := cfg.resolveMeter().Int64Counter(
+		cfg.metricName(requestBodyReadErrorsName),
+		otelmetric.WithDescription(requestBodyReadErrorsDescription),
+		otelmetric.WithUnit(requestBodyReadErrorsUnit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s counter: %w", requestBodyReadErrorsName, err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request & request without body
+			if cfg.skipRecording(r) || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// time the reads of the request body
+			body := &timedBody{ReadCloser: r.Body, now: cfg.now}
+			r.Body = body
+
+			// execute next http handler
+			next.ServeHTTP(w, r)
+
+			if !body.read {
+				return
+			}
+
+			// record the read duration & the read failure, the route pattern
+			// is only available after the request is handled
+			attrs := cfg.withAttributes(cfg.stableRequestAttributes(r, cfg.handledRoutePattern(r), 0))
+			histogram.Record(r.Context(), body.duration.Seconds(), attrs)
+			if body.err != nil {
+				counter.Add(r.Context(), 1, attrs)
+				oteltrace.SpanFromContext(r.Context()).AddEvent(
+					RequestBodyReadErrorEventName,
+					oteltrace.WithTimestamp(body.failedAt),
+					oteltrace.WithAttributes(
+						attribute.String("exception.message", body.err.Error()),
+						requestBodyReadBytesKey.Int64(body.readBytes),
+					),
+				)
+			}
+		})
+	}, nil
+}
+
+// MustNewRequestBodyRead is like [NewRequestBodyRead] but panics when the metric instrument cannot
+// be created.
+func MustNewRequestBodyRead(cfg BaseConfig, opts ...RecorderOption) func(next http.Handler) http.Handler {
+	return must(NewRequestBodyRead(cfg, opts...))
+}
+
+// timedBody is the request body measuring the duration spent in reading it &
+// keeping the first read failure.
+type timedBody struct {
+	io.ReadCloser
+	now func() time.Time
+
+	read      bool
+	readBytes int64
+	duration  time.Duration
+	err       error
+	failedAt  time.Time
+}
+
+func (b *timedBody) Read(p []byte) (int, error) {
+	start := b.now()
+	n, err := b.ReadCloser.Read(p)
+	end := b.now()
+
+	b.read = true
+	b.readBytes += int64(n)
+	b.duration += end.Sub(start)
+	if err != nil && !errors.Is(err, io.EOF) && b.err == nil {
+		b.err = err
+		b.failedAt = end
+	}
+	return n, err
+}
@@ -0,0 +1,121 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// slowBody is the request body taking 100ms for every read, it fails with err
+// once the data is consumed.
+type slowBody struct {
+	clock *fakeClock
+	data  []byte
+	err   error
+}
+
+func (b *slowBody) Read(p []byte) (int, error) {
+	b.clock.Advance(100 * time.Millisecond)
+	if len(b.data) == 0 {
+		return 0, b.err
+	}
+	n := copy(p, b.data[:1])
+	b.data = b.data[n:]
+	return n, nil
+}
+
+func TestRequestBodyRead(t *testing.T) {
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test")
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	baseCfg := otelchimetric.NewBaseConfig(
+		"test-server",
+		otelchimetric.WithMeterProvider(provider),
+		otelchimetric.WithClock(clock),
+	)
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		// start the span like the tracing middleware
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := tracer.Start(r.Context(), r.URL.Path)
+			defer span.End()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	router.Use(otelchimetric.MustNewRequestBodyRead(baseCfg))
+	readBody := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}
+	router.Post("/upload", readBody)
+	router.Post("/aborted", readBody)
+	router.Post("/ignored", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute requests
+	newRequest := func(path string, err error) *http.Request {
+		body := &slowBody{clock: clock, data: []byte("abc"), err: err}
+		return httptest.NewRequest(http.MethodPost, path, body)
+	}
+	router.ServeHTTP(httptest.NewRecorder(), newRequest("/upload", io.EOF))
+	router.ServeHTTP(httptest.NewRecorder(), newRequest("/aborted", errors.New("unexpected EOF")))
+	router.ServeHTTP(httptest.NewRecorder(), newRequest("/ignored", io.EOF))
+
+	// ensure the read duration is recorded for the requests whose body is read
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 2)
+
+	metrics := map[string]metricdata.Metrics{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+	hist, ok := metrics["http.server.request.body.read_duration"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	durations := map[string]float64{}
+	for _, dp := range hist.DataPoints {
+		route, _ := dp.Attributes.Value("http.route")
+		durations[route.AsString()] = dp.Sum
+	}
+	assert.InDeltaMapValues(t, map[string]float64{"/upload": 0.4, "/aborted": 0.4}, durations, 1e-9)
+
+	// ensure only the failed read is counted
+	sum, ok := metrics["http.server.request.body.read_errors"].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	route, _ := sum.DataPoints[0].Attributes.Value("http.route")
+	assert.Equal(t, "/aborted", route.AsString())
+	assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+
+	// ensure the span of the failed read has the event
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Empty(t, spans[0].Events())
+	require.Len(t, spans[1].Events(), 1)
+	event := spans[1].Events()[0]
+	assert.Equal(t, otelchimetric.RequestBodyReadErrorEventName, event.Name)
+	assert.Equal(t, clock.Now(), event.Time)
+	assert.Contains(t, event.Attributes, attribute.String("exception.message", "unexpected EOF"))
+	assert.Contains(t, event.Attributes, attribute.Int64("http.request.body.read_bytes", 3))
+}
@@ -0,0 +1,81 @@
+package otelchimetric
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/riandyrn/otelchi/otelchicore"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+const (
+	requestCountName        = "http.server.request.count"
+	requestCountDescription = "Number of HTTP server requests."
+	requestCountUnit        = "{request}"
+)
+
+// statusClassKey is the attribute key of the response status code class
+// recorded by [NewRequestCounter] when [WithStatusClass] is used.
+const statusClassKey = attribute.Key("http.response.status_class")
+
+// NewRequestCounter is a metrics recorder for counting the requests as
+// `http.server.request.count` metric. The metric uses the same attributes as
+// [NewRequestDurationSeconds] (e.g. `http.route`, `http.request.method`,
+// `http.response.status_code`), so RED dashboards could be built without
+// depending on the count of the duration histogram.
+//
+// Use [WithStatusClass] to record the status code class (e.g. `2xx`) instead
+// of the status code for reducing the cardinality.
+func NewRequestCounter(cfg BaseConfig, opts ...RecorderOption) (func(next http.Handler) http.Handler, error) {
+	recorderCfg := newRecorderConfig(opts)
+
+	// init metric, here we are using counter for counting the requests
+	counter, err := cfg.resolveMeter().Int64Counter(
+		cfg.metricName(requestCountName),
+		otelmetric.WithDescription(requestCountDescription),
+		otelmetric.WithUnit(requestCountUnit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s counter: %w", requestCountName, err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request
+			if cfg.skipRecording(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// get recording response writer
+			rrw := otelchicore.AcquireResponseWriter(w)
+			defer rrw.Release()
+
+			// execute next http handler
+			next.ServeHTTP(rrw.Writer(), r)
+
+			// count the request, the route pattern & the status code are
+			// only available after the request is handled
+			var attrs []attribute.KeyValue
+			if recorderCfg.statusClass {
+				attrs = cfg.stableRequestAttributes(r, cfg.handledRoutePattern(r), 0)
+				attrs = append(attrs, statusClassKey.String(statusClass(rrw.Status())))
+			} else {
+				attrs = cfg.stableRequestAttributes(r, cfg.handledRoutePattern(r), rrw.Status())
+			}
+			counter.Add(r.Context(), 1, cfg.withAttributes(attrs))
+		})
+	}, nil
+}
+
+// MustNewRequestCounter is like [NewRequestCounter] but panics when the metric instrument cannot
+// be created.
+func MustNewRequestCounter(cfg BaseConfig, opts ...RecorderOption) func(next http.Handler) http.Handler {
+	return must(NewRequestCounter(cfg, opts...))
+}
+
+// statusClass returns the class of the given status code, e.g. `2xx`.
+func statusClass(status int) string {
+	return fmt.Sprintf("%dxx", status/100)
+}
@@ -0,0 +1,87 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRequestCounter(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Options  []otelchimetric.RecorderOption
+		ExpAttrs []attribute.KeyValue
+	}{
+		{
+			Name: "Status Code",
+			ExpAttrs: []attribute.KeyValue{
+				attribute.String("http.route", "/user/{id}"),
+				attribute.String("http.request.method", "GET"),
+				attribute.Int("http.response.status_code", http.StatusNotFound),
+			},
+		},
+		{
+			Name:    "Status Class",
+			Options: []otelchimetric.RecorderOption{otelchimetric.WithStatusClass()},
+			ExpAttrs: []attribute.KeyValue{
+				attribute.String("http.route", "/user/{id}"),
+				attribute.String("http.request.method", "GET"),
+				attribute.String("http.response.status_class", "4xx"),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// setup environment
+			reader := sdkmetric.NewManualReader()
+			provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+			baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeterProvider(provider))
+			middleware := otelchimetric.MustNewRequestCounter(baseCfg, testCase.Options...)
+
+			router := chi.NewRouter()
+			router.Use(middleware)
+			router.Get("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			})
+
+			for i := 0; i < 3; i++ {
+				router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user/123", nil))
+			}
+
+			// read the recorded metrics
+			var rm metricdata.ResourceMetrics
+			err := reader.Collect(context.Background(), &rm)
+			require.NoError(t, err)
+			require.Len(t, rm.ScopeMetrics, 1)
+
+			metrics := rm.ScopeMetrics[0].Metrics
+			require.Len(t, metrics, 1)
+			assert.Equal(t, "http.server.request.count", metrics[0].Name)
+
+			sum, ok := metrics[0].Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+			require.Len(t, sum.DataPoints, 1)
+
+			dp := sum.DataPoints[0]
+			assert.Equal(t, int64(3), dp.Value)
+			for _, want := range testCase.ExpAttrs {
+				got, ok := dp.Attributes.Value(want.Key)
+				require.True(t, ok, want.Key)
+				assert.Equal(t, want.Value, got)
+			}
+			_, ok = dp.Attributes.Value(attribute.Key("http.response.status_class"))
+			assert.Equal(t, len(testCase.Options) > 0, ok)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package otelchimetric
+
+import (
+	"fmt"
+	"net/http"
+
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+const (
+	metricNameRequestDurationMs = "request_duration_millis"
+	metricUnitRequestDurationMs = "ms"
+	metricDescRequestDurationMs = "Measures the latency of HTTP requests processed by the server, in milliseconds."
+)
+
+// NewRequestDurationMillis is a metrics recorder for recording the request
+// duration in milliseconds. The histogram bucket boundaries could be customized
+// through [WithExplicitBucketBoundaries].
+func NewRequestDurationMillis(cfg BaseConfig, opts ...RecorderOption) (func(next http.Handler) http.Handler, error) {
+	recorderCfg := newRecorderConfig(opts)
+
+	// init metric, here we are using histogram for capturing request duration
+	histogramOpts := []otelmetric.Int64HistogramOption{
+		otelmetric.WithDescription(metricDescRequestDurationMs),
+		otelmetric.WithUnit(metricUnitRequestDurationMs),
+	}
+	if bounds := recorderCfg.bucketBoundariesOr(nil); bounds != nil {
+		histogramOpts = append(histogramOpts, otelmetric.WithExplicitBucketBoundaries(bounds...))
+	}
+	histogram, err := cfg.resolveMeter().Int64Histogram(cfg.metricName(metricNameRequestDurationMs), histogramOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s histogram: %w", metricNameRequestDurationMs, err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request
+			if cfg.skipRecording(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// capture the start time of the request
+			startTime := cfg.now()
+
+			// execute next http handler
+			next.ServeHTTP(w, r)
+
+			// record the request duration
+			duration := cfg.now().Sub(startTime)
+			histogram.Record(
+				r.Context(),
+				int64(duration.Milliseconds()),
+				cfg.withAttributes(cfg.requestAttributes(r, cfg.handledRoutePattern(r))),
+			)
+		})
+	}, nil
+}
+
+// MustNewRequestDurationMillis is like [NewRequestDurationMillis] but panics when the metric instrument cannot
+// be created.
+func MustNewRequestDurationMillis(cfg BaseConfig, opts ...RecorderOption) func(next http.Handler) http.Handler {
+	return must(NewRequestDurationMillis(cfg, opts...))
+}
@@ -0,0 +1,74 @@
+package otelchimetric
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/riandyrn/otelchi/otelchicore"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// requestDurationBucketBoundaries are the histogram bucket boundaries (in
+// seconds) recommended by the HTTP semantic conventions.
+var requestDurationBucketBoundaries = []float64{
+	0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10,
+}
+
+// NewRequestDurationSeconds is a metrics recorder for recording the request
+// duration as `http.server.request.duration` metric. Unlike
+// [NewRequestDurationMillis], the metric conforms to the stable HTTP semantic
+// conventions: it is recorded in seconds using the recommended bucket
+// boundaries, and it uses the stable attribute names (e.g.
+// `http.request.method`, `http.response.status_code`, `http.route`), so it
+// matches the metric emitted by otelhttp. The recommended bucket boundaries
+// could be overridden through [WithExplicitBucketBoundaries].
+func NewRequestDurationSeconds(cfg BaseConfig, opts ...RecorderOption) (func(next http.Handler) http.Handler, error) {
+	recorderCfg := newRecorderConfig(opts)
+
+	// init metric, here we are using histogram for capturing request duration
+	histogram, err := cfg.resolveMeter().Float64Histogram(
+		cfg.metricName(semconv.HTTPServerRequestDurationName),
+		otelmetric.WithDescription(semconv.HTTPServerRequestDurationDescription),
+		otelmetric.WithUnit(semconv.HTTPServerRequestDurationUnit),
+		otelmetric.WithExplicitBucketBoundaries(recorderCfg.bucketBoundariesOr(requestDurationBucketBoundaries)...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s histogram: %w", semconv.HTTPServerRequestDurationName, err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request
+			if cfg.skipRecording(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// capture the start time of the request
+			startTime := cfg.now()
+
+			// get recording response writer
+			rrw := otelchicore.AcquireResponseWriter(w)
+			defer rrw.Release()
+
+			// execute next http handler
+			next.ServeHTTP(rrw.Writer(), r)
+
+			// record the request duration, the route pattern is only
+			// available after the request is handled
+			duration := cfg.now().Sub(startTime)
+			histogram.Record(
+				r.Context(),
+				duration.Seconds(),
+				cfg.withAttributes(cfg.stableRequestAttributes(r, cfg.handledRoutePattern(r), rrw.Status())),
+			)
+		})
+	}, nil
+}
+
+// MustNewRequestDurationSeconds is like [NewRequestDurationSeconds] but panics when the metric instrument cannot
+// be created.
+func MustNewRequestDurationSeconds(cfg BaseConfig, opts ...RecorderOption) func(next http.Handler) http.Handler {
+	return must(NewRequestDurationSeconds(cfg, opts...))
+}
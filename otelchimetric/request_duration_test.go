@@ -1,4 +1,4 @@
-package metric_test
+package otelchimetric_test
 
 import (
 	"context"
@@ -8,7 +8,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/riandyrn/otelchi/metric"
+	"github.com/riandyrn/otelchi/otelchimetric"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
@@ -22,8 +22,8 @@ func TestRequestDurationMillis(t *testing.T) {
 	reader := sdkmetric.NewManualReader()
 	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
 
-	baseCfg := metric.NewBaseConfig("test-server", metric.WithMeterProvider(provider))
-	middleware := metric.NewRequestDurationMillis(baseCfg)
+	baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeterProvider(provider))
+	middleware := otelchimetric.MustNewRequestDurationMillis(baseCfg)
 
 	router := chi.NewRouter()
 	router.Use(middleware)
@@ -54,3 +54,33 @@ func TestRequestDurationMillis(t *testing.T) {
 	assert.GreaterOrEqual(t, dp.Sum, int64(expLatencyInMillis))
 	assert.Equal(t, uint64(1), dp.Count)
 }
+
+func TestRequestDurationMillisWithExplicitBucketBoundaries(t *testing.T) {
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeterProvider(provider))
+	middleware := otelchimetric.MustNewRequestDurationMillis(
+		baseCfg,
+		otelchimetric.WithExplicitBucketBoundaries(50, 100, 250, 500),
+	)
+
+	router := chi.NewRouter()
+	router.Use(middleware)
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	hist, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+	assert.Equal(t, []float64{50, 100, 250, 500}, hist.DataPoints[0].Bounds)
+}
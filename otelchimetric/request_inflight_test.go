@@ -0,0 +1,133 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRequestInflight(t *testing.T) {
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeterProvider(provider))
+	middleware := otelchimetric.MustNewRequestInFlight(baseCfg)
+
+	router := chi.NewRouter()
+	router.Use(middleware)
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		// the inflight request should be 1
+		require.Equal(t, int64(1), getCountRequestInFlight(t, reader))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+
+	// the inflight request should be 0
+	require.Equal(t, int64(0), getCountRequestInFlight(t, reader))
+
+	// execute the request
+	router.ServeHTTP(rec, req)
+
+	// the inflight request should be 0
+	require.Equal(t, int64(0), getCountRequestInFlight(t, reader))
+}
+
+func getCountRequestInFlight(t *testing.T, reader *sdkmetric.ManualReader) int64 {
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	if len(rm.ScopeMetrics) == 0 {
+		return 0
+	}
+
+	metrics := rm.ScopeMetrics[0].Metrics
+	if len(metrics) == 0 {
+		return 0
+	}
+
+	dps, ok := metrics[0].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	if len(dps.DataPoints) == 0 {
+		return 0
+	}
+
+	dp := dps.DataPoints[0]
+	return dp.Value
+}
+
+func TestRequestInflightWithChiRoutes(t *testing.T) {
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	router := chi.NewRouter()
+	baseCfg := otelchimetric.NewBaseConfig(
+		"test-server",
+		otelchimetric.WithMeterProvider(provider),
+		otelchimetric.WithChiRoutes(router),
+	)
+	router.Use(otelchimetric.MustNewRequestInFlight(baseCfg))
+	router.Get("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/user/123", nil)
+	req.Header.Set("User-Agent", "test-agent")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	sum, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+
+	// ensure the route is resolved & high-cardinality attributes are excluded
+	attrs := sum.DataPoints[0].Attributes
+	route, ok := attrs.Value(attribute.Key("http.route"))
+	require.True(t, ok)
+	require.Equal(t, "/user/{id}", route.AsString())
+	for _, key := range []attribute.Key{"net.sock.peer.addr", "net.sock.peer.port", "http.user_agent"} {
+		require.False(t, attrs.HasValue(key), "unexpected attribute: %s", key)
+	}
+}
+
+// failingMeter is a meter failing to create any up down counter.
+type failingMeter struct {
+	noop.Meter
+}
+
+func (failingMeter) Int64UpDownCounter(string, ...otelmetric.Int64UpDownCounterOption) (otelmetric.Int64UpDownCounter, error) {
+	return nil, errors.New("instrument limit exceeded")
+}
+
+func TestRequestInflightInstrumentError(t *testing.T) {
+	baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeter(failingMeter{}))
+
+	// ensure the error is returned instead of panicking
+	recorder, err := otelchimetric.NewRequestInFlight(baseCfg)
+	require.EqualError(t, err, "unable to create requests_inflight counter: instrument limit exceeded")
+	require.Nil(t, recorder)
+
+	// ensure the must variant panics with the same error
+	require.PanicsWithError(t, err.Error(), func() {
+		otelchimetric.MustNewRequestInFlight(baseCfg)
+	})
+}
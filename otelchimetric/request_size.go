@@ -0,0 +1,63 @@
+package otelchimetric
+
+import (
+	"fmt"
+	"net/http"
+
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+const (
+	metricNameRequestSize = "http.server.request.size"
+	metricUnitRequestSize = "By"
+	metricDescRequestSize = "Measures the size of HTTP request messages."
+)
+
+// NewRequestSizeBytes is a metrics recorder for recording the size of the
+// request body as `http.server.request.size` metric defined by the HTTP
+// semantic conventions v1.20.0. The size is taken from `Content-Length`
+// header when it is known, otherwise the number of bytes read by the handler
+// is used.
+//
+// The stable HTTP semantic conventions renamed the metric into
+// `http.server.request.body.size`, see [NewRequestBodySize].
+func NewRequestSizeBytes(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	// init metric, here we are using histogram for capturing request size
+	histogram, err := cfg.resolveMeter().Int64Histogram(
+		cfg.metricName(metricNameRequestSize),
+		otelmetric.WithDescription(metricDescRequestSize),
+		otelmetric.WithUnit(metricUnitRequestSize),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s histogram: %w", metricNameRequestSize, err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request
+			if cfg.skipRecording(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// count the bytes read from the request body
+			body := wrapBody(r)
+
+			// execute next http handler
+			next.ServeHTTP(w, r)
+
+			// record the request size
+			histogram.Record(
+				r.Context(),
+				requestBodySize(r, body),
+				cfg.withAttributes(cfg.requestAttributes(r, cfg.handledRoutePattern(r))),
+			)
+		})
+	}, nil
+}
+
+// MustNewRequestSizeBytes is like [NewRequestSizeBytes] but panics when the metric instrument cannot
+// be created.
+func MustNewRequestSizeBytes(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return must(NewRequestSizeBytes(cfg))
+}
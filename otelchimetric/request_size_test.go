@@ -0,0 +1,63 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRequestSizeBytes(t *testing.T) {
+	// setup environment
+	requestMsg := "Hello, Server!"
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeterProvider(provider))
+	middleware := otelchimetric.MustNewRequestSizeBytes(baseCfg)
+
+	router := chi.NewRouter()
+	router.Use(middleware)
+	router.Post("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// the content length is unknown, so the bytes read by the handler is used
+	req := httptest.NewRequest(http.MethodPost, "/user/123", strings.NewReader(requestMsg))
+	req.ContentLength = -1
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	metrics := rm.ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "http.server.request.size", metrics[0].Name)
+	assert.Equal(t, "By", metrics[0].Unit)
+
+	hist, ok := metrics[0].Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+
+	dp := hist.DataPoints[0]
+	assert.Equal(t, int64(len(requestMsg)), dp.Sum)
+
+	route, ok := dp.Attributes.Value(attribute.Key("http.route"))
+	require.True(t, ok)
+	assert.Equal(t, "/user/{id}", route.AsString())
+}
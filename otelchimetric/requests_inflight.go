@@ -1,11 +1,10 @@
-package metric
+package otelchimetric
 
 import (
 	"fmt"
 	"net/http"
 
 	otelmetric "go.opentelemetry.io/otel/metric"
-	"go.opentelemetry.io/otel/semconv/v1.20.0/httpconv"
 )
 
 const (
@@ -15,21 +14,27 @@ const (
 )
 
 // [RequestInFlight] is a metrics recorder for recording the number of requests in flight.
-func NewRequestInFlight(cfg BaseConfig) func(next http.Handler) http.Handler {
+func NewRequestInFlight(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
 	// init metric, here we are using counter for capturing request in flight
-	counter, err := cfg.Meter.Int64UpDownCounter(
-		metricNameRequestInFlight,
+	counter, err := cfg.resolveMeter().Int64UpDownCounter(
+		cfg.metricName(metricNameRequestInFlight),
 		otelmetric.WithDescription(metricDescRequestInFlight),
 		otelmetric.WithUnit(metricUnitRequestInFlight),
 	)
 	if err != nil {
-		panic(fmt.Sprintf("unable to create %s counter: %v", metricNameRequestInFlight, err))
+		return nil, fmt.Errorf("unable to create %s counter: %w", metricNameRequestInFlight, err)
 	}
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request
+			if cfg.skipRecording(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			// define metric attributes
-			attrs := otelmetric.WithAttributes(httpconv.ServerRequest(cfg.ServerName, r)...)
+			attrs := cfg.withAttributes(cfg.requestAttributes(r, cfg.routePattern(r)))
 
 			// increase the number of requests in flight
 			counter.Add(r.Context(), 1, attrs)
@@ -40,5 +45,11 @@ func NewRequestInFlight(cfg BaseConfig) func(next http.Handler) http.Handler {
 			// decrease the number of requests in flight
 			counter.Add(r.Context(), -1, attrs)
 		})
-	}
+	}, nil
+}
+
+// MustNewRequestInFlight is like [NewRequestInFlight] but panics when the metric instrument cannot
+// be created.
+func MustNewRequestInFlight(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return must(NewRequestInFlight(cfg))
 }
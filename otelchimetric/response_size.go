@@ -0,0 +1,84 @@
+package otelchimetric
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/riandyrn/otelchi/otelchicore"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+const (
+	metricNameResponseSizeBytes = "response_size_bytes"
+	metricUnitResponseSizeBytes = "By"
+	metricDescResponseSizeBytes = "Measures the size of the response in bytes."
+
+	metricNameResponseSize = "http.server.response.size"
+	metricUnitResponseSize = "By"
+	metricDescResponseSize = "Measures the size of HTTP response messages."
+)
+
+// NewResponseSizeBytes is a metrics recorder for recording the size of the
+// response body based on the semantic conventions mode set by
+// [WithSemconvVersion]: the legacy `response_size_bytes` metric is recorded
+// in [SemconvOld] mode, the `http.server.response.size` metric defined by the
+// HTTP semantic conventions v1.20.0 is recorded in [SemconvNew] mode, and
+// both are recorded in [SemconvDual] mode.
+//
+// The stable HTTP semantic conventions renamed the metric into
+// `http.server.response.body.size`, see [NewResponseBodySize].
+func NewResponseSizeBytes(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	// init metrics, here we are using histogram for capturing response size
+	var histograms []otelmetric.Int64Histogram
+	if cfg.semconvMode.EmitsOld() {
+		histogram, err := cfg.resolveMeter().Int64Histogram(
+			cfg.metricName(metricNameResponseSizeBytes),
+			otelmetric.WithDescription(metricDescResponseSizeBytes),
+			otelmetric.WithUnit(metricUnitResponseSizeBytes),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create %s histogram: %w", metricNameResponseSizeBytes, err)
+		}
+		histograms = append(histograms, histogram)
+	}
+	if cfg.semconvMode.EmitsNew() {
+		histogram, err := cfg.resolveMeter().Int64Histogram(
+			cfg.metricName(metricNameResponseSize),
+			otelmetric.WithDescription(metricDescResponseSize),
+			otelmetric.WithUnit(metricUnitResponseSize),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create %s histogram: %w", metricNameResponseSize, err)
+		}
+		histograms = append(histograms, histogram)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request
+			if cfg.skipRecording(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// get recording response writer
+			rrw := otelchicore.AcquireResponseWriter(w)
+			defer rrw.Release()
+
+			// execute next http handler
+			next.ServeHTTP(rrw.Writer(), r)
+
+			// record the response size
+			attrs := cfg.withAttributes(cfg.requestAttributes(r, cfg.handledRoutePattern(r)))
+			for _, histogram := range histograms {
+				histogram.Record(r.Context(), rrw.BytesWritten(), attrs)
+			}
+		})
+	}, nil
+}
+
+// MustNewResponseSizeBytes is like [NewResponseSizeBytes] but panics when the metric instrument cannot
+// be created.
+func MustNewResponseSizeBytes(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return must(NewResponseSizeBytes(cfg))
+}
@@ -0,0 +1,136 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestResponseSizeBytes(t *testing.T) {
+	// setup environment
+	responseMsg := "Hello, World!"
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeterProvider(provider))
+	middleware := otelchimetric.MustNewResponseSizeBytes(baseCfg)
+
+	router := chi.NewRouter()
+	router.Use(middleware)
+	router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(responseMsg))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	metrics := rm.ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 1)
+
+	hist, ok := metrics[0].Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+
+	dp := hist.DataPoints[0]
+	assert.Equal(t, int64(len(responseMsg)), dp.Sum)
+	assert.Equal(t, uint64(1), dp.Count)
+}
+
+func TestResponseSizeBytesSemconvMode(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Mode     otelchimetric.SemconvMode
+		ExpNames []string
+	}{
+		{Name: "Old", Mode: otelchimetric.SemconvOld, ExpNames: []string{"response_size_bytes"}},
+		{Name: "New", Mode: otelchimetric.SemconvNew, ExpNames: []string{"http.server.response.size"}},
+		{Name: "Dual", Mode: otelchimetric.SemconvDual, ExpNames: []string{"response_size_bytes", "http.server.response.size"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// setup environment
+			reader := sdkmetric.NewManualReader()
+			provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+			baseCfg := otelchimetric.NewBaseConfig(
+				"test-server",
+				otelchimetric.WithMeterProvider(provider),
+				otelchimetric.WithSemconvVersion(testCase.Mode),
+			)
+			router := chi.NewRouter()
+			router.Use(otelchimetric.MustNewResponseSizeBytes(baseCfg))
+			router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("Hello, World!"))
+			})
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			// read the recorded metrics
+			var rm metricdata.ResourceMetrics
+			require.NoError(t, reader.Collect(context.Background(), &rm))
+			require.Len(t, rm.ScopeMetrics, 1)
+
+			metrics := rm.ScopeMetrics[0].Metrics
+			require.Len(t, metrics, len(testCase.ExpNames))
+			for i, name := range testCase.ExpNames {
+				assert.Equal(t, name, metrics[i].Name)
+				assert.Equal(t, "By", metrics[i].Unit)
+				hist, ok := metrics[i].Data.(metricdata.Histogram[int64])
+				require.True(t, ok)
+				require.Len(t, hist.DataPoints, 1)
+				assert.Equal(t, int64(len("Hello, World!")), hist.DataPoints[0].Sum)
+			}
+		})
+	}
+}
+
+func TestResponseSizeBytesPreservesInterfaces(t *testing.T) {
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	baseCfg := otelchimetric.NewBaseConfig("test-server", otelchimetric.WithMeterProvider(provider))
+	middleware := otelchimetric.MustNewResponseSizeBytes(baseCfg)
+
+	recorder := httptest.NewRecorder()
+	router := chi.NewRouter()
+	router.Use(middleware)
+	router.Get("/stream", func(w http.ResponseWriter, r *http.Request) {
+		// server-sent events style response
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 3; i++ {
+			_, _ = w.Write([]byte("data: ping\n\n"))
+			require.NoError(t, http.NewResponseController(w).Flush())
+		}
+	})
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	assert.True(t, recorder.Flushed)
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	hist, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+	assert.Equal(t, int64(3*len("data: ping\n\n")), hist.DataPoints[0].Sum)
+}
@@ -0,0 +1,63 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRouteAttributes(t *testing.T) {
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	baseCfg := otelchimetric.NewBaseConfig(
+		"test-server",
+		otelchimetric.WithMeterProvider(provider),
+		otelchimetric.WithRouteAttributes(map[string][]attribute.KeyValue{
+			"/admin/*":     {attribute.String("team", "platform")},
+			"/admin/audit": {attribute.String("team", "security")},
+		}),
+	)
+
+	router := chi.NewRouter()
+	router.Use(otelchimetric.MustNewRequestCounter(baseCfg))
+	for _, pattern := range []string{"/admin/users/{id}", "/admin/audit", "/users/{id}"} {
+		router.Get(pattern, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+	}
+
+	// execute requests
+	for _, path := range []string{"/admin/users/1", "/admin/audit", "/users/1"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// ensure the attributes are added to the metrics of the matching routes
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	sum, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	teams := map[string]string{}
+	for _, dp := range sum.DataPoints {
+		route, _ := dp.Attributes.Value("http.route")
+		team, _ := dp.Attributes.Value("team")
+		teams[route.AsString()] = team.AsString()
+	}
+	assert.Equal(t, map[string]string{
+		"/admin/users/{id}": "platform",
+		"/admin/audit":      "security",
+		"/users/{id}":       "",
+	}, teams)
+}
@@ -0,0 +1,85 @@
+package otelchimetric
+
+import (
+	"net/http"
+
+	"github.com/riandyrn/otelchi/otelchicore"
+)
+
+// SemconvMode determines which version of the HTTP semantic conventions is
+// used by the recorders depending on it (e.g. [NewRequestDuration]), it is
+// the same type as `otelchitrace.SemconvMode`, so the tracing middleware & the
+// metric recorders could share the same mode.
+type SemconvMode = otelchicore.SemconvMode
+
+const (
+	// SemconvOld emits the legacy metrics, e.g. `request_duration_millis`.
+	// This is the default mode.
+	SemconvOld = otelchicore.SemconvOld
+	// SemconvNew emits the metrics defined by the stable HTTP semantic
+	// conventions, e.g. `http.server.request.duration`.
+	SemconvNew = otelchicore.SemconvNew
+	// SemconvDual emits both legacy & stable metrics, it is useful during
+	// the migration of dashboards & alerts.
+	SemconvDual = otelchicore.SemconvDual
+)
+
+// WithSemconvVersion specifies which version of the HTTP semantic conventions
+// is used by the recorders depending on it, e.g:
+//
+//	mode := otelchitrace.SemconvDual
+//	r.Use(
+//		otelchitrace.Middleware("my-server", otelchitrace.WithSemconvVersion(mode)),
+//		otelchimetric.MustNewRequestDuration(otelchimetric.NewBaseConfig("my-server", otelchimetric.WithSemconvVersion(mode))),
+//	)
+//
+// If this option is not set, the mode is determined by the
+// `OTEL_SEMCONV_STABILITY_OPT_IN` environment variable like the tracing
+// middleware: `http` selects [SemconvNew], `http/dup` selects [SemconvDual],
+// otherwise [SemconvOld] is used.
+func WithSemconvVersion(mode SemconvMode) Option {
+	return optionFunc(func(cfg *BaseConfig) {
+		cfg.semconvMode = mode
+	})
+}
+
+// NewRequestDuration is a metrics recorder for recording the request
+// duration based on the semantic conventions mode set by
+// [WithSemconvVersion]: the legacy `request_duration_millis` metric (see
+// [NewRequestDurationMillis]) is recorded in [SemconvOld] mode, the stable
+// `http.server.request.duration` metric in seconds (see
+// [NewRequestDurationSeconds]) is recorded in [SemconvNew] mode, and both
+// are recorded in [SemconvDual] mode.
+//
+// The given options only apply to the stable metric, e.g. the bucket
+// boundaries set by [WithExplicitBucketBoundaries] are in seconds.
+func NewRequestDuration(cfg BaseConfig, opts ...RecorderOption) (func(next http.Handler) http.Handler, error) {
+	var recorders []func(next http.Handler) http.Handler
+	if cfg.semconvMode.EmitsOld() {
+		recorder, err := NewRequestDurationMillis(cfg)
+		if err != nil {
+			return nil, err
+		}
+		recorders = append(recorders, recorder)
+	}
+	if cfg.semconvMode.EmitsNew() {
+		recorder, err := NewRequestDurationSeconds(cfg, opts...)
+		if err != nil {
+			return nil, err
+		}
+		recorders = append(recorders, recorder)
+	}
+
+	return func(next http.Handler) http.Handler {
+		for i := len(recorders) - 1; i >= 0; i-- {
+			next = recorders[i](next)
+		}
+		return next
+	}, nil
+}
+
+// MustNewRequestDuration is like [NewRequestDuration] but panics when the
+// metric instrument cannot be created.
+func MustNewRequestDuration(cfg BaseConfig, opts ...RecorderOption) func(next http.Handler) http.Handler {
+	return must(NewRequestDuration(cfg, opts...))
+}
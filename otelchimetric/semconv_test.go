@@ -0,0 +1,79 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/riandyrn/otelchi/otelchitrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRequestDurationSemconvMode(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Options     []otelchimetric.Option
+		Env         string
+		WantMetrics []string
+	}{
+		{
+			Name:        "Default",
+			WantMetrics: []string{"request_duration_millis"},
+		},
+		{
+			Name:        "New",
+			Options:     []otelchimetric.Option{otelchimetric.WithSemconvVersion(otelchimetric.SemconvNew)},
+			WantMetrics: []string{"http.server.request.duration"},
+		},
+		{
+			Name:        "Dual Shared With Tracing",
+			Options:     []otelchimetric.Option{otelchimetric.WithSemconvVersion(otelchitrace.SemconvDual)},
+			WantMetrics: []string{"http.server.request.duration", "request_duration_millis"},
+		},
+		{
+			Name:        "New From Env",
+			Env:         "http",
+			WantMetrics: []string{"http.server.request.duration"},
+		},
+		{
+			Name:        "Option Overrides Env",
+			Options:     []otelchimetric.Option{otelchimetric.WithSemconvVersion(otelchimetric.SemconvOld)},
+			Env:         "http/dup",
+			WantMetrics: []string{"request_duration_millis"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Setenv("OTEL_SEMCONV_STABILITY_OPT_IN", testCase.Env)
+
+			// setup environment
+			reader := sdkmetric.NewManualReader()
+			provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			opts := append([]otelchimetric.Option{otelchimetric.WithMeterProvider(provider)}, testCase.Options...)
+			baseCfg := otelchimetric.NewBaseConfig("test-server", opts...)
+
+			router := chi.NewRouter()
+			router.Use(otelchimetric.MustNewRequestDuration(baseCfg))
+			router.Get("/test", func(w http.ResponseWriter, r *http.Request) {})
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			// ensure only the metrics of the selected mode are recorded
+			var rm metricdata.ResourceMetrics
+			require.NoError(t, reader.Collect(context.Background(), &rm))
+			require.Len(t, rm.ScopeMetrics, 1)
+			var names []string
+			for _, m := range rm.ScopeMetrics[0].Metrics {
+				names = append(names, m.Name)
+			}
+			sort.Strings(names)
+			assert.Equal(t, testCase.WantMetrics, names)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestShadowTraffic(t *testing.T) {
+	// define test cases
+	testCases := []struct {
+		Name          string
+		Exclude       bool
+		ExpCount      uint64
+		ExpShadowTags int
+	}{
+		{
+			Name:          "Tag Shadow Requests",
+			Exclude:       false,
+			ExpCount:      2,
+			ExpShadowTags: 1,
+		},
+		{
+			Name:          "Exclude Shadow Requests",
+			Exclude:       true,
+			ExpCount:      1,
+			ExpShadowTags: 0,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// setup environment
+			reader := sdkmetric.NewManualReader()
+			provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+			baseCfg := otelchimetric.NewBaseConfig(
+				"test-server",
+				otelchimetric.WithMeterProvider(provider),
+				otelchimetric.WithShadowTraffic(nil, testCase.Exclude),
+			)
+			middleware := otelchimetric.MustNewRequestDurationMillis(baseCfg)
+
+			router := chi.NewRouter()
+			router.Use(middleware)
+			router.Get("/test", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			// execute both production & shadow requests
+			shadowReq := httptest.NewRequest(http.MethodGet, "/test", nil)
+			shadowReq.Header.Set("X-Shadow-Request", "true")
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+			router.ServeHTTP(httptest.NewRecorder(), shadowReq)
+
+			// read the recorded metrics
+			var rm metricdata.ResourceMetrics
+			err := reader.Collect(context.Background(), &rm)
+			require.NoError(t, err)
+			require.Len(t, rm.ScopeMetrics, 1)
+
+			hist, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[int64])
+			require.True(t, ok)
+
+			var count uint64
+			var shadowTags int
+			for _, dp := range hist.DataPoints {
+				count += dp.Count
+				if v, ok := dp.Attributes.Value(attribute.Key("request.shadow")); ok && v.AsBool() {
+					shadowTags++
+				}
+			}
+			assert.Equal(t, testCase.ExpCount, count)
+			assert.Equal(t, testCase.ExpShadowTags, shadowTags)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package otelchimetric
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/riandyrn/otelchi/otelchicore"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	throttledRequestsName        = "http.server.throttled_requests"
+	throttledRequestsDescription = "Number of HTTP server requests rejected by throttling or rate limiting."
+	throttledRequestsUnit        = "{request}"
+)
+
+const (
+	// ThrottledKey is the span attribute key marking the request rejected by
+	// throttling or rate limiting, it is recorded by [NewThrottleObserver].
+	ThrottledKey = attribute.Key("http.server.throttled")
+
+	// RetryAfterKey is the span attribute key of the delay (in seconds)
+	// advertised by the `Retry-After` header of the throttled response, it
+	// is recorded by [NewThrottleObserver].
+	RetryAfterKey = attribute.Key("http.response.retry_after")
+)
+
+// NewThrottleObserver is a metrics recorder for counting the requests
+// rejected by throttling or rate limiting as `http.server.throttled_requests`
+// metric, keyed by the same attributes as [NewRequestCounter] (e.g.
+// `http.route`, `http.request.method`). The request is considered throttled
+// when the response status is `429 Too Many Requests`, or `503 Service
+// Unavailable` having `Retry-After` header.
+//
+// The span in the request context (e.g. the one started by otelchi tracing
+// middleware) is marked with `http.server.throttled=true` attribute as well,
+// along with `http.response.retry_after` attribute when the response has
+// `Retry-After` header.
+func NewThrottleObserver(cfg BaseConfig) (func(next http.Handler) http.Handler, error) {
+	// init metric, here we are using counter for counting the throttled requests
+	counter, err := cfg.resolveMeter().Int64Counter(
+		cfg.metricName(throttledRequestsName),
+		otelmetric.WithDescription(throttledRequestsDescription),
+		otelmetric.WithUnit(throttledRequestsUnit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s counter: %w", throttledRequestsName, err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// skip recording excluded request
+			if cfg.skipRecording(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// get recording response writer
+			rrw := otelchicore.AcquireResponseWriter(w)
+			defer rrw.Release()
+
+			// execute next http handler
+			next.ServeHTTP(rrw.Writer(), r)
+
+			retryAfter, hasRetryAfter := parseRetryAfter(w.Header().Get("Retry-After"), cfg.now())
+			throttled := rrw.Status() == http.StatusTooManyRequests ||
+				(rrw.Status() == http.StatusServiceUnavailable && hasRetryAfter)
+			if !throttled {
+				return
+			}
+
+			// annotate the span of the request
+			spanAttrs := []attribute.KeyValue{ThrottledKey.Bool(true)}
+			if hasRetryAfter {
+				spanAttrs = append(spanAttrs, RetryAfterKey.Int64(retryAfter))
+			}
+			oteltrace.SpanFromContext(r.Context()).SetAttributes(spanAttrs...)
+
+			// count the throttled request, the route pattern is only
+			// available after the request is handled
+			attrs := cfg.stableRequestAttributes(r, cfg.handledRoutePattern(r), rrw.Status())
+			counter.Add(r.Context(), 1, cfg.withAttributes(attrs))
+		})
+	}, nil
+}
+
+// MustNewThrottleObserver is like [NewThrottleObserver] but panics when the metric instrument cannot
+// be created.
+func MustNewThrottleObserver(cfg BaseConfig) func(next http.Handler) http.Handler {
+	return must(NewThrottleObserver(cfg))
+}
+
+// parseRetryAfter returns the delay in seconds advertised by the given
+// `Retry-After` header value, which is either the number of seconds or the
+// HTTP date. It returns false when the value is absent or invalid.
+func parseRetryAfter(value string, now time.Time) (int64, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return max(seconds, 0), true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	// round up the partial second so the client doesn't retry too early
+	delay := date.Sub(now)
+	seconds := int64(delay / time.Second)
+	if delay%time.Second > 0 {
+		seconds++
+	}
+	return max(seconds, 0), true
+}
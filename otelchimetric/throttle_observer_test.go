@@ -0,0 +1,91 @@
+package otelchimetric_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestThrottleObserver(t *testing.T) {
+	// setup environment
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test")
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	baseCfg := otelchimetric.NewBaseConfig(
+		"test-server",
+		otelchimetric.WithMeterProvider(provider),
+		otelchimetric.WithClock(clock),
+	)
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		// start the span like the tracing middleware
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := tracer.Start(r.Context(), r.URL.Path)
+			defer span.End()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	router.Use(otelchimetric.MustNewThrottleObserver(baseCfg))
+	router.Get("/limited/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	router.Get("/maintenance", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", clock.Now().Add(1500*time.Millisecond).Format(http.TimeFormat))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	router.Get("/unavailable", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	router.Get("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute requests
+	for _, path := range []string{"/limited/1", "/limited/2", "/maintenance", "/unavailable", "/ok"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// ensure only the throttled requests are counted by route
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+
+	m := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "http.server.throttled_requests", m.Name)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	counts := map[string]int64{}
+	for _, dp := range sum.DataPoints {
+		route, _ := dp.Attributes.Value("http.route")
+		counts[route.AsString()] += dp.Value
+	}
+	assert.Equal(t, map[string]int64{"/limited/{id}": 2, "/maintenance": 1}, counts)
+
+	// ensure the spans of the throttled requests are annotated
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 5)
+	assert.Contains(t, spans[0].Attributes(), otelchimetric.RetryAfterKey.Int64(30))
+	assert.Contains(t, spans[2].Attributes(), otelchimetric.ThrottledKey.Bool(true))
+	assert.Contains(t, spans[2].Attributes(), otelchimetric.RetryAfterKey.Int64(1))
+	for _, span := range spans[3:] {
+		assert.NotContains(t, span.Attributes(), attribute.Bool("http.server.throttled", true))
+	}
+}
@@ -1,4 +1,4 @@
-package metric
+package otelchimetric
 
 // Version is the current release version of metrics package.
 func Version() string {
@@ -0,0 +1,93 @@
+// Package otelchitest provides helpers for testing that the routes
+// instrumented by otelchi produce the expected spans.
+package otelchitest
+
+import (
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServerName is the server name used by the middleware installed by
+// NewRecordingRouter.
+const ServerName = "otelchitest"
+
+// NewRecordingRouter returns a chi router instrumented by the otelchi
+// middleware along with the span recorder receiving the spans it produces.
+//
+// The middleware uses a tracer provider which samples every span & is aware
+// of the chi routes of the returned router, the given options are applied on
+// top of it.
+func NewRecordingRouter(opts ...otelchi.Option) (*chi.Mux, *tracetest.SpanRecorder) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanProcessor(spanRecorder),
+	)
+
+	router := chi.NewRouter()
+	opts = append([]otelchi.Option{
+		otelchi.WithTracerProvider(tracerProvider),
+		otelchi.WithChiRoutes(router),
+	}, opts...)
+	router.Use(otelchi.Middleware(ServerName, opts...))
+
+	return router, spanRecorder
+}
+
+// SpanWant describes the expected values of a span.
+type SpanWant struct {
+	// Name is the expected span name.
+	Name string
+	// Kind is the expected span kind.
+	Kind trace.SpanKind
+	// Status is the expected span status code.
+	Status codes.Code
+	// Attributes are the attributes expected to be found in the span, the
+	// span may contain other attributes as well.
+	Attributes []attribute.KeyValue
+}
+
+// AssertSpan asserts that the span matches the wanted values. It returns
+// whether the assertion succeeded.
+func AssertSpan(t testing.TB, span sdktrace.ReadOnlySpan, want SpanWant) bool {
+	t.Helper()
+
+	ok := assert.Equal(t, want.Name, span.Name())
+	ok = assert.Equal(t, want.Kind, span.SpanKind()) && ok
+	ok = assert.Equal(t, want.Status, span.Status().Code) && ok
+
+	got := make(map[attribute.Key]attribute.Value, len(span.Attributes()))
+	for _, a := range span.Attributes() {
+		got[a.Key] = a.Value
+	}
+	for _, attr := range want.Attributes {
+		if !assert.Contains(t, got, attr.Key) {
+			ok = false
+			continue
+		}
+		ok = assert.Equal(t, attr.Value, got[attr.Key]) && ok
+	}
+	return ok
+}
+
+// AssertSpans asserts that the spans match the wanted values in order.
+func AssertSpans(t testing.TB, spans []sdktrace.ReadOnlySpan, wants ...SpanWant) bool {
+	t.Helper()
+
+	if !assert.Len(t, spans, len(wants)) {
+		return false
+	}
+	ok := true
+	for i, span := range spans {
+		ok = AssertSpan(t, span, wants[i]) && ok
+	}
+	return ok
+}
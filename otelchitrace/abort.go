@@ -0,0 +1,46 @@
+package otelchitrace
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// RequestAbortedKey is the attribute key used for marking requests which
+	// context is canceled before the handler returns, e.g. because the client
+	// disconnected or the handler timed out.
+	RequestAbortedKey = attribute.Key("http.request.aborted")
+
+	// RequestAbortedEventName is the name of the span event recording the
+	// cause of the aborted request.
+	RequestAbortedEventName = "http.request.aborted"
+
+	// abortCauseKey is the attribute key of the cancellation cause recorded in
+	// the aborted request event.
+	abortCauseKey = attribute.Key("http.request.abort_cause")
+)
+
+// recordAborted records the cancellation of the request context into the
+// span, it returns the span status which should be used for the aborted
+// request. The ok is false when the request is not aborted.
+func recordAborted(ctx context.Context, span oteltrace.Span, eventOpts ...oteltrace.EventOption) (code codes.Code, description string, ok bool) {
+	if ctx.Err() == nil {
+		return codes.Unset, "", false
+	}
+	cause := context.Cause(ctx)
+
+	span.SetAttributes(RequestAbortedKey.Bool(true))
+	span.AddEvent(RequestAbortedEventName, append(eventOpts, oteltrace.WithAttributes(abortCauseKey.String(cause.Error())))...)
+
+	if errors.Is(cause, context.DeadlineExceeded) {
+		// the server gave up on the request, this is a server failure
+		return codes.Error, "request timed out", true
+	}
+	// the client disconnected, the error response (if any) is not caused by
+	// the server failure
+	return codes.Unset, "", true
+}
@@ -0,0 +1,129 @@
+package otelchitrace
+
+import (
+	"context"
+
+	"github.com/riandyrn/otelchi/internal/attrfilter"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// WithAttributeFilter specifies the function deciding whether the attribute
+// emitted by the middleware is kept, it is applied to every attribute of the
+// spans (including the span events & links) and metrics recorded by the
+// middleware regardless of which option adds them. This allows platform
+// teams to centrally drop high-cardinality or PII attributes, e.g:
+//
+//	otelchitrace.WithAttributeFilter(func(kv attribute.KeyValue) bool {
+//		return kv.Key != "user_agent.original"
+//	})
+//
+// The attributes set by the handlers on the span taken from the request
+// context are not filtered. Use `otelchimetric.WithAttributeFilter` for the
+// metric recorders.
+func WithAttributeFilter(fn func(attribute.KeyValue) bool) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.attributeFilter = fn
+	})
+}
+
+// filteringTracer is the tracer applying the attribute filter & the value
+// length limit to the spans it starts.
+type filteringTracer struct {
+	oteltrace.Tracer
+	keep  attrfilter.Fn
+	limit int
+}
+
+var _ oteltrace.Tracer = filteringTracer{}
+
+// Start starts the span with the filtered attributes, the returned context
+// holds the underlying span while the returned span applies the filter.
+func (t filteringTracer) Start(ctx context.Context, spanName string, opts ...oteltrace.SpanStartOption) (context.Context, oteltrace.Span) {
+	cfg := oteltrace.NewSpanStartConfig(opts...)
+	var trunc *truncation
+	if t.limit > 0 {
+		trunc = &truncation{limit: t.limit}
+	}
+	links := t.filterLinks(trunc, cfg.Links())
+	attrs, truncatedAttr, truncated := trunc.apply(attrfilter.Apply(t.keep, cfg.Attributes()))
+	if truncated {
+		attrs = append(attrs, truncatedAttr)
+	}
+	filteredOpts := []oteltrace.SpanStartOption{
+		oteltrace.WithAttributes(attrs...),
+		oteltrace.WithLinks(links...),
+		oteltrace.WithSpanKind(cfg.SpanKind()),
+	}
+	if cfg.NewRoot() {
+		filteredOpts = append(filteredOpts, oteltrace.WithNewRoot())
+	}
+	if !cfg.Timestamp().IsZero() {
+		filteredOpts = append(filteredOpts, oteltrace.WithTimestamp(cfg.Timestamp()))
+	}
+	ctx, span := t.Tracer.Start(ctx, spanName, filteredOpts...)
+	return ctx, filteringSpan{Span: span, keep: t.keep, trunc: trunc}
+}
+
+// filterLinks applies the filter & the truncation to the attributes of the
+// given links, the truncation of the link attributes is listed by the
+// attributes of the span.
+func (t filteringTracer) filterLinks(trunc *truncation, links []oteltrace.Link) []oteltrace.Link {
+	for i := range links {
+		links[i].Attributes, _, _ = trunc.apply(attrfilter.Apply(t.keep, links[i].Attributes))
+	}
+	return links
+}
+
+// filteringSpan is the span applying the attribute filter & the value length
+// limit.
+type filteringSpan struct {
+	oteltrace.Span
+	keep  attrfilter.Fn
+	trunc *truncation
+}
+
+var _ oteltrace.Span = filteringSpan{}
+
+func (s filteringSpan) SetAttributes(kv ...attribute.KeyValue) {
+	attrs, truncatedAttr, truncated := s.trunc.apply(attrfilter.Apply(s.keep, kv))
+	if truncated {
+		attrs = append(attrs, truncatedAttr)
+	}
+	s.Span.SetAttributes(attrs...)
+}
+
+// process applies the filter & the truncation to the given attributes of the
+// span event or link, the truncation is listed by the attributes of the span.
+func (s filteringSpan) process(kv []attribute.KeyValue) []attribute.KeyValue {
+	attrs, truncatedAttr, truncated := s.trunc.apply(attrfilter.Apply(s.keep, kv))
+	if truncated {
+		s.Span.SetAttributes(truncatedAttr)
+	}
+	return attrs
+}
+
+func (s filteringSpan) AddEvent(name string, options ...oteltrace.EventOption) {
+	s.Span.AddEvent(name, s.filterEventOptions(options)...)
+}
+
+func (s filteringSpan) RecordError(err error, options ...oteltrace.EventOption) {
+	s.Span.RecordError(err, s.filterEventOptions(options)...)
+}
+
+func (s filteringSpan) AddLink(link oteltrace.Link) {
+	link.Attributes = s.process(link.Attributes)
+	s.Span.AddLink(link)
+}
+
+func (s filteringSpan) filterEventOptions(options []oteltrace.EventOption) []oteltrace.EventOption {
+	cfg := oteltrace.NewEventConfig(options...)
+	filtered := []oteltrace.EventOption{
+		oteltrace.WithAttributes(s.process(cfg.Attributes())...),
+		oteltrace.WithStackTrace(cfg.StackTrace()),
+	}
+	if !cfg.Timestamp().IsZero() {
+		filtered = append(filtered, oteltrace.WithTimestamp(cfg.Timestamp()))
+	}
+	return filtered
+}
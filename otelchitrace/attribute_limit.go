@@ -0,0 +1,137 @@
+package otelchitrace
+
+import (
+	"sync"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TruncatedAttributesKey is the span attribute key listing the keys of the
+// attributes truncated by `WithAttributeValueLengthLimit`.
+const TruncatedAttributesKey = attribute.Key("otelchi.truncated_attributes")
+
+// truncationMarker is appended to the truncated attribute values.
+const truncationMarker = "..."
+
+// WithAttributeValueLengthLimit limits the length (in characters) of every
+// string attribute value emitted by the middleware on the spans (including
+// the span events & links), e.g. the user agent, the URL & the captured
+// headers. The longer values are truncated to the limit followed by `...`
+// marker, and the keys of the truncated attributes are listed in
+// `otelchi.truncated_attributes` span attribute. This protects the collector
+// payload limits from the abusive requests carrying huge values.
+//
+// The attributes set by the handlers on the span taken from the request
+// context are not truncated, use the SDK span limits for them.
+func WithAttributeValueLengthLimit(n int) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.attributeValueLengthLimit = n
+	})
+}
+
+// truncation truncates the attribute values of a span & remembers the keys
+// of the truncated attributes.
+type truncation struct {
+	limit int
+
+	mu   sync.Mutex
+	keys []string
+}
+
+// apply returns the given attributes with the values exceeding the limit
+// truncated, it returns true along with the attribute listing all truncated
+// keys of the span when any new key is truncated. The given slice is never
+// modified.
+func (t *truncation) apply(attrs []attribute.KeyValue) ([]attribute.KeyValue, attribute.KeyValue, bool) {
+	if t == nil {
+		return attrs, attribute.KeyValue{}, false
+	}
+	var (
+		res     []attribute.KeyValue
+		newKeys bool
+	)
+	for i, attr := range attrs {
+		truncated, ok := t.truncate(attr)
+		if !ok {
+			if res != nil {
+				res = append(res, attr)
+			}
+			continue
+		}
+		// copy the attributes only once the first one is truncated
+		if res == nil {
+			res = make([]attribute.KeyValue, i, len(attrs))
+			copy(res, attrs[:i])
+		}
+		res = append(res, truncated)
+		if t.remember(string(attr.Key)) {
+			newKeys = true
+		}
+	}
+	if res == nil {
+		return attrs, attribute.KeyValue{}, false
+	}
+	if !newKeys {
+		return res, attribute.KeyValue{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return res, TruncatedAttributesKey.StringSlice(append([]string(nil), t.keys...)), true
+}
+
+// truncate returns the given attribute with its value truncated, it returns
+// false when the value doesn't exceed the limit.
+func (t *truncation) truncate(attr attribute.KeyValue) (attribute.KeyValue, bool) {
+	switch attr.Value.Type() {
+	case attribute.STRING:
+		value, ok := truncateString(attr.Value.AsString(), t.limit)
+		if ok {
+			return attr.Key.String(value), true
+		}
+	case attribute.STRINGSLICE:
+		values := attr.Value.AsStringSlice()
+		truncated := false
+		for i, v := range values {
+			if value, ok := truncateString(v, t.limit); ok {
+				values[i] = value
+				truncated = true
+			}
+		}
+		if truncated {
+			return attr.Key.StringSlice(values), true
+		}
+	}
+	return attr, false
+}
+
+// remember adds the given key to the truncated keys, it returns false when
+// the key has been truncated before.
+func (t *truncation) remember(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, k := range t.keys {
+		if k == key {
+			return false
+		}
+	}
+	t.keys = append(t.keys, key)
+	return true
+}
+
+// truncateString returns the first limit characters of the given string
+// followed by the truncation marker, it returns false when the string doesn't
+// exceed the limit.
+func truncateString(s string, limit int) (string, bool) {
+	if len(s) <= limit || utf8.RuneCountInString(s) <= limit {
+		return s, false
+	}
+	n := 0
+	for i := range s {
+		if n == limit {
+			return s[:i] + truncationMarker, true
+		}
+		n++
+	}
+	return s, false
+}
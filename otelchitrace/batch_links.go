@@ -0,0 +1,174 @@
+package otelchitrace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// BatchItemIndexKey is the link attribute key of the index of the batch item
+// carrying the linked trace context, it is recorded on the links parsed by
+// `WithBatchLinksFromJSON`.
+const BatchItemIndexKey = attribute.Key("http.request.batch.item_index")
+
+// traceparentKey is the key of the span context in W3C trace context format
+// in the carrier.
+const traceparentKey = "traceparent"
+
+// maxBatchLinks is the maximum number of links parsed from a single request,
+// it matches the default link count limit of the OpenTelemetry SDK.
+const maxBatchLinks = 128
+
+// LinkFromCarrier links the given span to the span context carried by the
+// given carrier in W3C trace context format (i.e. `traceparent` &
+// `tracestate` keys), e.g. the trace context of every item of the batch
+// payload processed by the handler:
+//
+//	for _, item := range batch.Items {
+//		otelchitrace.LinkFromCarrier(span, propagation.MapCarrier{
+//			"traceparent": item.Traceparent,
+//		})
+//	}
+//
+// It returns false when the carrier doesn't hold a valid span context, in
+// which case no link is added.
+func LinkFromCarrier(span oteltrace.Span, carrier propagation.TextMapCarrier, attrs ...attribute.KeyValue) bool {
+	link, ok := linkFromCarrier(carrier, attrs...)
+	if !ok {
+		return false
+	}
+	span.AddLink(link)
+	return true
+}
+
+// linkFromCarrier returns the link to the span context carried by the given
+// carrier in W3C trace context format.
+func linkFromCarrier(carrier propagation.TextMapCarrier, attrs ...attribute.KeyValue) (oteltrace.Link, bool) {
+	spanCtx := oteltrace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+	if !spanCtx.IsValid() {
+		return oteltrace.Link{}, false
+	}
+	return oteltrace.Link{SpanContext: spanCtx, Attributes: attrs}, true
+}
+
+// WithBatchLinksFromHeaders makes the span of the request linked to the trace
+// contexts carried by the given request headers, each value (or each comma
+// separated element of the value) of the headers is a `traceparent`, e.g. the
+// trace contexts of the events fanned in by the webhook sender. The links are
+// added when the span is started, so they are visible to the sampler.
+func WithBatchLinksFromHeaders(headers ...string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.batchLinkHeaders = append(cfg.batchLinkHeaders, headers...)
+	})
+}
+
+// WithBatchLinksFromJSON makes the span of the request having JSON body
+// linked to the trace contexts carried by the items of the batch payload,
+// e.g. for OTLP-like ingestion endpoints. The body is either the array of
+// items or a single item, the `traceparent` of each item is taken from the
+// given field, which could be the dot separated path of the nested field
+// (e.g. `meta.traceparent`). Each link records the index of its item as
+// `http.request.batch.item_index` attribute.
+//
+// The body is read before the span is started (at most maxBytes bytes), it
+// is restored for the handler. The body exceeding maxBytes is not parsed.
+func WithBatchLinksFromJSON(field string, maxBytes int) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.batchLinkJSONField = strings.Split(field, ".")
+		cfg.batchLinkJSONMaxBytes = maxBytes
+	})
+}
+
+// batchLinks returns the links to the trace contexts carried by the request
+// as configured by `WithBatchLinksFromHeaders` & `WithBatchLinksFromJSON`.
+func (tw traceware) batchLinks(r *http.Request) []oteltrace.Link {
+	var links []oteltrace.Link
+	for _, header := range tw.batchLinkHeaders {
+		for _, value := range r.Header.Values(header) {
+			for _, traceparent := range strings.Split(value, ",") {
+				if len(links) >= maxBatchLinks {
+					return links
+				}
+				link, ok := linkFromCarrier(propagation.MapCarrier{traceparentKey: strings.TrimSpace(traceparent)})
+				if ok {
+					links = append(links, link)
+				}
+			}
+		}
+	}
+	if len(tw.batchLinkJSONField) > 0 {
+		links = append(links, tw.jsonBatchLinks(r, maxBatchLinks-len(links))...)
+	}
+	return links
+}
+
+// jsonBatchLinks returns at most limit links to the trace contexts carried by
+// the items of the JSON request body, the body is restored for the handler.
+func (tw traceware) jsonBatchLinks(r *http.Request, limit int) []oteltrace.Link {
+	if limit <= 0 || r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return nil
+	}
+
+	// read one more byte for detecting the body exceeding the limit
+	body, err := io.ReadAll(io.LimitReader(r.Body, int64(tw.batchLinkJSONMaxBytes)+1))
+	r.Body = restoredBody{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+	if err != nil || len(body) > tw.batchLinkJSONMaxBytes {
+		return nil
+	}
+
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil
+	}
+	items, ok := payload.([]any)
+	if !ok {
+		items = []any{payload}
+	}
+	var links []oteltrace.Link
+	for i, item := range items {
+		if len(links) >= limit {
+			break
+		}
+		traceparent, ok := jsonField(item, tw.batchLinkJSONField).(string)
+		if !ok {
+			continue
+		}
+		link, ok := linkFromCarrier(propagation.MapCarrier{traceparentKey: traceparent}, BatchItemIndexKey.Int(i))
+		if ok {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// jsonField returns the value of the nested field of the given decoded JSON
+// value, it returns nil when the field doesn't exist.
+func jsonField(value any, path []string) any {
+	for _, name := range path {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+	return value
+}
+
+// restoredBody is the request body which beginning has been read by the
+// middleware & is replayed before the rest of the original body.
+type restoredBody struct {
+	io.Reader
+	io.Closer
+}
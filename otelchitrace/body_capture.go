@@ -0,0 +1,175 @@
+package otelchitrace
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	requestBodyEventName  = "http.request.body"
+	responseBodyEventName = "http.response.body"
+
+	bodyContentKey   = attribute.Key("http.body.content")
+	bodyTruncatedKey = attribute.Key("http.body.truncated")
+)
+
+// WithBodyCapture enables recording the request & response bodies as span
+// events (`http.request.body` & `http.response.body`), truncated to at most
+// maxBytes bytes. Only the bodies which content type matches one of the given
+// content types are recorded, the content type could use wildcard subtype
+// (e.g. `text/*`). If no content type is given, every body is recorded.
+//
+// The bodies are captured while they are read by the handler & written into
+// the response, so the request body is never consumed by the middleware.
+//
+// This option is meant for debugging (e.g. in staging environment), since
+// the bodies might contain sensitive data, use `WithBodyRedactFn` for
+// redacting them before they are recorded.
+func WithBodyCapture(maxBytes int, contentTypes ...string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.bodyCaptureMaxBytes = maxBytes
+		cfg.bodyCaptureContentTypes = contentTypes
+	})
+}
+
+// WithBodyRedactFn specifies the function used for redacting the bodies
+// captured by `WithBodyCapture` before they are recorded, it receives the
+// content type & the captured (possibly truncated) body.
+func WithBodyRedactFn(fn func(contentType string, body []byte) []byte) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.bodyRedactFn = fn
+	})
+}
+
+// bodyCapture holds the captured request & response bodies.
+type bodyCapture struct {
+	maxBytes     int
+	contentTypes []string
+	redactFn     func(contentType string, body []byte) []byte
+
+	request             *cappedBuffer
+	requestContentType  string
+	response            *cappedBuffer
+	responseContentType string
+	responseChecked     bool
+}
+
+// captureRequest starts capturing the request body if its content type
+// matches.
+func (bc *bodyCapture) captureRequest(r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	if r.Body == nil || r.Body == http.NoBody || !bc.matches(contentType) {
+		return
+	}
+	bc.request = &cappedBuffer{max: bc.maxBytes}
+	bc.requestContentType = contentType
+	r.Body = &capturingBody{ReadCloser: r.Body, buf: bc.request}
+}
+
+// onWrite captures the response body, the content type is checked on the
+// first write since the header is complete by then.
+func (bc *bodyCapture) onWrite(header http.Header, b []byte) {
+	if !bc.responseChecked {
+		bc.responseChecked = true
+		contentType := header.Get("Content-Type")
+		if len(contentType) == 0 {
+			// content type is sniffed by net/http in this case
+			contentType = http.DetectContentType(b)
+		}
+		if bc.matches(contentType) {
+			bc.response = &cappedBuffer{max: bc.maxBytes}
+			bc.responseContentType = contentType
+		}
+	}
+	if bc.response != nil {
+		_, _ = bc.response.Write(b)
+	}
+}
+
+// record adds the captured bodies as span events.
+func (bc *bodyCapture) record(span oteltrace.Span, opts ...oteltrace.EventOption) {
+	if bc.request != nil {
+		span.AddEvent(requestBodyEventName, append(opts, oteltrace.WithAttributes(bc.attributes(bc.requestContentType, bc.request)...))...)
+	}
+	if bc.response != nil {
+		span.AddEvent(responseBodyEventName, append(opts, oteltrace.WithAttributes(bc.attributes(bc.responseContentType, bc.response)...))...)
+	}
+}
+
+func (bc *bodyCapture) attributes(contentType string, buf *cappedBuffer) []attribute.KeyValue {
+	body := buf.Bytes()
+	if bc.redactFn != nil {
+		body = bc.redactFn(contentType, body)
+	}
+	return []attribute.KeyValue{
+		bodyContentKey.String(string(body)),
+		bodyTruncatedKey.Bool(buf.truncated),
+	}
+}
+
+// matches reports whether the given content type matches the configured
+// content types.
+func (bc *bodyCapture) matches(contentType string) bool {
+	if len(bc.contentTypes) == 0 {
+		return true
+	}
+	return matchesContentType(contentType, bc.contentTypes)
+}
+
+// matchesContentType returns true when the given content type matches one of
+// the given content types, which could use wildcard subtype (e.g. `text/*`).
+func matchesContentType(contentType string, contentTypes []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, want := range contentTypes {
+		want = strings.ToLower(want)
+		if want == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(want, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// cappedBuffer is a buffer which silently drops the bytes exceeding its
+// capacity.
+type cappedBuffer struct {
+	bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.max - b.Len(); len(p) > remaining {
+		b.truncated = true
+		if remaining > 0 {
+			b.Buffer.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// capturingBody copies the request body into the buffer as it is read.
+type capturingBody struct {
+	io.ReadCloser
+	buf *cappedBuffer
+}
+
+func (c *capturingBody) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	if n > 0 {
+		_, _ = c.buf.Write(p[:n])
+	}
+	return n, err
+}
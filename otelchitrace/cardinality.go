@@ -0,0 +1,23 @@
+package otelchitrace
+
+import (
+	"github.com/riandyrn/otelchi/otelchicore"
+)
+
+// DefaultRouteCardinalityFallback is the route used in place of the routes
+// exceeding the limit set by `WithMaxRouteCardinality` when no fallback is
+// specified.
+const DefaultRouteCardinalityFallback = otelchicore.DefaultRouteCardinalityFallback
+
+// WithMaxRouteCardinality limits the number of distinct `http.route` values
+// (and span names derived from them) emitted by the middleware to n. The
+// routes seen after the limit is reached are collapsed into the fallback
+// route, protecting the tracing backend from cardinality explosion. If the
+// fallback is empty, `_other_` is used.
+//
+// Use `otelchimetric.WithMaxRouteCardinality` for the metric recorders.
+func WithMaxRouteCardinality(n int, fallback string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.routeLimiter = otelchicore.NewRouteLimiter(n, fallback)
+	})
+}
@@ -0,0 +1,303 @@
+package otelchitrace
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	clientAddressKey = attribute.Key("client.address")
+)
+
+// ClientIPStrategy determines which request header is used for resolving the
+// address of the client behind proxies.
+type ClientIPStrategy int
+
+const (
+	// ClientIPStrategyXForwardedFor resolves the client address from the
+	// first address in `X-Forwarded-For` header. This is the default strategy.
+	ClientIPStrategyXForwardedFor ClientIPStrategy = iota
+	// ClientIPStrategyForwarded resolves the client address from the `for`
+	// parameter of the first element in RFC 7239 `Forwarded` header. When the
+	// stable HTTP semantic conventions are used, the `proto` & `host`
+	// parameters of the same element are recorded as `url.scheme` &
+	// `server.address` (with `server.port`) attributes. The configured server
+	// name still takes precedence over the `host` parameter for
+	// `server.address`.
+	ClientIPStrategyForwarded
+	// ClientIPStrategyForwardedThenXForwardedFor resolves the client address
+	// from `Forwarded` header, if it is not present `X-Forwarded-For` header
+	// will be used instead. The `proto` & `host` parameters of `Forwarded`
+	// header are recorded like in `ClientIPStrategyForwarded`.
+	ClientIPStrategyForwardedThenXForwardedFor
+	// ClientIPStrategyRemoteAddr ignores any forwarding headers and only use
+	// the address of the peer connected to the server.
+	ClientIPStrategyRemoteAddr
+)
+
+// ClientIPConfig is configuration for resolving the address of the client
+// that sends the request.
+type ClientIPConfig struct {
+	// Strategy determines which forwarding header is used for resolving the
+	// client address, see `ClientIPStrategy` for details.
+	Strategy ClientIPStrategy
+	// ProxyProtocolContextKey is the request context key where the PROXY-protocol
+	// aware listener stores the original client address (e.g. through
+	// `http.Server.ConnContext`). The stored value could be either `net.Addr`
+	// or `string`. If nil, the request context won't be checked.
+	ProxyProtocolContextKey any
+	// ProxyProtocolHeader is the request header where the PROXY-protocol aware
+	// proxy puts the original client address. Like the forwarding headers, it
+	// is only honored for requests coming from the trusted proxies when
+	// `TrustedProxies` is set. If empty, no header will be checked.
+	ProxyProtocolHeader string
+	// TrustedProxies is the list of networks of the proxies in front of the
+	// server. When it is set, the forwarding headers are only honored for
+	// requests coming from the trusted proxies, and the client address is the
+	// right-most address in the forwarding header which is not a trusted
+	// proxy. This prevents the clients from spoofing their address by sending
+	// the forwarding headers themselves. If empty, every proxy is trusted &
+	// the left-most address in the forwarding header is used.
+	TrustedProxies []netip.Prefix
+}
+
+// WithClientIP enables recording the address of the client that sends the
+// request as `client.address` attribute.
+//
+// The address is resolved in following order:
+//
+//  1. The original client address provided by PROXY-protocol aware listener,
+//     see `ClientIPConfig` for details.
+//  2. The forwarding header selected by `ClientIPConfig.Strategy`.
+//  3. The address of the peer connected to the server (`http.Request.RemoteAddr`).
+//
+// When the stable HTTP semantic conventions are used (see `WithSemconvVersion`),
+// the `client.address` attribute is always recorded, this option only
+// customizes how the address is resolved.
+func WithClientIP(cfg ClientIPConfig) Option {
+	return optionFunc(func(c *config) {
+		c.clientIP = &cfg
+	})
+}
+
+// resolveClientIP returns the address of the client that sends the request
+// based on the given config, it returns empty string when the address cannot
+// be resolved.
+func resolveClientIP(cfg *ClientIPConfig, r *http.Request) string {
+	if addr := proxyProtocolContextAddress(cfg, r); len(addr) > 0 {
+		return addr
+	}
+	peer := hostOnly(r.RemoteAddr)
+	if len(cfg.TrustedProxies) > 0 && !isTrustedProxy(cfg.TrustedProxies, peer) {
+		// the PROXY-protocol & forwarding headers are set by the client itself
+		return peer
+	}
+	if addr := proxyProtocolHeaderAddress(cfg, r); len(addr) > 0 {
+		return addr
+	}
+	switch cfg.Strategy {
+	case ClientIPStrategyXForwardedFor:
+		if addr := xForwardedForAddress(cfg, r); len(addr) > 0 {
+			return addr
+		}
+	case ClientIPStrategyForwarded:
+		if addr := forwardedAddress(cfg, r); len(addr) > 0 {
+			return addr
+		}
+	case ClientIPStrategyForwardedThenXForwardedFor:
+		if addr := forwardedAddress(cfg, r); len(addr) > 0 {
+			return addr
+		}
+		if addr := xForwardedForAddress(cfg, r); len(addr) > 0 {
+			return addr
+		}
+	}
+	return peer
+}
+
+// xForwardedForAddress returns the client address from `X-Forwarded-For`
+// headers. The proxy might add its own header line instead of appending to
+// the existing one, so every line is combined in order before the chain is
+// walked.
+func xForwardedForAddress(cfg *ClientIPConfig, r *http.Request) string {
+	var addrs []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, addr := range strings.Split(header, ",") {
+			addrs = append(addrs, strings.TrimSpace(addr))
+		}
+	}
+	return clientFromForwardedChain(cfg, addrs)
+}
+
+// forwardedAddress returns the `for` parameter of the element in RFC 7239
+// `Forwarded` header describing the client, e.g.
+// `Forwarded: for="[2001:db8::1]:4711";proto=https`, see
+// `forwardedClientElement` for how the element is chosen.
+func forwardedAddress(cfg *ClientIPConfig, r *http.Request) string {
+	return forwardedNodeHost(forwardedClientElement(cfg, r)["for"])
+}
+
+// forwardedClientElement returns the element of RFC 7239 `Forwarded` header
+// added for the request sent by the client, which is the first element. When
+// trusted proxies are configured, it is the right-most element which `for`
+// parameter is not a trusted proxy instead. It returns nil when the header is
+// missing.
+func forwardedClientElement(cfg *ClientIPConfig, r *http.Request) map[string]string {
+	if len(cfg.TrustedProxies) == 0 {
+		return forwardedElement(r)
+	}
+	elems := forwardedElements(r)
+	for i := len(elems) - 1; i >= 0; i-- {
+		if !isTrustedProxy(cfg.TrustedProxies, forwardedNodeHost(elems[i]["for"])) {
+			return elems[i]
+		}
+	}
+	if len(elems) == 0 {
+		return nil
+	}
+	// every element is added for a trusted proxy, use the left-most one
+	return elems[0]
+}
+
+// forwardedOrigin returns the scheme & host of the request sent by the client
+// from the `proto` & `host` parameters of RFC 7239 `Forwarded` header. They
+// are only returned when the header is used by the strategy & it is trusted,
+// otherwise empty strings are returned.
+func forwardedOrigin(cfg *ClientIPConfig, r *http.Request) (scheme, host string) {
+	if cfg.Strategy != ClientIPStrategyForwarded && cfg.Strategy != ClientIPStrategyForwardedThenXForwardedFor {
+		return "", ""
+	}
+	if len(cfg.TrustedProxies) > 0 && !isTrustedProxy(cfg.TrustedProxies, hostOnly(r.RemoteAddr)) {
+		// the header is set by the client itself
+		return "", ""
+	}
+	elem := forwardedClientElement(cfg, r)
+	switch proto := strings.ToLower(elem["proto"]); proto {
+	case "http", "https":
+		scheme = proto
+	}
+	return scheme, elem["host"]
+}
+
+// clientFromForwardedChain returns the client address from the chain of
+// addresses in the forwarding header, ordered from the client to the last
+// proxy.
+func clientFromForwardedChain(cfg *ClientIPConfig, addrs []string) string {
+	if len(addrs) == 0 {
+		return ""
+	}
+	if len(cfg.TrustedProxies) == 0 {
+		return addrs[0]
+	}
+	for i := len(addrs) - 1; i >= 0; i-- {
+		if !isTrustedProxy(cfg.TrustedProxies, addrs[i]) {
+			return addrs[i]
+		}
+	}
+	// every address is a trusted proxy, use the left-most one
+	return addrs[0]
+}
+
+// isTrustedProxy reports whether the given address belongs to one of the
+// trusted proxy networks.
+func isTrustedProxy(trusted []netip.Prefix, addr string) bool {
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedElement parses the first element of RFC 7239 `Forwarded` header
+// into map of lower-cased parameter name to its unquoted value.
+func forwardedElement(r *http.Request) map[string]string {
+	header := r.Header.Get("Forwarded")
+	if len(header) == 0 {
+		return nil
+	}
+	first, _, _ := strings.Cut(header, ",")
+	return parseForwardedElement(first)
+}
+
+// forwardedElements parses every element of RFC 7239 `Forwarded` headers.
+func forwardedElements(r *http.Request) []map[string]string {
+	var elems []map[string]string
+	for _, header := range r.Header.Values("Forwarded") {
+		for _, elem := range strings.Split(header, ",") {
+			elems = append(elems, parseForwardedElement(elem))
+		}
+	}
+	return elems
+}
+
+// parseForwardedElement parses single element of RFC 7239 `Forwarded` header
+// into map of lower-cased parameter name to its unquoted value.
+func parseForwardedElement(element string) map[string]string {
+	elem := map[string]string{}
+	for _, pair := range strings.Split(element, ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			continue
+		}
+		elem[strings.ToLower(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return elem
+}
+
+// forwardedNodeHost returns the host part of RFC 7239 node identifier, it
+// returns empty string for obfuscated or unknown identifier.
+func forwardedNodeHost(node string) string {
+	if len(node) == 0 || node == "unknown" || strings.HasPrefix(node, "_") {
+		return ""
+	}
+	if strings.HasPrefix(node, "[") {
+		// IPv6 address is always enclosed in brackets
+		end := strings.Index(node, "]")
+		if end < 0 {
+			return ""
+		}
+		return node[1:end]
+	}
+	return hostOnly(node)
+}
+
+// proxyProtocolContextAddress returns the original client address stored in
+// the request context by the listener, the client cannot set it.
+func proxyProtocolContextAddress(cfg *ClientIPConfig, r *http.Request) string {
+	if cfg.ProxyProtocolContextKey != nil {
+		switch v := r.Context().Value(cfg.ProxyProtocolContextKey).(type) {
+		case net.Addr:
+			return hostOnly(v.String())
+		case string:
+			return hostOnly(v)
+		}
+	}
+	return ""
+}
+
+// proxyProtocolHeaderAddress returns the original client address put in the
+// request header by the proxy, it must only be honored for trusted proxies.
+func proxyProtocolHeaderAddress(cfg *ClientIPConfig, r *http.Request) string {
+	if len(cfg.ProxyProtocolHeader) > 0 {
+		return hostOnly(strings.TrimSpace(r.Header.Get(cfg.ProxyProtocolHeader)))
+	}
+	return ""
+}
+
+// hostOnly strips the port from the given address if any.
+func hostOnly(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
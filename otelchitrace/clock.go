@@ -0,0 +1,61 @@
+package otelchitrace
+
+import (
+	"time"
+
+	"github.com/riandyrn/otelchi/otelchicore"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Clock is the time source used by the middleware.
+type Clock = otelchicore.Clock
+
+// WithClock specifies the time source used for the span start & end
+// timestamps. It is mainly useful for tests which need deterministic span
+// durations without real sleeps. If none is specified, the timestamps are
+// determined by the tracer.
+func WithClock(clock Clock) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.clock = clock
+	})
+}
+
+// WithTimeNow specifies the function returning the current time, it is the
+// shorthand of `WithClock` for the time source which is a plain function,
+// e.g. the one replaying the recorded timestamps in simulations.
+func WithTimeNow(now func() time.Time) Option {
+	return WithClock(otelchicore.ClockFunc(now))
+}
+
+// clockStartOptions returns the span start options related to the clock.
+func (tw traceware) clockStartOptions() []oteltrace.SpanStartOption {
+	if tw.clock == nil {
+		return nil
+	}
+	return []oteltrace.SpanStartOption{oteltrace.WithTimestamp(tw.clock.Now())}
+}
+
+// clockEndOptions returns the span end options related to the clock.
+func (tw traceware) clockEndOptions() []oteltrace.SpanEndOption {
+	if tw.clock == nil {
+		return nil
+	}
+	return []oteltrace.SpanEndOption{oteltrace.WithTimestamp(tw.clock.Now())}
+}
+
+// clockEventOptions returns the span event options related to the clock.
+func (tw traceware) clockEventOptions() []oteltrace.EventOption {
+	if tw.clock == nil {
+		return nil
+	}
+	return []oteltrace.EventOption{oteltrace.WithTimestamp(tw.clock.Now())}
+}
+
+// now returns the current time from the clock, it falls back to the system
+// clock when no clock is specified.
+func (tw traceware) now() time.Time {
+	if tw.clock == nil {
+		return time.Now()
+	}
+	return tw.clock.Now()
+}
@@ -0,0 +1,93 @@
+package otelchitrace
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/felixge/httpsnoop"
+	"go.opentelemetry.io/otel/attribute"
+	semconvstable "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// ResponseUncompressedSizeKey is the attribute key of the size of the
+// response body before it is compressed, it is recorded when the compression
+// middleware is wrapped by `WrapCompress`.
+const ResponseUncompressedSizeKey = attribute.Key("http.response.body.uncompressed_size")
+
+type compressionStateCtxKey struct{}
+
+// compressionState holds the number of bytes written by the handler into the
+// compression middleware.
+type compressionState struct {
+	probed            bool
+	uncompressedBytes int64
+}
+
+// WithCompressionAttributes enables recording the content negotiation &
+// compression attributes on the server span:
+//
+//   - `http.request.header.accept-encoding`
+//   - `http.response.header.content-encoding`
+//   - `http.response.body.size`, the size of the response body written on the
+//     wire (i.e. after compression)
+//   - `http.response.body.uncompressed_size`, only when the compression
+//     middleware is wrapped by `WrapCompress`
+//
+// This allows analyzing the compression savings per route from the spans.
+func WithCompressionAttributes() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.compressionAttributes = true
+	})
+}
+
+// WrapCompress wraps the compression middleware (e.g. chi `middleware.Compress`)
+// so the size of the response body before it is compressed could be recorded
+// by the tracing middleware created with `WithCompressionAttributes`, e.g:
+//
+//	router.Use(otelchitrace.Middleware("my-server", otelchitrace.WithCompressionAttributes()))
+//	router.Use(otelchitrace.WrapCompress(middleware.Compress(5)))
+//
+// The compression middleware is executed as it is when the request is not
+// traced by such tracing middleware.
+func WrapCompress(compress func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		// count the bytes written by the next handler into the compressor
+		counter := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			state, ok := r.Context().Value(compressionStateCtxKey{}).(*compressionState)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			state.probed = true
+			next.ServeHTTP(httpsnoop.Wrap(w, httpsnoop.Hooks{
+				Write: func(next httpsnoop.WriteFunc) httpsnoop.WriteFunc {
+					return func(b []byte) (int, error) {
+						n, err := next(b)
+						state.uncompressedBytes += int64(n)
+						return n, err
+					}
+				},
+				ReadFrom: func(next httpsnoop.ReadFromFunc) httpsnoop.ReadFromFunc {
+					return func(src io.Reader) (int64, error) {
+						n, err := next(src)
+						state.uncompressedBytes += n
+						return n, err
+					}
+				},
+			}), r)
+		})
+		return compress(counter)
+	}
+}
+
+// compressionAttributes returns the content negotiation & compression
+// attributes of the completed request.
+func compressionAttributes(r *http.Request, header http.Header, writtenBytes int64, state *compressionState) []attribute.KeyValue {
+	attrs := headerAttributes("http.request.header.", r.Header, []string{"Accept-Encoding"})
+	attrs = append(attrs, headerAttributes("http.response.header.", header, []string{"Content-Encoding"})...)
+	attrs = append(attrs, semconvstable.HTTPResponseBodySize(int(writtenBytes)))
+	if state.probed {
+		attrs = append(attrs, ResponseUncompressedSizeKey.Int64(state.uncompressedBytes))
+	}
+	return attrs
+}
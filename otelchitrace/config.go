@@ -0,0 +1,437 @@
+package otelchitrace
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/internal/health"
+	"github.com/riandyrn/otelchi/internal/routeattr"
+	"github.com/riandyrn/otelchi/otelchicore"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/log"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// These defaults are used in `TraceHeaderConfig`.
+const (
+	DefaultTraceIDResponseHeaderKey      = "X-Trace-Id"
+	DefaultTraceSampledResponseHeaderKey = "X-Trace-Sampled"
+)
+
+// config is used to configure the mux middleware.
+type config struct {
+	tracerProvider                oteltrace.TracerProvider
+	propagators                   propagation.TextMapPropagator
+	chiRoutes                     chi.Routes
+	requestMethodInSpanName       bool
+	filters                       []Filter
+	routeFilters                  []RouteFilter
+	traceIDResponseHeaderKey      string
+	traceSampledResponseHeaderKey string
+	publicEndpointFn              func(r *http.Request) bool
+	internalRequestFn             func(r *http.Request) bool
+	tlsClientIdentity             bool
+	tlsClientRedactFn             func(value string) string
+	clientIP                      *ClientIPConfig
+	staticAttributes              []attribute.KeyValue
+	schemaURL                     string
+	scopeAttributes               []attribute.KeyValue
+	dynamicServerName             *ServerName
+	serverNameFn                  func(r *http.Request) string
+	clock                         Clock
+	overheadMetric                bool
+	meterProvider                 otelmetric.MeterProvider
+	overheadMeterProvider         otelmetric.MeterProvider
+	shadowRequestFn               func(r *http.Request) bool
+	semconvMode                   SemconvMode
+	spanAttributesFn              func(r *http.Request) []attribute.KeyValue
+	capturedRequestHeaders        []string
+	capturedResponseHeaders       []string
+	spanStatusFn                  func(statusCode int) (codes.Code, string)
+	errorHook                     func(span oteltrace.Span, r *http.Request, statusCode int)
+	traceparentResponseHeader     bool
+	tracestateResponseHeader      bool
+	traceResponseHeaderFn         func(r *http.Request) bool
+	routeConfigs                  []routeConfig
+	sseInstrumentation            bool
+	websocketSpanMode             WebsocketSpanMode
+	middlewareSpans               bool
+	handlerSpan                   bool
+	carrierFn                     func(r *http.Request) propagation.TextMapCarrier
+	networkAttributes             bool
+	bodyCaptureMaxBytes           int
+	bodyCaptureContentTypes       []string
+	bodyRedactFn                  func(contentType string, body []byte) []byte
+	routerMiddlewares             []func(http.Handler) http.Handler
+	urlParamsAllowlist            []string
+	routeLimiter                  *otelchicore.RouteLimiter
+	loggerProvider                log.LoggerProvider
+	dynamicTracerProvider         bool
+	compressionAttributes         bool
+	queryRecordingMode            QueryRecordingMode
+	queryAllowlist                []string
+	queryDenylist                 []string
+	methodOverrideHeaders         []string
+	latencySamplingThreshold      time.Duration
+	propagationMode               PropagationMode
+	healthPaths                   health.Paths
+	routeCache                    *otelchicore.RouteCache
+	lowAllocationMode             bool
+	spanStartOptionsFn            func(r *http.Request) []oteltrace.SpanStartOption
+	requestStartHeader            string
+	attributeFilter               func(attribute.KeyValue) bool
+	debugStats                    bool
+	mountPrefix                   string
+	notFoundRoute                 string
+	lifecycleEvents               bool
+	secondaryTracerProvider       oteltrace.TracerProvider
+	problemDetailsMaxBytes        int
+	requestIDHeader               string
+	generateRequestID             bool
+	tracePropagationOnly          bool
+	tracerName                    string
+	tracer                        oteltrace.Tracer
+	capturedResponseTrailers      []string
+	slogLogger                    *slog.Logger
+	routeAttributes               routeattr.Registry
+	idempotencyKeyHeader          string
+	idempotencyKeyUnhashed        bool
+	idempotencyRetryWindow        time.Duration
+	postRouteEnrichment           func(r *http.Request, span oteltrace.Span)
+	routeNormalizer               func(pattern string) string
+	serverTimingHeader            bool
+	spanKindFn                    func(r *http.Request) oteltrace.SpanKind
+	batchLinkHeaders              []string
+	batchLinkJSONField            []string
+	batchLinkJSONMaxBytes         int
+	bodyStatusMaxBytes            int
+	bodyStatusFn                  func(status int, body []byte) (codes.Code, string)
+	bodyStatusContentTypes        []string
+	graphQLPath                   string
+	phaseTimings                  bool
+	attributeValueLengthLimit     int
+}
+
+// Option specifies instrumentation configuration options.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (o optionFunc) apply(c *config) {
+	o(c)
+}
+
+// Filter is a predicate used to determine whether a given http.Request should
+// be traced. A Filter must return true if the request should be traced.
+type Filter func(*http.Request) bool
+
+// RouteFilter is a predicate used to determine whether a given http.Request
+// should be traced based on its chi route pattern. A RouteFilter must return
+// true if the request should be traced.
+type RouteFilter func(route string, r *http.Request) bool
+
+// WithPropagators specifies propagators to use for extracting
+// information from the HTTP requests. If none are specified, global
+// ones will be used.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.propagators = propagators
+	})
+}
+
+// WithCarrierFn specifies the function returning the carrier used by the
+// propagators for extracting the trace context from the HTTP requests, e.g.
+// query parameters of signed webhook callbacks. If none is specified, the
+// request headers are used.
+func WithCarrierFn(fn func(r *http.Request) propagation.TextMapCarrier) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.carrierFn = fn
+	})
+}
+
+// WithTracerProvider specifies a tracer provider to use for creating a tracer.
+// If none is specified, the global provider is used.
+func WithTracerProvider(provider oteltrace.TracerProvider) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.tracerProvider = provider
+	})
+}
+
+// WithChiRoutes specified the routes that being used by application. Its main
+// purpose is to provide route pattern as span name during span creation. If this
+// option is not set, by default the span is named by the raw request path on
+// creation & renamed to the route pattern at the end of span execution, unless
+// the underlying handler has already overridden the span name.
+func WithChiRoutes(routes chi.Routes) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.chiRoutes = routes
+	})
+}
+
+// WithRequestMethodInSpanName is used for adding http request method to span name.
+// While this is not necessary for vendors that properly implemented the tracing
+// specs (e.g Jaeger, AWS X-Ray, etc...), but for other vendors such as Elastic
+// and New Relic this might be helpful.
+//
+// See following threads for details:
+//
+// - https://github.com/riandyrn/otelchi/pull/3#issuecomment-1005883910
+// - https://github.com/riandyrn/otelchi/issues/6#issuecomment-1034461912
+func WithRequestMethodInSpanName(isActive bool) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.requestMethodInSpanName = isActive
+	})
+}
+
+// WithFilter adds a filter to the list of filters used by the handler.
+// If any filter indicates to exclude a request then the request will not be
+// traced. All filters must allow a request to be traced for a Span to be created.
+// If no filters are provided then all requests are traced.
+// Filters will be invoked for each processed request, it is advised to make them
+// simple and fast.
+func WithFilter(filter Filter) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.filters = append(cfg.filters, filter)
+	})
+}
+
+// WithRouteFilter adds a route filter to the list of route filters used by
+// the handler. Unlike `WithFilter`, the route filters receive the chi route
+// pattern matched by the request (e.g. `/debug/*`), which allows excluding
+// requests by their route. The route is empty when the request doesn't match
+// any route.
+//
+// Since the route must be resolved before the span is started, route filters
+// only take effect when `WithChiRoutes` is also used. They are invoked after
+// the filters registered by `WithFilter`.
+func WithRouteFilter(filter RouteFilter) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.routeFilters = append(cfg.routeFilters, filter)
+	})
+}
+
+// WithTraceIDResponseHeader enables adding trace id into response header.
+// It accepts a function that generates the header key name. If this parameter
+// function set to `nil` the default header key which is `X-Trace-Id` will be used.
+//
+// Deprecated: use `WithTraceResponseHeaders` instead.
+func WithTraceIDResponseHeader(headerKeyFunc func() string) Option {
+	cfg := TraceHeaderConfig{
+		TraceIDHeader:      "",
+		TraceSampledHeader: "",
+	}
+	if headerKeyFunc != nil {
+		cfg.TraceIDHeader = headerKeyFunc()
+	}
+	return WithTraceResponseHeaders(cfg)
+}
+
+// TraceHeaderConfig is configuration for trace headers in the response.
+type TraceHeaderConfig struct {
+	TraceIDHeader      string `json:"trace_id_header" yaml:"trace_id_header"`           // if non-empty overrides the default of X-Trace-ID
+	TraceSampledHeader string `json:"trace_sampled_header" yaml:"trace_sampled_header"` // if non-empty overrides the default of X-Trace-Sampled
+	EmitTraceparent    bool   `json:"emit_traceparent" yaml:"emit_traceparent"`         // if true the W3C `traceparent` header is also written
+	EmitTracestate     bool   `json:"emit_tracestate" yaml:"emit_tracestate"`           // if true the W3C `tracestate` header is also written when it is non-empty
+
+	// SetHeaderFn decides whether the trace headers are written into the
+	// response of the given request, e.g. only for the internal callers or
+	// the requests having a debug header, so the trace ids are not leaked to
+	// the public internet. If it is nil, the headers are always written.
+	SetHeaderFn func(r *http.Request) bool `json:"-" yaml:"-"`
+}
+
+// WithTraceResponseHeaders configures the response headers for trace information.
+// It accepts a TraceHeaderConfig struct that contains the keys for the Trace ID
+// and Trace Sampled headers. If the provided keys are empty, default values will
+// be used for the respective headers.
+//
+// The W3C `traceparent` & `tracestate` headers could also be written by setting
+// `EmitTraceparent` & `EmitTracestate`, this allows browser RUM agents to link
+// the frontend spans with the backend traces.
+func WithTraceResponseHeaders(cfg TraceHeaderConfig) Option {
+	return optionFunc(func(c *config) {
+		c.traceIDResponseHeaderKey = cfg.TraceIDHeader
+		if c.traceIDResponseHeaderKey == "" {
+			c.traceIDResponseHeaderKey = DefaultTraceIDResponseHeaderKey
+		}
+
+		c.traceSampledResponseHeaderKey = cfg.TraceSampledHeader
+		if c.traceSampledResponseHeaderKey == "" {
+			c.traceSampledResponseHeaderKey = DefaultTraceSampledResponseHeaderKey
+		}
+
+		c.traceparentResponseHeader = cfg.EmitTraceparent
+		c.tracestateResponseHeader = cfg.EmitTracestate
+		c.traceResponseHeaderFn = cfg.SetHeaderFn
+	})
+}
+
+// WithPublicEndpoint is used for marking every endpoint as public endpoint.
+// This means if the incoming request has span context, it won't be used as
+// parent span by the span generated by this middleware, instead the generated
+// span will be the root span (new trace) and then linked to the span from the
+// incoming request.
+//
+// Let say we have the following scenario:
+//
+//  1. We have 2 systems: `SysA` & `SysB`.
+//  2. `SysA` has the following services: `SvcA.1` & `SvcA.2`.
+//  3. `SysB` has the following services: `SvcB.1` & `SvcB.2`.
+//  4. `SvcA.2` is used internally only by `SvcA.1`.
+//  5. `SvcB.2` is used internally only by `SvcB.1`.
+//  6. All of these services already instrumented otelchi & using the same collector (e.g Jaeger).
+//  7. In `SvcA.1` we should set `WithPublicEndpoint()` since it is the entry point (a.k.a "public endpoint") for entering `SysA`.
+//  8. In `SvcA.2` we should not set `WithPublicEndpoint()` since it is only used internally by `SvcA.1` inside `SysA`.
+//  9. Point 7 & 8 also applies to both services in `SysB`.
+//
+// Now, whenever `SvcA.1` calls `SvcA.2` there will be only a single trace generated. This trace will contain 2 spans: root span from `SvcA.1` & child span from `SvcA.2`.
+//
+// But if let say `SvcA.2` calls `SvcB.1`, then there will be 2 traces generated: trace from `SysA` & trace from `SysB`. But in trace generated in `SysB` there will be like a marking that this trace is actually related to trace in `SysA` (a.k.a linked with the trace from `SysA`).
+func WithPublicEndpoint() Option {
+	return WithPublicEndpointFn(func(r *http.Request) bool { return true })
+}
+
+// WithPublicEndpointFn runs with every request, and allows conditionally
+// configuring the Handler to link the generated span with an incoming span
+// context.
+//
+// If the function return `true` the generated span will be linked with the
+// incoming span context. Otherwise, the generated span will be set as the
+// child span of the incoming span context.
+//
+// Essentially it has the same functionality as `WithPublicEndpoint` but with
+// more flexibility.
+func WithPublicEndpointFn(fn func(r *http.Request) bool) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.publicEndpointFn = fn
+	})
+}
+
+// WithInternalRequestFn runs with every request, and allows conditionally
+// marking the generated span as an internal span (`SpanKindInternal`) instead
+// of a server span.
+//
+// This is useful when the service runs behind a service mesh sidecar (e.g.
+// Envoy) that already creates a server span for the incoming request. In that
+// case recording another server span for the same request produces duplicated
+// server spans in the trace, so the function could be used to detect requests
+// forwarded by the sidecar (e.g. by checking a header set by the mesh).
+func WithInternalRequestFn(fn func(r *http.Request) bool) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.internalRequestFn = fn
+	})
+}
+
+// WithSpanKindFn runs with every request, and allows choosing the kind of the
+// generated span, e.g. recording the intra-cluster endpoints only called by
+// sidecars as internal spans (`SpanKindInternal`) to keep the service maps
+// clean. When the function returns `SpanKindUnspecified`, the kind is
+// determined as if the option is not used.
+//
+// This option takes precedence over `WithInternalRequestFn`.
+func WithSpanKindFn(fn func(r *http.Request) oteltrace.SpanKind) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.spanKindFn = fn
+	})
+}
+
+// WithTLSClientIdentity enables recording the identity of the verified client
+// certificate (mTLS) as span attributes. The certificate subject is recorded
+// as `tls.client.subject`, its common name as `tls.client.subject.common_name`
+// and its SANs as `tls.client.subject.alt_names`.
+//
+// The redactFn is invoked for every recorded value, it could be used to mask
+// sensitive part of the identity. If it is set to `nil`, the values will be
+// recorded as is.
+func WithTLSClientIdentity(redactFn func(value string) string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.tlsClientIdentity = true
+		cfg.tlsClientRedactFn = redactFn
+	})
+}
+
+// WithStaticAttributes adds the given attributes to every span & metric
+// generated by the middleware (see `WithOverheadMetric` &
+// `WithRequestQueueTimeMetric`), e.g. `service.component=api-gateway`. Since
+// the attributes are constant, they are cheaper than computing the attributes
+// for each request.
+//
+// When this option is used multiple times, the attributes are accumulated.
+func WithStaticAttributes(attrs ...attribute.KeyValue) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.staticAttributes = append(cfg.staticAttributes, attrs...)
+	})
+}
+
+// WithSchemaURL overrides the semantic conventions schema URL set on the
+// tracer. By default the schema URL of the semantic conventions version used
+// by the middleware is set, so backends doing schema-aware translation could
+// handle the generated spans correctly. This option is useful when the
+// attributes are pinned to a different semantic conventions version.
+func WithSchemaURL(schemaURL string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.schemaURL = schemaURL
+	})
+}
+
+// WithScopeAttributes sets the instrumentation scope attributes of the tracer.
+func WithScopeAttributes(attrs ...attribute.KeyValue) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.scopeAttributes = append(cfg.scopeAttributes, attrs...)
+	})
+}
+
+// WithSpanAttributesFn specifies a function invoked for every request before
+// the span is started, the returned attributes are added into the span. This
+// is useful for attaching per-request attributes such as tenant ID or feature
+// flags without writing a separate middleware.
+//
+// The function is invoked on the hot path, it is advised to make it simple
+// and fast. For constant attributes use `WithStaticAttributes` instead.
+func WithSpanAttributesFn(fn func(r *http.Request) []attribute.KeyValue) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.spanAttributesFn = fn
+	})
+}
+
+// WithSpanStartOptionsFn specifies a function invoked for every request right
+// before the span is started, the returned options are applied after the
+// options set by the middleware. This allows advanced customization of the
+// span start such as adding links, setting the start timestamp from the
+// `X-Request-Start` header set by the load balancer, or adding attributes at
+// span start rather than after.
+//
+// The function is invoked on the hot path, it is advised to make it simple
+// and fast.
+func WithSpanStartOptionsFn(fn func(r *http.Request) []oteltrace.SpanStartOption) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.spanStartOptionsFn = fn
+	})
+}
+
+// WithSpanStatusFn specifies the function used for mapping the response status
+// code into the span status. By default, as per the semantic conventions for
+// server spans, only 5xx status codes are marked as `codes.Error` while the
+// other status codes (including 4xx) leave the span status unset.
+func WithSpanStatusFn(fn func(statusCode int) (codes.Code, string)) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.spanStatusFn = fn
+	})
+}
+
+// WithErrorHook specifies the function invoked after the handler is executed
+// for every response with 4xx or 5xx status code. The span passed to the hook
+// is the server span, so the hook could be used for recording domain errors
+// (e.g. via `span.RecordError`) or additional attributes on it.
+func WithErrorHook(hook func(span oteltrace.Span, r *http.Request, statusCode int)) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.errorHook = hook
+	})
+}
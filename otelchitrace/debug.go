@@ -0,0 +1,281 @@
+package otelchitrace
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// WithDebugStats registers the middleware into the report served by
+// `DebugHandler` and enables counting the filtered requests & the emitted
+// spans per route. The middleware stays registered for the lifetime of the
+// process, so this option should only be used for the long-lived
+// middlewares.
+func WithDebugStats() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.debugStats = true
+	})
+}
+
+// DebugHandler returns the handler reporting the instrumentation state of the
+// middlewares created with `WithDebugStats` as JSON, it is mountable under
+// e.g. `/debug/otelchi`:
+//
+//	router.Mount("/debug/otelchi", otelchitrace.DebugHandler())
+//
+// The report includes the active options, the resolved propagators, the
+// route cache contents, the filter hit counts & the number of spans emitted
+// per route, which helps diagnosing why a route is not traced in production.
+// The report may reveal the internals of the service, so make sure the
+// handler is not publicly accessible.
+func DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(DebugReport{Middlewares: debugRegistry.report()})
+	})
+}
+
+// DebugReport is the report served by `DebugHandler`.
+type DebugReport struct {
+	Middlewares []MiddlewareDebugInfo `json:"middlewares"`
+}
+
+// MiddlewareDebugInfo is the instrumentation state of a middleware.
+type MiddlewareDebugInfo struct {
+	ServerName       string           `json:"server_name"`
+	Version          string           `json:"version"`
+	SemconvMode      string           `json:"semconv_mode"`
+	ActiveOptions    []string         `json:"active_options"`
+	Propagator       string           `json:"propagator"`
+	PropagatorFields []string         `json:"propagator_fields"`
+	RouteCache       []RouteCacheInfo `json:"route_cache,omitempty"`
+	FilterHits       map[string]int64 `json:"filter_hits"`
+	SpansPerRoute    map[string]int64 `json:"spans_per_route"`
+}
+
+// RouteCacheInfo is the entry of the route cache.
+type RouteCacheInfo struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Route   string `json:"route"`
+	Matched bool   `json:"matched"`
+}
+
+// debugFilter identifies the reason of the request is not traced.
+type debugFilter int
+
+const (
+	debugFilterFilter debugFilter = iota
+	debugFilterRouteFilter
+	debugFilterRouteConfig
+	debugFilterHealthCheck
+	debugFilterCount
+)
+
+var debugFilterNames = [debugFilterCount]string{
+	debugFilterFilter:      "filter",
+	debugFilterRouteFilter: "route_filter",
+	debugFilterRouteConfig: "route_config",
+	debugFilterHealthCheck: "health_check",
+}
+
+// debugStats holds the instrumentation state of a middleware. The methods
+// are safe to call on a nil stats, in which case nothing is counted.
+type debugStats struct {
+	serverName string
+	cfg        config
+
+	filterHits    [debugFilterCount]atomic.Int64
+	spansPerRoute sync.Map // route => *atomic.Int64
+}
+
+// newDebugStats returns the stats of the middleware registered into the
+// debug registry, it returns nil when `WithDebugStats` is not used.
+func newDebugStats(cfg config, serverName string) *debugStats {
+	if !cfg.debugStats {
+		return nil
+	}
+	stats := &debugStats{serverName: serverName, cfg: cfg}
+	debugRegistry.add(stats)
+	return stats
+}
+
+func (s *debugStats) filtered(filter debugFilter) {
+	if s != nil {
+		s.filterHits[filter].Add(1)
+	}
+}
+
+func (s *debugStats) spanEmitted(route string) {
+	if s == nil {
+		return
+	}
+	counter, ok := s.spansPerRoute.Load(route)
+	if !ok {
+		counter, _ = s.spansPerRoute.LoadOrStore(route, &atomic.Int64{})
+	}
+	counter.(*atomic.Int64).Add(1)
+}
+
+func (s *debugStats) info() MiddlewareDebugInfo {
+	info := MiddlewareDebugInfo{
+		ServerName:       s.serverName,
+		Version:          Version(),
+		SemconvMode:      s.cfg.semconvMode.String(),
+		ActiveOptions:    s.cfg.activeOptions(),
+		Propagator:       fmt.Sprintf("%T", s.cfg.propagators),
+		PropagatorFields: s.cfg.propagators.Fields(),
+		FilterHits:       make(map[string]int64, len(debugFilterNames)),
+		SpansPerRoute:    make(map[string]int64),
+	}
+	if s.cfg.dynamicServerName != nil {
+		info.ServerName = s.cfg.dynamicServerName.Get()
+	}
+	for _, entry := range s.cfg.routeCache.Entries() {
+		info.RouteCache = append(info.RouteCache, RouteCacheInfo{
+			Method:  entry.Method,
+			Path:    entry.Path,
+			Route:   entry.Pattern,
+			Matched: entry.Matched,
+		})
+	}
+	sort.Slice(info.RouteCache, func(i, j int) bool {
+		a, b := info.RouteCache[i], info.RouteCache[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	for filter, name := range debugFilterNames {
+		info.FilterHits[name] = s.filterHits[filter].Load()
+	}
+	s.spansPerRoute.Range(func(route, counter any) bool {
+		info.SpansPerRoute[route.(string)] = counter.(*atomic.Int64).Load()
+		return true
+	})
+	return info
+}
+
+// debugRegistry holds the stats of the middlewares created with
+// `WithDebugStats`.
+var debugRegistry = &debugStatsRegistry{}
+
+type debugStatsRegistry struct {
+	mu    sync.Mutex
+	stats []*debugStats
+}
+
+func (r *debugStatsRegistry) add(stats *debugStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats = append(r.stats, stats)
+}
+
+func (r *debugStatsRegistry) report() []MiddlewareDebugInfo {
+	r.mu.Lock()
+	stats := append([]*debugStats(nil), r.stats...)
+	r.mu.Unlock()
+
+	infos := make([]MiddlewareDebugInfo, 0, len(stats))
+	for _, s := range stats {
+		infos = append(infos, s.info())
+	}
+	return infos
+}
+
+// activeOptions returns the names of the options affecting the config.
+func (cfg config) activeOptions() []string {
+	options := []struct {
+		name   string
+		active bool
+	}{
+		{"WithTracer", cfg.tracer != nil},
+		{"WithTracerName", len(cfg.tracerName) > 0},
+		{"WithTracerProvider", cfg.tracerProvider != nil},
+		{"WithDynamicTracerProvider", cfg.dynamicTracerProvider},
+		{"WithTracePropagationOnly", cfg.tracePropagationOnly},
+		{"WithSecondaryTracerProvider", cfg.secondaryTracerProvider != nil},
+		{"WithChiRoutes", cfg.chiRoutes != nil},
+		{"WithMountPrefix", len(cfg.mountPrefix) > 0},
+		{"WithNotFoundRouteLabel", len(cfg.notFoundRoute) > 0},
+		{"WithRequestMethodInSpanName", cfg.requestMethodInSpanName},
+		{"WithFilter", len(cfg.filters) > 0},
+		{"WithRouteFilter", len(cfg.routeFilters) > 0},
+		{"WithHealthEndpointsFiltered", len(cfg.healthPaths) > 0},
+		{"WithRouteConfig", len(cfg.routeConfigs) > 0},
+		{"WithRouteAttributes", len(cfg.routeAttributes) > 0},
+		{"WithTraceResponseHeaders", len(cfg.traceIDResponseHeaderKey) > 0 || cfg.traceparentResponseHeader},
+		{"WithPublicEndpointFn", cfg.publicEndpointFn != nil},
+		{"WithInternalRequestFn", cfg.internalRequestFn != nil},
+		{"WithSpanKindFn", cfg.spanKindFn != nil},
+		{"WithTLSClientIdentity", cfg.tlsClientIdentity},
+		{"WithClientIP", cfg.clientIP != nil},
+		{"WithNetworkAttributes", cfg.networkAttributes},
+		{"WithStaticAttributes", len(cfg.staticAttributes) > 0},
+		{"WithSpanAttributesFn", cfg.spanAttributesFn != nil},
+		{"WithSpanStartOptionsFn", cfg.spanStartOptionsFn != nil},
+		{"WithLifecycleEvents", cfg.lifecycleEvents},
+		{"WithSpanStatusFn", cfg.spanStatusFn != nil},
+		{"WithPostRouteEnrichment", cfg.postRouteEnrichment != nil},
+		{"WithErrorHook", cfg.errorHook != nil},
+		{"WithAttributeFilter", cfg.attributeFilter != nil},
+		{"WithScopeAttributes", len(cfg.scopeAttributes) > 0},
+		{"WithDynamicServerName", cfg.dynamicServerName != nil},
+		{"WithServerNameFn", cfg.serverNameFn != nil},
+		{"WithClock", cfg.clock != nil},
+		{"WithMeterProvider", cfg.meterProvider != nil},
+		{"WithOverheadMetric", cfg.overheadMetric},
+		{"WithRequestQueueTimeMetric", len(cfg.requestStartHeader) > 0},
+		{"WithShadowTraffic", cfg.shadowRequestFn != nil},
+		{"WithCapturedRequestHeaders", len(cfg.capturedRequestHeaders) > 0},
+		{"WithCapturedResponseHeaders", len(cfg.capturedResponseHeaders) > 0},
+		{"WithCapturedResponseTrailers", len(cfg.capturedResponseTrailers) > 0},
+		{"WithSSEInstrumentation", cfg.sseInstrumentation},
+		{"WithWebsocketSpanMode", cfg.websocketSpanMode != 0},
+		{"WithMiddlewareSpans", cfg.middlewareSpans},
+		{"WithWrappedHandlerSpan", cfg.handlerSpan},
+		{"WithCarrierFn", cfg.carrierFn != nil},
+		{"WithPropagatorsOrdered", isOrderedPropagators(cfg.propagators)},
+		{"WithBodyCapture", cfg.bodyCaptureMaxBytes > 0},
+		{"WithProblemDetailsCapture", cfg.problemDetailsMaxBytes > 0},
+		{"WithResponseBodyStatusFn", cfg.bodyStatusFn != nil},
+		{"WithGraphQLSupport", len(cfg.graphQLPath) > 0},
+		{"WithRequestID", len(cfg.requestIDHeader) > 0},
+		{"WithIdempotencyKeyCapture", len(cfg.idempotencyKeyHeader) > 0},
+		{"WithIdempotencyKeyUnhashed", cfg.idempotencyKeyUnhashed},
+		{"WithIdempotencyKeyRetryLinks", cfg.idempotencyRetryWindow > 0},
+		{"WithBatchLinksFromHeaders", len(cfg.batchLinkHeaders) > 0},
+		{"WithBatchLinksFromJSON", len(cfg.batchLinkJSONField) > 0},
+		{"WithAttributeValueLengthLimit", cfg.attributeValueLengthLimit > 0},
+		{"WithServerTimingHeader", cfg.serverTimingHeader},
+		{"WithPhaseTimings", cfg.phaseTimings},
+		{"WithURLParamsAsAttributes", len(cfg.urlParamsAllowlist) > 0},
+		{"WithRoutePatternNormalizer", cfg.routeNormalizer != nil},
+		{"WithMaxRouteCardinality", cfg.routeLimiter != nil},
+		{"WithRouteCache", cfg.routeCache != nil},
+		{"WithSlogLogger", cfg.slogLogger != nil},
+		{"WithRequestLogging", cfg.loggerProvider != nil},
+		{"WithCompressionAttributes", cfg.compressionAttributes},
+		{"WithQueryRecording", cfg.queryRecordingMode != QueryRecordingOff},
+		{"WithMethodOverride", len(cfg.methodOverrideHeaders) > 0},
+		{"WithLatencyForcedSampling", cfg.latencySamplingThreshold > 0},
+		{"WithLowAllocationMode", cfg.lowAllocationMode},
+	}
+	active := []string{}
+	for _, opt := range options {
+		if opt.active {
+			active = append(active, opt.name)
+		}
+	}
+	return active
+}
+
+func isOrderedPropagators(p any) bool {
+	_, ok := p.(orderedPropagators)
+	return ok
+}
@@ -0,0 +1,69 @@
+package otelchitrace
+
+import (
+	"github.com/riandyrn/otelchi/internal/drain"
+)
+
+// ServerDrainingKey is the attribute key used for marking requests served
+// while the server is draining.
+const ServerDrainingKey = drain.Key
+
+// ServerDrainingInFlightKey is the attribute key of the number of requests in
+// flight (including the request itself) when the request served while the
+// server is draining is started. It is recorded along with
+// `server.draining=true`, so the slow or failed requests during deployments
+// could be correlated with the load the server was shutting down with.
+const ServerDrainingInFlightKey = drain.InFlightKey
+
+// MarkDraining marks the server as draining, usually it is called right before
+// calling `http.Server.Shutdown`. Every request served after this call will
+// have `server.draining=true` attribute on both its span and metrics, this is
+// useful for explaining latency or error anomalies during deployments.
+//
+// The number of requests in flight when the server is marked as draining is
+// snapshotted, see `DrainStatus`.
+func MarkDraining() {
+	drain.Mark()
+}
+
+// UnmarkDraining reverts the effect of `MarkDraining`, e.g. when the server
+// shutdown is cancelled.
+func UnmarkDraining() {
+	drain.Unmark()
+}
+
+// IsDraining returns true when the server has been marked as draining.
+func IsDraining() bool {
+	return drain.Active()
+}
+
+// DrainSnapshot is the snapshot of the draining state returned by
+// `DrainStatus`.
+type DrainSnapshot struct {
+	// Draining is true when the server has been marked as draining.
+	Draining bool
+
+	// InFlight is the number of requests currently traced by the middleware
+	// across all the routers in the process.
+	InFlight int64
+
+	// InFlightAtMark is the number of requests traced by the middleware
+	// which were in flight when `MarkDraining` was called, it is zero when
+	// the server has never been marked as draining.
+	InFlightAtMark int64
+}
+
+// DrainStatus returns the snapshot of the draining state, e.g. for logging
+// the requests left behind when the shutdown deadline is exceeded:
+//
+//	otelchitrace.MarkDraining()
+//	if err := server.Shutdown(ctx); err != nil {
+//		log.Printf("shutdown: %v, drain status: %+v", err, otelchitrace.DrainStatus())
+//	}
+func DrainStatus() DrainSnapshot {
+	return DrainSnapshot{
+		Draining:       drain.Active(),
+		InFlight:       drain.InFlight(),
+		InFlightAtMark: drain.InFlightAtMark(),
+	}
+}
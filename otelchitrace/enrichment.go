@@ -0,0 +1,45 @@
+package otelchitrace
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+type serverSpanCtxKey struct{}
+
+// EnrichSpan adds the given attributes to the server span started by the
+// middleware for the request owning the given context, even when the context
+// carries a child span (e.g. the ones of `WrapMiddleware` or
+// `WrapHandler`). This allows the downstream middlewares (e.g. the
+// authentication middleware) to record the established identity on the
+// server span regardless of their order, e.g:
+//
+//	otelchitrace.EnrichSpan(r.Context(), attribute.String("enduser.id", user.ID))
+//
+// When the request is not handled by the middleware, the attributes are added
+// to the span in the given context.
+func EnrichSpan(ctx context.Context, attrs ...attribute.KeyValue) {
+	span, ok := ctx.Value(serverSpanCtxKey{}).(oteltrace.Span)
+	if !ok {
+		span = oteltrace.SpanFromContext(ctx)
+	}
+	span.SetAttributes(attrs...)
+}
+
+// WithPostRouteEnrichment specifies the function invoked with the server span
+// after the inner handlers are completed & the route is resolved, for every
+// request traced by the middleware. Unlike `WithSpanAttributesFn`, the
+// function could observe the outcome of the inner handlers, e.g. the route
+// parameters or the response metadata (see `ResponseInfoFromContext`).
+//
+// The request passed to the function is the one received by the middleware,
+// so the values stored by the downstream middlewares into their own request
+// context are not visible, use `EnrichSpan` from those middlewares instead.
+func WithPostRouteEnrichment(fn func(r *http.Request, span oteltrace.Span)) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.postRouteEnrichment = fn
+	})
+}
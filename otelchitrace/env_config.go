@@ -0,0 +1,108 @@
+package otelchitrace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The environment variables read by the middleware for its default settings,
+// see `Middleware` for details.
+const (
+	envFilterPaths             = "OTELCHI_FILTER_PATHS"
+	envTraceResponseHeaders    = "OTELCHI_TRACE_RESPONSE_HEADERS"
+	envCaptureHeaders          = "OTELCHI_CAPTURE_HEADERS"
+	envCaptureResponseHeaders  = "OTELCHI_CAPTURE_RESPONSE_HEADERS"
+	envRequestMethodInSpanName = "OTELCHI_REQUEST_METHOD_IN_SPAN_NAME"
+	envQueryRecording          = "OTELCHI_QUERY_RECORDING"
+)
+
+// configFromEnv returns the config holding the settings read from the
+// environment variables. The invalid variables are left out of the config &
+// reported in the returned error.
+func configFromEnv() (Config, error) {
+	var cfg Config
+	var errs []error
+
+	for _, expr := range envList(envFilterPaths) {
+		if _, err := regexp.Compile(expr); err != nil {
+			errs = append(errs, envError(envFilterPaths, expr, err))
+			continue
+		}
+		cfg.FilterPathRegexps = append(cfg.FilterPathRegexps, expr)
+	}
+
+	cfg.CapturedRequestHeaders = envList(envCaptureHeaders)
+	cfg.CapturedResponseHeaders = envList(envCaptureResponseHeaders)
+
+	if mode := strings.TrimSpace(os.Getenv(envQueryRecording)); len(mode) > 0 {
+		if _, err := parseQueryRecordingMode(mode); err != nil {
+			errs = append(errs, envError(envQueryRecording, mode, err))
+		} else {
+			cfg.QueryRecording = mode
+		}
+	}
+
+	traceResponseHeaders, err := envBool(envTraceResponseHeaders)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if traceResponseHeaders {
+		cfg.TraceResponseHeaders = &TraceHeaderConfig{}
+	}
+
+	cfg.RequestMethodInSpanName, err = envBool(envRequestMethodInSpanName)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return cfg, errors.Join(errs...)
+}
+
+// envOptions returns the functional options equivalent to the valid settings
+// read from the environment variables, the invalid ones are reported in the
+// returned error.
+func envOptions() ([]Option, error) {
+	cfg, envErr := configFromEnv()
+	opts, err := cfg.Options()
+	if err != nil {
+		// should not happen since the config is already validated
+		return nil, errors.Join(envErr, err)
+	}
+	return opts, envErr
+}
+
+// envList returns the comma-separated values of the environment variable,
+// the empty values are omitted.
+func envList(name string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(name), ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// envBool returns the boolean value of the environment variable, it is false
+// when the variable is not set.
+func envBool(name string) (bool, error) {
+	v := strings.TrimSpace(os.Getenv(name))
+	if len(v) == 0 {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, envError(name, v, err)
+	}
+	return b, nil
+}
+
+// envError returns the error describing the invalid value of the
+// environment variable.
+func envError(name, value string, err error) error {
+	return fmt.Errorf("invalid environment variable %s=%q: %w", name, value, err)
+}
@@ -0,0 +1,214 @@
+package otelchitrace
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// GraphQLOperationNameKey is the attribute key of the name of the
+	// GraphQL operation executed by the request.
+	GraphQLOperationNameKey = attribute.Key("graphql.operation.name")
+
+	// GraphQLOperationTypeKey is the attribute key of the type of the
+	// GraphQL operation executed by the request, i.e. `query`, `mutation`
+	// or `subscription`.
+	GraphQLOperationTypeKey = attribute.Key("graphql.operation.type")
+)
+
+// maxGraphQLBodyBytes is the maximum size of the GraphQL request body parsed
+// for the operation, the operation of the larger body is not recorded.
+const maxGraphQLBodyBytes = 64 << 10
+
+// WithGraphQLSupport makes the middleware aware of the GraphQL endpoint
+// served at the given path, e.g. `/graphql`. The operation name & type of the
+// request to the endpoint are parsed from the body (or the query parameters
+// for GET request) and recorded as `graphql.operation.name` &
+// `graphql.operation.type` attributes, and the operation name is appended to
+// the span name (e.g. `/graphql MyOperation`, or `POST /graphql MyOperation`
+// with `WithRequestMethodInSpanName`). Otherwise all the GraphQL traffic
+// would collapse into the single span name.
+//
+// The body is read before the span is started (at most 64 KiB), it is
+// restored for the handler. The operation of the larger body is not
+// recorded.
+func WithGraphQLSupport(path string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.graphQLPath = path
+	})
+}
+
+// graphQLOperation is the GraphQL operation executed by the request.
+type graphQLOperation struct {
+	Name string
+	Type string
+}
+
+// attributes returns the span attributes describing the operation.
+func (op graphQLOperation) attributes() []attribute.KeyValue {
+	attrs := []attribute.KeyValue{GraphQLOperationTypeKey.String(op.Type)}
+	if len(op.Name) > 0 {
+		attrs = append(attrs, GraphQLOperationNameKey.String(op.Name))
+	}
+	return attrs
+}
+
+// spanName returns the given span name followed by the operation name.
+func (op graphQLOperation) spanName(name string) string {
+	if len(op.Name) == 0 {
+		return name
+	}
+	return name + " " + op.Name
+}
+
+// graphQLOperation returns the GraphQL operation executed by the request, it
+// returns false when the request isn't sent to the GraphQL endpoint or its
+// operation couldn't be parsed.
+func (tw traceware) graphQLOperation(r *http.Request) (graphQLOperation, bool) {
+	if len(tw.graphQLPath) == 0 || r.URL.Path != tw.graphQLPath {
+		return graphQLOperation{}, false
+	}
+
+	var document, operationName string
+	switch {
+	case r.Method == http.MethodGet:
+		query := r.URL.Query()
+		document, operationName = query.Get("query"), query.Get("operationName")
+	case r.Body != nil && r.Body != http.NoBody:
+		// read one more byte for detecting the body exceeding the limit
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxGraphQLBodyBytes+1))
+		r.Body = restoredBody{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+		if err != nil || len(body) > maxGraphQLBodyBytes {
+			return graphQLOperation{}, false
+		}
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType == "application/graphql" {
+			document = string(body)
+			break
+		}
+		var params struct {
+			Query         string `json:"query"`
+			OperationName string `json:"operationName"`
+		}
+		if err := json.Unmarshal(body, &params); err != nil {
+			return graphQLOperation{}, false
+		}
+		document, operationName = params.Query, params.OperationName
+	}
+	return parseGraphQLOperation(document, operationName)
+}
+
+// parseGraphQLOperation returns the operation of the given GraphQL document
+// having the given name, or the first operation when the name is empty.
+func parseGraphQLOperation(document, operationName string) (graphQLOperation, bool) {
+	ops := graphQLOperations(document)
+	for _, op := range ops {
+		if len(operationName) == 0 || op.Name == operationName {
+			return op, true
+		}
+	}
+	return graphQLOperation{}, false
+}
+
+// graphQLOperations returns the operations defined in the given GraphQL
+// document, it only scans the top-level definitions, so the selection sets
+// are skipped without being parsed.
+func graphQLOperations(document string) []graphQLOperation {
+	var (
+		ops   []graphQLOperation
+		depth int
+		op    *graphQLOperation
+	)
+	for i := 0; i < len(document); {
+		c := document[i]
+		switch {
+		case c == '#':
+			// skip the comment until the end of line
+			for i < len(document) && document[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			i = skipGraphQLString(document, i)
+		case c == '{':
+			// the top-level selection set ends the definition, the one
+			// without operation keyword is the anonymous query
+			if depth == 0 {
+				if op == nil {
+					op = &graphQLOperation{Type: "query"}
+				}
+				ops = append(ops, *op)
+				op = nil
+			}
+			depth++
+			i++
+		case c == '(':
+			depth++
+			i++
+		case c == '}' || c == ')':
+			depth--
+			i++
+		case depth == 0 && isGraphQLNameStart(c):
+			start := i
+			for i < len(document) && isGraphQLNameChar(document[i]) {
+				i++
+			}
+			name := document[start:i]
+			switch {
+			case op != nil && len(op.Name) == 0 && op.Type != "fragment":
+				op.Name = name
+			case op == nil && (name == "query" || name == "mutation" || name == "subscription" || name == "fragment"):
+				op = &graphQLOperation{Type: name}
+			}
+		default:
+			i++
+		}
+	}
+
+	// the fragments are not operations
+	res := ops[:0]
+	for _, op := range ops {
+		if op.Type != "fragment" {
+			res = append(res, op)
+		}
+	}
+	return res
+}
+
+// skipGraphQLString returns the index following the string (or block string)
+// starting at the given index.
+func skipGraphQLString(document string, i int) int {
+	if len(document) >= i+3 && document[i:i+3] == `"""` {
+		for i += 3; i < len(document); i++ {
+			if document[i] == '\\' {
+				i++
+				continue
+			}
+			if len(document) >= i+3 && document[i:i+3] == `"""` {
+				return i + 3
+			}
+		}
+		return i
+	}
+	for i++; i < len(document); i++ {
+		switch document[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return i
+}
+
+func isGraphQLNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isGraphQLNameChar(c byte) bool {
+	return isGraphQLNameStart(c) || (c >= '0' && c <= '9')
+}
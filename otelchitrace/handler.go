@@ -0,0 +1,52 @@
+package otelchitrace
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NewHandler wraps the given handler with the tracing middleware, it mirrors
+// `otelhttp.NewHandler` but resolves the chi route pattern of the request.
+// It is useful for instrumenting at the server level, e.g. when the chi
+// router is mounted inside a non-chi mux:
+//
+//	router := chi.NewRouter()
+//	router.Get("/users/{id}", getUser)
+//	mux := http.NewServeMux()
+//	mux.Handle("/", router)
+//	http.ListenAndServe(":8080", otelchitrace.NewHandler(mux, "my-server"))
+//
+// When the handler is a chi router (i.e. implements `chi.Routes`), it is
+// passed to the middleware through `WithChiRoutes` so the route pattern is
+// resolved before the span is started. Otherwise the route pattern is
+// resolved from the first chi router serving the request.
+func NewHandler(h http.Handler, serverName string, opts ...Option) http.Handler {
+	routes, _ := h.(chi.Routes)
+	if routes != nil {
+		// the routes specified by the caller take precedence
+		opts = append([]Option{WithChiRoutes(routes)}, opts...)
+	}
+	return routeContextHandler{
+		routes:  routes,
+		handler: Middleware(serverName, opts...)(h),
+	}
+}
+
+// routeContextHandler makes sure the request carries the chi routing context
+// before it reaches the tracing middleware, so the route pattern populated
+// by the downstream chi router is visible to the middleware.
+type routeContextHandler struct {
+	routes  chi.Routes
+	handler http.Handler
+}
+
+func (h routeContextHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if chi.RouteContext(r.Context()) == nil {
+		rctx := chi.NewRouteContext()
+		rctx.Routes = h.routes
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	}
+	h.handler.ServeHTTP(w, r)
+}
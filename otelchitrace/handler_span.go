@@ -0,0 +1,136 @@
+package otelchitrace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+type handlerSpanCtxKey struct{}
+
+// WithWrappedHandlerSpan enables creating an internal child span around the
+// route handlers wrapped by `WrapHandler`, named after the handler function
+// (e.g. `github.com/acme/app.(*Server).GetUser`). This allows telling the time
+// spent in the handler apart from the time spent in chi routing & the
+// middlewares, which is only covered by the server span.
+//
+// The span is not created automatically: chi doesn't allow the middleware to
+// wrap the route handler it dispatches to, so every route handler which
+// should get the span must be wrapped by `WrapHandler`. When this option is
+// enabled but no route handler is wrapped, the error is reported once to the
+// global OpenTelemetry error handler.
+func WithWrappedHandlerSpan(enabled bool) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.handlerSpan = enabled
+	})
+}
+
+// wrappedHandlers is the number of route handlers wrapped by `WrapHandler`.
+var wrappedHandlers atomic.Int64
+
+// WrapHandler wraps the given route handler so it is executed inside the
+// handler span, see `WithWrappedHandlerSpan`. It has the signature of chi
+// middleware, so it could be applied to the route handlers through
+// `chi.Router.With` or `chi.Router.Group`, e.g:
+//
+//	router := chi.NewRouter()
+//	router.Use(otelchitrace.Middleware("my-server", otelchitrace.WithWrappedHandlerSpan(true)))
+//	router.Use(authMiddleware)
+//	router.Group(func(r chi.Router) {
+//		r.Use(otelchitrace.WrapHandler)
+//		r.Get("/users/{id}", srv.GetUser)
+//	})
+//
+// The inline middlewares are applied to the route handler when the route is
+// registered, so the handler name is resolved once per route. WrapHandler
+// must be the last inline middleware, otherwise the span covers & is named
+// after the subsequent inline middlewares.
+//
+// The handler is executed as it is when the request is not traced by the
+// middleware created with `WithWrappedHandlerSpan` option.
+func WrapHandler(next http.Handler) http.Handler {
+	wrappedHandlers.Add(1)
+	name := handlerName(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spans, ok := r.Context().Value(handlerSpanCtxKey{}).(*childSpans)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, span := spans.start(r.Context(), name)
+		defer spans.end(span)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// handlerName returns the name of the given handler, the function name is
+// used for `http.HandlerFunc`, otherwise the type name is used.
+func handlerName(handler http.Handler) string {
+	if fn, ok := handler.(http.HandlerFunc); ok {
+		if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+			// method values are suffixed by "-fm"
+			return strings.TrimSuffix(f.Name(), "-fm")
+		}
+	}
+	return fmt.Sprintf("%T", handler)
+}
+
+// childSpans starts the internal child spans of the server span, it is
+// created once per middleware & shared by the requests through the request
+// context.
+type childSpans struct {
+	tracer oteltrace.Tracer
+	clock  Clock
+	// unwrappedOnce reports the handler span enabled without any handler
+	// wrapped by `WrapHandler` once
+	unwrappedOnce sync.Once
+}
+
+func newChildSpans(cfg config, tracer oteltrace.Tracer) *childSpans {
+	if !cfg.handlerSpan && !cfg.middlewareSpans {
+		return nil
+	}
+	return &childSpans{tracer: tracer, clock: cfg.clock}
+}
+
+// checkWrappedHandlers reports the error when no route handler is wrapped by
+// `WrapHandler`, so the enabled handler span is never created. It should be
+// called once the request is handled, when the routes have been registered.
+func (c *childSpans) checkWrappedHandlers() {
+	if wrappedHandlers.Load() > 0 {
+		return
+	}
+	c.unwrappedOnce.Do(func() {
+		otel.Handle(errors.New("no route handler is wrapped by WrapHandler, the handler span enabled by WithWrappedHandlerSpan is never created"))
+	})
+}
+
+// start starts the internal span with the given name.
+func (c *childSpans) start(ctx context.Context, name string) (context.Context, oteltrace.Span) {
+	spanOpts := []oteltrace.SpanStartOption{
+		oteltrace.WithSpanKind(oteltrace.SpanKindInternal),
+	}
+	if c.clock != nil {
+		spanOpts = append(spanOpts, oteltrace.WithTimestamp(c.clock.Now()))
+	}
+	return c.tracer.Start(ctx, name, spanOpts...)
+}
+
+// end ends the given span started by start.
+func (c *childSpans) end(span oteltrace.Span) {
+	if c.clock != nil {
+		span.End(oteltrace.WithTimestamp(c.clock.Now()))
+		return
+	}
+	span.End()
+}
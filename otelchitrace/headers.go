@@ -0,0 +1,67 @@
+package otelchitrace
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RedactedHeaderValue is the value recorded in place of sensitive header
+// values captured by `WithCapturedRequestHeaders` & `WithCapturedResponseHeaders`.
+const RedactedHeaderValue = "[REDACTED]"
+
+// sensitiveHeaders are the headers whose values are always redacted when
+// captured, the keys are in canonical form.
+var sensitiveHeaders = map[string]struct{}{
+	"Authorization":       {},
+	"Proxy-Authorization": {},
+	"Cookie":              {},
+	"Set-Cookie":          {},
+}
+
+// WithCapturedRequestHeaders enables recording the given request headers as
+// `http.request.header.<key>` span attributes, where `<key>` is the lowercase
+// header name, e.g. `http.request.header.x-request-id`. The values of
+// sensitive headers (`Authorization`, `Proxy-Authorization`, `Cookie`) are
+// always redacted.
+//
+// When this option is used multiple times, the headers are accumulated.
+func WithCapturedRequestHeaders(headers ...string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.capturedRequestHeaders = append(cfg.capturedRequestHeaders, headers...)
+	})
+}
+
+// WithCapturedResponseHeaders enables recording the given response headers as
+// `http.response.header.<key>` span attributes, where `<key>` is the lowercase
+// header name, e.g. `http.response.header.content-type`. The values of
+// sensitive headers (`Set-Cookie`) are always redacted.
+//
+// When this option is used multiple times, the headers are accumulated.
+func WithCapturedResponseHeaders(headers ...string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.capturedResponseHeaders = append(cfg.capturedResponseHeaders, headers...)
+	})
+}
+
+// headerAttributes returns the attributes of the given headers which are
+// present in h, the attribute keys are prefixed with the given prefix.
+func headerAttributes(prefix string, h http.Header, keys []string) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for _, key := range keys {
+		values := h.Values(key)
+		if len(values) == 0 {
+			continue
+		}
+		if _, ok := sensitiveHeaders[http.CanonicalHeaderKey(key)]; ok {
+			redacted := make([]string, len(values))
+			for i := range redacted {
+				redacted[i] = RedactedHeaderValue
+			}
+			values = redacted
+		}
+		attrs = append(attrs, attribute.StringSlice(prefix+strings.ToLower(key), values))
+	}
+	return attrs
+}
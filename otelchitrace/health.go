@@ -0,0 +1,26 @@
+package otelchitrace
+
+import (
+	"github.com/riandyrn/otelchi/internal/health"
+)
+
+// HealthCheckKey is the attribute key used for marking the health-check
+// requests whose tracing is forced, see `WithHealthEndpointsFiltered`.
+const HealthCheckKey = health.Key
+
+// WithHealthEndpointsFiltered excludes the requests to the health-check
+// endpoints with the given paths from tracing. If no paths are given,
+// `/healthz`, `/livez`, `/readyz` & `/ping` are used. The paths are matched
+// exactly against the request URL path.
+//
+// The tracing is forced when the request carries a sampled trace context
+// (e.g. a probe sent manually while debugging), in this case the span is
+// marked with the `http.route.health_check=true` attribute.
+//
+// Use `otelchimetric.WithHealthEndpointsFiltered` for excluding the
+// health-check requests from metrics.
+func WithHealthEndpointsFiltered(paths ...string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.healthPaths = health.NewPaths(paths...)
+	})
+}
@@ -0,0 +1,141 @@
+package otelchitrace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// DefaultIdempotencyKeyHeader is the request header carrying the idempotency
+// key when no custom header is specified in `WithIdempotencyKeyCapture`.
+const DefaultIdempotencyKeyHeader = "Idempotency-Key"
+
+const (
+	// IdempotencyKeyKey is the span attribute key of the idempotency key of
+	// the request, the key is hashed unless `WithIdempotencyKeyUnhashed` is
+	// used.
+	IdempotencyKeyKey = attribute.Key("http.request.idempotency_key")
+
+	// IdempotencyRetryKey is the span attribute key marking the request
+	// repeating an idempotency key observed within the window specified in
+	// `WithIdempotencyKeyRetryLinks`.
+	IdempotencyRetryKey = attribute.Key("http.request.idempotency_retry")
+)
+
+// maxIdempotencyKeys is the maximum number of idempotency keys remembered for
+// linking the retries, the keys beyond the limit are not remembered until the
+// expired ones are evicted.
+const maxIdempotencyKeys = 10000
+
+// WithIdempotencyKeyCapture enables recording the idempotency key of the
+// request taken from the given header as `http.request.idempotency_key` span
+// attribute. The key is recorded as the hex encoded SHA-256 hash (truncated to
+// 16 bytes) so it could be correlated without exposing the key itself. If the
+// header is empty, `Idempotency-Key` header is used.
+func WithIdempotencyKeyCapture(header string) Option {
+	return optionFunc(func(cfg *config) {
+		if len(header) == 0 {
+			header = DefaultIdempotencyKeyHeader
+		}
+		cfg.idempotencyKeyHeader = header
+	})
+}
+
+// WithIdempotencyKeyUnhashed makes `WithIdempotencyKeyCapture` record the
+// idempotency key as is instead of its hash.
+func WithIdempotencyKeyUnhashed() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.idempotencyKeyUnhashed = true
+	})
+}
+
+// WithIdempotencyKeyRetryLinks makes `WithIdempotencyKeyCapture` link the span
+// of the request repeating an idempotency key observed within the given
+// window to the span of the previous request, and mark it with
+// `http.request.idempotency_retry=true` attribute. This makes the client retry
+// storms visible in the traces. The keys are remembered per middleware
+// instance.
+func WithIdempotencyKeyRetryLinks(window time.Duration) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.idempotencyRetryWindow = window
+	})
+}
+
+// idempotencyKey returns the idempotency key of the given request as it is
+// recorded, it returns false when the capture is disabled or the request
+// doesn't have the key.
+func (tw traceware) idempotencyKey(r *http.Request) (string, bool) {
+	if len(tw.idempotencyKeyHeader) == 0 {
+		return "", false
+	}
+	key := r.Header.Get(tw.idempotencyKeyHeader)
+	if len(key) == 0 {
+		return "", false
+	}
+	if tw.idempotencyKeyUnhashed {
+		return key, true
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16]), true
+}
+
+// idempotencyKeys remembers the span of the last request observed for each
+// idempotency key.
+type idempotencyKeys struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	seen map[string]idempotencyKeySeen
+}
+
+type idempotencyKeySeen struct {
+	spanCtx oteltrace.SpanContext
+	at      time.Time
+}
+
+// newIdempotencyKeys returns the store of the idempotency keys, it returns nil
+// when the retry links are not enabled.
+func newIdempotencyKeys(cfg config) *idempotencyKeys {
+	if len(cfg.idempotencyKeyHeader) == 0 || cfg.idempotencyRetryWindow <= 0 {
+		return nil
+	}
+	return &idempotencyKeys{
+		window: cfg.idempotencyRetryWindow,
+		seen:   map[string]idempotencyKeySeen{},
+	}
+}
+
+// previous returns the span of the request having the given key observed
+// within the window before now.
+func (s *idempotencyKeys) previous(key string, now time.Time) (oteltrace.SpanContext, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen, ok := s.seen[key]
+	if !ok || now.Sub(seen.at) > s.window {
+		return oteltrace.SpanContext{}, false
+	}
+	return seen.spanCtx, true
+}
+
+// observe remembers the span of the request having the given key.
+func (s *idempotencyKeys) observe(key string, spanCtx oteltrace.SpanContext, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.seen[key]; !ok && len(s.seen) >= maxIdempotencyKeys {
+		// evict the expired keys to make room
+		for k, seen := range s.seen {
+			if now.Sub(seen.at) > s.window {
+				delete(s.seen, k)
+			}
+		}
+		if len(s.seen) >= maxIdempotencyKeys {
+			return
+		}
+	}
+	s.seen[key] = idempotencyKeySeen{spanCtx: spanCtx, at: now}
+}
@@ -0,0 +1,67 @@
+package otelchitrace
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// ForcedSamplingKey is the attribute key marking the synthetic span emitted
+// for the slow request which was not sampled, see
+// `WithLatencyForcedSampling`.
+const ForcedSamplingKey = attribute.Key("otelchi.sampling.forced")
+
+// WithLatencyForcedSampling enables emitting a synthetic sampled span for the
+// request which takes at least the given threshold to complete but whose
+// span was not sampled, so the slow outliers missed by the head sampling are
+// still visible.
+//
+// The synthetic span has the same name, kind, timestamps & status as the
+// original span, it carries the request, route & status code attributes and
+// is marked with the `otelchi.sampling.forced` attribute. The attributes set
+// on the original span by the handler are not available since the span was
+// not recorded.
+//
+// The synthetic span is started as the child of the unsampled span with the
+// sampled flag set, so it is recorded by the samplers respecting the parent
+// decision such as the default `ParentBased` sampler. Its parent span is
+// never exported, so the backends may display it as an orphan span.
+func WithLatencyForcedSampling(threshold time.Duration) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.latencySamplingThreshold = threshold
+	})
+}
+
+// forceSampleSlowRequest emits the synthetic sampled span when the span was
+// not sampled & the request takes at least the threshold to complete.
+func (tw traceware) forceSampleSlowRequest(span oteltrace.Span, name string, kind oteltrace.SpanKind, startTime time.Time, attrs []attribute.KeyValue, code codes.Code, description string) {
+	if tw.latencySamplingThreshold <= 0 {
+		return
+	}
+	spanCtx := span.SpanContext()
+	if !spanCtx.IsValid() || spanCtx.IsSampled() {
+		return
+	}
+	endTime := tw.now()
+	if endTime.Sub(startTime) < tw.latencySamplingThreshold {
+		return
+	}
+
+	parentCtx := oteltrace.ContextWithRemoteSpanContext(
+		context.Background(),
+		spanCtx.WithTraceFlags(spanCtx.TraceFlags().WithSampled(true)),
+	)
+	_, forced := tw.tracer.Start(
+		parentCtx,
+		name,
+		oteltrace.WithSpanKind(kind),
+		oteltrace.WithAttributes(attrs...),
+		oteltrace.WithAttributes(ForcedSamplingKey.Bool(true)),
+		oteltrace.WithTimestamp(startTime),
+	)
+	forced.SetStatus(code, description)
+	forced.End(oteltrace.WithTimestamp(endTime))
+}
@@ -0,0 +1,40 @@
+package otelchitrace
+
+import (
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// HeadersReadEventName is the name of the span event recorded once the
+	// request headers are read, right before the request is passed to the
+	// next handler.
+	HeadersReadEventName = "headers_read"
+
+	// FirstByteWrittenEventName is the name of the span event recorded once
+	// the first byte of the response is written, the time between the span
+	// start & this event is the time to first byte (TTFB) of the request.
+	FirstByteWrittenEventName = "first_byte_written"
+
+	// ResponseCompleteEventName is the name of the span event recorded once
+	// the next handler returns.
+	ResponseCompleteEventName = "response_complete"
+)
+
+// WithLifecycleEvents enables the span events recording the lifecycle
+// phases of the request: `headers_read`, `first_byte_written` and
+// `response_complete`. The event timestamps allow analyzing the time to first
+// byte per route directly from the spans.
+//
+// The `first_byte_written` event is not recorded when the handler doesn't
+// write any response body, e.g. for `204 No Content` response.
+func WithLifecycleEvents() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.lifecycleEvents = true
+	})
+}
+
+// recordLifecycleEvent records the lifecycle event with the given name into
+// the span.
+func (tw traceware) recordLifecycleEvent(span oteltrace.Span, name string) {
+	span.AddEvent(name, tw.clockEventOptions()...)
+}
@@ -0,0 +1,237 @@
+package otelchitrace
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+// WithLowAllocationMode enables building the request attributes of the
+// semantic conventions v1.20.0 (see `SemconvOld`) directly into the pooled
+// attribute buffer, reusing the attributes pre-computed from the static
+// server name, scheme & protocol version instead of allocating them on every
+// request.
+//
+// The attributes are the same as the default mode except `enduser.id`, which
+// is not recorded since decoding the basic authentication credentials
+// allocates. The attributes of the stable semantic conventions are not
+// affected by this option.
+//
+// When `WithRequestMethodInSpanName` is enabled, the "METHOD route" span names
+// are also cached per route (up to 1024 names) so they are not concatenated
+// on every request.
+func WithLowAllocationMode() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.lowAllocationMode = true
+	})
+}
+
+// attributesBuffer is the pooled buffer for the span attributes built on
+// every request.
+type attributesBuffer struct {
+	attrs []attribute.KeyValue
+}
+
+// attributesBufferCap is the initial capacity of the attributes buffer, it
+// fits the attributes of a typical request.
+const attributesBufferCap = 16
+
+var attributesBufferPool = &sync.Pool{
+	New: func() interface{} {
+		return &attributesBuffer{attrs: make([]attribute.KeyValue, 0, attributesBufferCap)}
+	},
+}
+
+func getAttributesBuffer() *attributesBuffer {
+	return attributesBufferPool.Get().(*attributesBuffer)
+}
+
+// put returns the buffer into the pool, attrs is the final slice built on
+// top of the buffer which may have been grown.
+func (b *attributesBuffer) put(attrs []attribute.KeyValue) {
+	// drop the references to the attribute values
+	clear(attrs)
+	b.attrs = attrs[:0]
+	attributesBufferPool.Put(b)
+}
+
+// serverHostAttributes holds the attributes pre-computed from the static
+// server name.
+type serverHostAttributes struct {
+	serverName string
+	hostName   attribute.KeyValue
+	port       int
+}
+
+// newServerHostAttributes returns the attributes pre-computed from the given
+// server name, it returns nil when the server name could change per request.
+func newServerHostAttributes(cfg config, serverName string) *serverHostAttributes {
+	if !cfg.lowAllocationMode || cfg.dynamicServerName != nil || len(serverName) == 0 {
+		return nil
+	}
+	host, port := splitHostPortV120(serverName)
+	return &serverHostAttributes{
+		serverName: serverName,
+		hostName:   semconv.NetHostName(host),
+		port:       port,
+	}
+}
+
+// protocolVersionAttributes are the pre-computed protocol version attributes
+// of the known protocols.
+var protocolVersionAttributes = map[string]attribute.KeyValue{
+	"HTTP/1.0": semconv.NetProtocolVersion("1.0"),
+	"HTTP/1.1": semconv.NetProtocolVersion("1.1"),
+	"HTTP/2":   semconv.NetProtocolVersion("2.0"),
+	"HTTP/3":   semconv.NetProtocolVersion("3.0"),
+}
+
+// appendServerRequestAttributes appends the request attributes of the semantic
+// conventions v1.20.0 to attrs, it mirrors `httpconv.ServerRequest` except it
+// doesn't record `enduser.id`.
+func (tw traceware) appendServerRequestAttributes(attrs []attribute.KeyValue, serverName string, r *http.Request) []attribute.KeyValue {
+	// method, scheme & protocol
+	if len(r.Method) == 0 {
+		attrs = append(attrs, semconv.HTTPMethod(http.MethodGet))
+	} else {
+		attrs = append(attrs, semconv.HTTPMethod(r.Method))
+	}
+	if r.TLS != nil {
+		attrs = append(attrs, semconv.HTTPSchemeHTTPS)
+	} else {
+		attrs = append(attrs, semconv.HTTPSchemeHTTP)
+	}
+	if proto, ok := protocolVersionAttributes[r.Proto]; ok {
+		attrs = append(attrs, proto)
+	} else {
+		attrs = append(attrs, semconv.NetProtocolName(r.Proto))
+	}
+
+	// host name & port, the server name is prioritized over the request host
+	var port int
+	if static := tw.serverHost; static != nil && static.serverName == serverName {
+		attrs = append(attrs, static.hostName)
+		port = static.port
+	} else {
+		var host string
+		if len(serverName) == 0 {
+			host, port = splitHostPortV120(r.Host)
+		} else {
+			host, port = splitHostPortV120(serverName)
+		}
+		attrs = append(attrs, semconv.NetHostName(host))
+	}
+	if len(serverName) > 0 && port < 0 {
+		_, port = splitHostPortV120(r.Host)
+	}
+	if port > 0 && !(r.TLS != nil && port == 443) && !(r.TLS == nil && port == 80) {
+		attrs = append(attrs, semconv.NetHostPort(port))
+	}
+
+	// peer address
+	if peer, peerPort := splitHostPortV120(r.RemoteAddr); len(peer) > 0 {
+		attrs = append(attrs, semconv.NetSockPeerAddr(peer))
+		if peerPort > 0 {
+			attrs = append(attrs, semconv.NetSockPeerPort(peerPort))
+		}
+	}
+
+	if userAgent := r.UserAgent(); len(userAgent) > 0 {
+		attrs = append(attrs, semconv.UserAgentOriginal(userAgent))
+	}
+
+	// client ip, only the first address in `X-Forwarded-For` is used
+	clientIP := r.Header.Get("X-Forwarded-For")
+	if idx := strings.Index(clientIP, ","); idx >= 0 {
+		clientIP = clientIP[:idx]
+	}
+	if len(clientIP) > 0 {
+		attrs = append(attrs, semconv.HTTPClientIP(clientIP))
+	}
+
+	return attrs
+}
+
+// splitHostPortV120 splits the given address into host & port the same way as
+// `httpconv.ServerRequest`, the port is -1 when it is not present or invalid.
+// The address without port is detected upfront to avoid allocating the error
+// of `net.SplitHostPort`.
+func splitHostPortV120(hostport string) (string, int) {
+	if strings.HasPrefix(hostport, "[") {
+		addrEnd := strings.LastIndex(hostport, "]")
+		if addrEnd < 0 {
+			// invalid address
+			return "", -1
+		}
+		if !strings.Contains(hostport[addrEnd:], ":") {
+			return hostport[1:addrEnd], -1
+		}
+	} else if !strings.Contains(hostport, ":") {
+		return hostport, -1
+	}
+
+	host, portStr, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return "", -1
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return host, -1
+	}
+	return host, int(port)
+}
+
+// maxCachedSpanNames is the maximum number of span names kept by the span
+// name cache, the names of the routes beyond the limit are built per request.
+const maxCachedSpanNames = 1024
+
+// spanNameCache caches the "METHOD route" span names. The names are keyed by
+// method then route so the lookup doesn't need to build the name (or a
+// composite key) upfront.
+type spanNameCache struct {
+	mu    sync.RWMutex
+	names map[string]map[string]string
+	size  int
+}
+
+// newSpanNameCache returns the span name cache, it returns nil when the span
+// names don't contain the request method or the low allocation mode is not
+// enabled.
+func newSpanNameCache(cfg config) *spanNameCache {
+	if !cfg.lowAllocationMode || !cfg.requestMethodInSpanName {
+		return nil
+	}
+	return &spanNameCache{names: map[string]map[string]string{}}
+}
+
+// get returns the span name of the given method & route.
+func (c *spanNameCache) get(method, route string) string {
+	c.mu.RLock()
+	name, ok := c.names[method][route]
+	c.mu.RUnlock()
+	if ok {
+		return name
+	}
+
+	name = method + " " + route
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size >= maxCachedSpanNames {
+		return name
+	}
+	routes, ok := c.names[method]
+	if !ok {
+		routes = map[string]string{}
+		c.names[method] = routes
+	}
+	if _, ok := routes[route]; !ok {
+		routes[route] = name
+		c.size++
+	}
+	return name
+}
@@ -0,0 +1,65 @@
+package otelchitrace
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// MethodOverrideKey is the attribute key of the effective request method
+// specified by the method override header (e.g. `X-HTTP-Method-Override`)
+// of a tunneled request. The original request method is still recorded in
+// the request method attribute.
+const MethodOverrideKey = attribute.Key("http.request.method_override")
+
+// DefaultMethodOverrideHeader is the method override header used when
+// `WithMethodOverride` is called without any header.
+const DefaultMethodOverrideHeader = "X-HTTP-Method-Override"
+
+// WithMethodOverride enables the awareness of the method override headers,
+// which are used by the clients behind restrictive proxies for tunneling the
+// requests through `POST`. When any of the headers is present, the effective
+// method is recorded in the `http.request.method_override` attribute & used
+// in the span name when `WithRequestMethodInSpanName` is enabled.
+//
+// The headers are checked in the given order, if none is given
+// `X-HTTP-Method-Override` is used. Only the standard HTTP methods are
+// honored to keep the span names bounded.
+func WithMethodOverride(headers ...string) Option {
+	return optionFunc(func(cfg *config) {
+		if len(headers) == 0 {
+			headers = []string{DefaultMethodOverrideHeader}
+		}
+		cfg.methodOverrideHeaders = append(cfg.methodOverrideHeaders, headers...)
+	})
+}
+
+// overriddenMethod returns the effective method specified by the method
+// override headers of the request, it returns empty string when there is no
+// valid override.
+func (tw traceware) overriddenMethod(r *http.Request) string {
+	for _, header := range tw.methodOverrideHeaders {
+		value := r.Header.Get(header)
+		if len(value) == 0 {
+			continue
+		}
+		method := strings.ToUpper(strings.TrimSpace(value))
+		if isStandardMethod(method) {
+			return method
+		}
+	}
+	return ""
+}
+
+// isStandardMethod returns whether the given method is one of the methods
+// defined in the `net/http` package.
+func isStandardMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodConnect,
+		http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,801 @@
+package otelchitrace
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/internal/attrfilter"
+	"github.com/riandyrn/otelchi/internal/drain"
+	"github.com/riandyrn/otelchi/internal/semconvutil"
+	"github.com/riandyrn/otelchi/otelchicore"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/log"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+
+	"go.opentelemetry.io/otel/semconv/v1.20.0/httpconv"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = modulePath
+
+	// the W3C trace context headers, in canonical form so they could be set
+	// directly into http.Header
+	traceparentHeader = "Traceparent"
+	tracestateHeader  = "Tracestate"
+)
+
+// Middleware sets up a handler to start tracing the incoming
+// requests. The serverName parameter should describe the name of the
+// (virtual) server handling the request.
+//
+// The default settings could be tuned by the operators through the following
+// environment variables, the given options are applied afterward so they
+// override (or complement for the accumulated options) these settings:
+//
+//   - `OTELCHI_FILTER_PATHS`: comma-separated regular expressions of the
+//     request paths excluded from tracing, e.g. `^/healthz$,^/internal/`.
+//   - `OTELCHI_TRACE_RESPONSE_HEADERS`: `true` writes the trace information
+//     into the default response headers, see `WithTraceResponseHeaders`.
+//   - `OTELCHI_CAPTURE_HEADERS`: comma-separated request headers recorded
+//     as span attributes, see `WithCapturedRequestHeaders`.
+//   - `OTELCHI_CAPTURE_RESPONSE_HEADERS`: comma-separated response headers
+//     recorded as span attributes, see `WithCapturedResponseHeaders`.
+//   - `OTELCHI_REQUEST_METHOD_IN_SPAN_NAME`: `true` adds the request method
+//     into the span name, see `WithRequestMethodInSpanName`.
+//   - `OTELCHI_QUERY_RECORDING`: the query string recording mode, either
+//     `off`, `redacted` or `full`, see `WithQueryRecording`.
+//
+// The invalid environment variables are ignored & reported to the global
+// OpenTelemetry error handler.
+func Middleware(serverName string, opts ...Option) func(next http.Handler) http.Handler {
+	cfg := config{
+		semconvMode: semconvutil.ModeFromEnv(),
+	}
+	envOpts, err := envOptions()
+	if err != nil {
+		otel.Handle(err)
+	}
+	for _, opt := range envOpts {
+		opt.apply(&cfg)
+	}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	if len(cfg.schemaURL) == 0 {
+		cfg.schemaURL = semconvSchemaURL(cfg.semconvMode)
+	}
+	tracerOpts := []oteltrace.TracerOption{
+		oteltrace.WithInstrumentationVersion(Version()),
+		oteltrace.WithSchemaURL(cfg.schemaURL),
+		oteltrace.WithInstrumentationAttributes(cfg.scopeAttributes...),
+	}
+	name := cfg.instrumentationName()
+	var tracer oteltrace.Tracer
+	switch {
+	case cfg.tracer != nil:
+		tracer = cfg.tracer
+	case cfg.tracerProvider == nil && cfg.dynamicTracerProvider:
+		tracer = globalTracer{name: name, opts: tracerOpts}
+	default:
+		if cfg.tracerProvider == nil {
+			cfg.tracerProvider = otel.GetTracerProvider()
+		}
+		tracer = cfg.tracerProvider.Tracer(name, tracerOpts...)
+	}
+	if cfg.secondaryTracerProvider != nil {
+		tracer = teeTracer{
+			primary:   tracer,
+			secondary: cfg.secondaryTracerProvider.Tracer(name, tracerOpts...),
+		}
+	}
+	if cfg.attributeFilter != nil || cfg.attributeValueLengthLimit > 0 {
+		tracer = filteringTracer{Tracer: tracer, keep: cfg.attributeFilter, limit: cfg.attributeValueLengthLimit}
+	}
+	if cfg.propagators == nil {
+		cfg.propagators = otel.GetTextMapPropagator()
+	}
+	if ordered, ok := cfg.propagators.(orderedPropagators); ok {
+		ordered.mode = cfg.propagationMode
+		cfg.propagators = ordered
+	}
+
+	overheadHistogram := newOverheadHistogram(cfg)
+	queueDurationHistogram := newQueueDurationHistogram(cfg)
+	debug := newDebugStats(cfg, serverName)
+
+	var requestLogger log.Logger
+	if cfg.loggerProvider != nil {
+		requestLogger = cfg.loggerProvider.Logger(
+			name,
+			log.WithInstrumentationVersion(Version()),
+			log.WithSchemaURL(cfg.schemaURL),
+			log.WithInstrumentationAttributes(cfg.scopeAttributes...),
+		)
+	}
+
+	childSpans := newChildSpans(cfg, tracer)
+	spanNames := newSpanNameCache(cfg)
+	idempotencyKeys := newIdempotencyKeys(cfg)
+	routes := newRouteResolver(cfg)
+
+	return func(handler http.Handler) http.Handler {
+		return traceware{
+			config:                 cfg,
+			serverName:             serverName,
+			tracer:                 tracer,
+			handler:                handler,
+			overheadHistogram:      overheadHistogram,
+			overheadAttributes:     otelmetric.WithAttributes(attrfilter.Apply(cfg.attributeFilter, cfg.staticAttributes)...),
+			queueDurationHistogram: queueDurationHistogram,
+			childSpans:             childSpans,
+			requestLogger:          requestLogger,
+			serverHost:             newServerHostAttributes(cfg, serverName),
+			spanNames:              spanNames,
+			idempotencyKeys:        idempotencyKeys,
+			debug:                  debug,
+			routes:                 routes,
+		}
+	}
+}
+
+type traceware struct {
+	config
+	serverName             string
+	tracer                 oteltrace.Tracer
+	handler                http.Handler
+	overheadHistogram      otelmetric.Float64Histogram
+	overheadAttributes     otelmetric.MeasurementOption
+	queueDurationHistogram otelmetric.Float64Histogram
+	childSpans             *childSpans
+	requestLogger          log.Logger
+	serverHost             *serverHostAttributes
+	spanNames              *spanNameCache
+	idempotencyKeys        *idempotencyKeys
+	debug                  *debugStats
+	routes                 *otelchicore.RouteResolver
+}
+
+// ServeHTTP implements the http.Handler interface. It does the actual
+// tracing of the request.
+func (tw traceware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// go through all filters if any
+	for _, filter := range tw.filters {
+		// if there is a filter that returns false, we skip tracing
+		// and execute next handler
+		if !filter(r) {
+			tw.debug.filtered(debugFilterFilter)
+			tw.handler.ServeHTTP(w, r)
+			return
+		}
+	}
+
+	// measure the time spent by the middleware itself
+	overhead := overheadTimer{histogram: tw.overheadHistogram, attrs: tw.overheadAttributes}
+	overhead.begin()
+	defer overhead.end(r.Context())
+
+	// if we have access to chi routes, we could resolve the route pattern
+	// beforehand, this allows the route config to be applied before the span
+	// is started
+	routePattern := tw.routes.Match(r)
+	if tw.chiRoutes != nil {
+		for _, filter := range tw.routeFilters {
+			// if there is a route filter that returns false, we skip tracing
+			// and execute next handler
+			if !filter(routePattern, r) {
+				tw.debug.filtered(debugFilterRouteFilter)
+				tw.handler.ServeHTTP(w, r)
+				return
+			}
+		}
+	}
+	routeCfg := lookupRouteConfig(tw.routeConfigs, routePattern)
+	if routeCfg != nil && routeCfg.tracingDisabled {
+		tw.debug.filtered(debugFilterRouteConfig)
+		tw.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// extract tracing header using propagator
+	var carrier propagation.TextMapCarrier = propagation.HeaderCarrier(r.Header)
+	if tw.carrierFn != nil {
+		carrier = tw.carrierFn(r)
+	}
+	ctx := tw.propagators.Extract(r.Context(), carrier)
+
+	// expose the incoming trace context without starting the span
+	if tw.tracePropagationOnly {
+		tw.writeTraceResponseHeaders(ctx, r, w.Header())
+		tw.handler.ServeHTTP(w, r.WithContext(ctx))
+		return
+	}
+
+	// skip the health-check request unless its tracing is forced by the
+	// sampled trace context
+	healthCheck := tw.healthPaths.Match(r)
+	if healthCheck && !oteltrace.SpanContextFromContext(ctx).IsSampled() {
+		tw.debug.filtered(debugFilterHealthCheck)
+		tw.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// register the request in flight for the draining snapshot
+	inFlight := drain.Begin()
+	defer drain.End()
+
+	// create span, based on specification, we need to set already known attributes
+	// when creating the span, the only thing missing here is HTTP route pattern since
+	// in go-chi/chi route pattern could only be extracted once the request is executed
+	// check here for details:
+	//
+	// https://github.com/go-chi/chi/issues/150#issuecomment-278850733
+	//
+	// if we have access to chi routes, we could extract the route pattern beforehand,
+	// otherwise the span is named by the raw path until the route is resolved.
+	spanName := ""
+	routeState := &routeState{routes: tw.routes}
+	attrsBuf := getAttributesBuffer()
+	spanAttributes := tw.appendRequestAttributes(attrsBuf.attrs, r)
+	defer func() {
+		// the span attributes are copied by the tracer, so the buffer could
+		// be reused once the request is completed
+		attrsBuf.put(spanAttributes)
+	}()
+
+	// the method used in the span name, it is the effective method for the
+	// tunneled requests
+	spanMethod := r.Method
+	if method := tw.overriddenMethod(r); len(method) > 0 {
+		spanMethod = method
+		spanAttributes = append(spanAttributes, MethodOverrideKey.String(method))
+	}
+	spanAttributes = append(spanAttributes, tw.staticAttributes...)
+	if tw.spanAttributesFn != nil {
+		spanAttributes = append(spanAttributes, tw.spanAttributesFn(r)...)
+	}
+
+	if len(routePattern) > 0 {
+		route := tw.limitedRoute(routePattern)
+		routeState.set(route)
+		spanName = tw.spanName(spanMethod, route)
+		spanAttributes = append(spanAttributes, tw.httpRouteAttributes(route, routePattern)...)
+		spanAttributes = append(spanAttributes, tw.routeAttributes.Lookup(routePattern)...)
+	}
+	if routeCfg != nil {
+		if len(routeCfg.spanName) > 0 {
+			spanName = routeCfg.spanName
+		}
+		spanAttributes = append(spanAttributes, routeCfg.attributes...)
+	}
+	if len(spanName) == 0 {
+		spanName = addPrefixToSpanName(tw.requestMethodInSpanName, spanMethod, r.URL.Path)
+	}
+
+	// distinguish the operations served by the GraphQL endpoint
+	graphQLOp, isGraphQL := tw.graphQLOperation(r)
+	if isGraphQL {
+		if routeCfg == nil || len(routeCfg.spanName) == 0 {
+			spanName = graphQLOp.spanName(spanName)
+		}
+		spanAttributes = append(spanAttributes, graphQLOp.attributes()...)
+	}
+
+	// record the query string
+	spanAttributes = append(spanAttributes, tw.queryAttributes(r)...)
+
+	// record the captured request headers
+	if len(tw.capturedRequestHeaders) > 0 {
+		spanAttributes = append(spanAttributes, headerAttributes("http.request.header.", r.Header, tw.capturedRequestHeaders)...)
+	}
+
+	// record identity of the verified client certificate
+	if tw.tlsClientIdentity {
+		spanAttributes = append(spanAttributes, tlsClientAttributes(r, tw.tlsClientRedactFn)...)
+	}
+
+	// record the address of the client that sends the request & the network
+	// attributes, the stable semantic conventions already include them in the
+	// request attributes
+	if !tw.semconvMode.EmitsNew() {
+		spanAttributes = append(spanAttributes, tw.clientAttributes(r)...)
+	}
+
+	// mark request served while the server is draining
+	if IsDraining() {
+		spanAttributes = append(spanAttributes,
+			ServerDrainingKey.Bool(true),
+			ServerDrainingInFlightKey.Int64(inFlight),
+		)
+	}
+
+	// mark health-check request whose tracing is forced
+	if healthCheck {
+		spanAttributes = append(spanAttributes, HealthCheckKey.Bool(true))
+	}
+
+	// mark shadow (mirrored) request
+	if tw.shadowRequestFn != nil && tw.shadowRequestFn(r) {
+		spanAttributes = append(spanAttributes, ShadowRequestKey.Bool(true))
+	}
+
+	// determine span kind, request forwarded internally (e.g. by service
+	// mesh sidecar) should not produce another server span
+	spanKind := oteltrace.SpanKindServer
+	if tw.internalRequestFn != nil && tw.internalRequestFn(r) {
+		spanKind = oteltrace.SpanKindInternal
+	}
+	if tw.spanKindFn != nil {
+		if kind := tw.spanKindFn(r); kind != oteltrace.SpanKindUnspecified {
+			spanKind = kind
+		}
+	}
+
+	// define span start options
+	spanOpts := []oteltrace.SpanStartOption{
+		oteltrace.WithAttributes(spanAttributes...),
+		oteltrace.WithSpanKind(spanKind),
+	}
+
+	if tw.publicEndpointFn != nil && tw.publicEndpointFn(r) {
+		// mark span as the root span
+		spanOpts = append(spanOpts, oteltrace.WithNewRoot())
+
+		// linking incoming span context to the root span, we need to
+		// ensure if the incoming span context is valid (because it is
+		// possible for us to receive invalid span context due to various
+		// reason such as bug or context propagation error) and it is
+		// coming from another service (remote) before linking it to the
+		// root span
+		spanCtx := oteltrace.SpanContextFromContext(ctx)
+		if spanCtx.IsValid() && spanCtx.IsRemote() {
+			spanOpts = append(
+				spanOpts,
+				oteltrace.WithLinks(oteltrace.Link{
+					SpanContext: spanCtx,
+				}),
+			)
+		}
+	}
+
+	// link the trace contexts carried by the batch payload
+	if len(tw.batchLinkHeaders) > 0 || len(tw.batchLinkJSONField) > 0 {
+		if links := tw.batchLinks(r); len(links) > 0 {
+			spanOpts = append(spanOpts, oteltrace.WithLinks(links...))
+		}
+	}
+
+	// start span
+	startTime := tw.now()
+	spanOpts = append(spanOpts, tw.clockStartOptions()...)
+
+	// record the idempotency key & link the retry to the previous request
+	idempotencyKey, hasIdempotencyKey := tw.idempotencyKey(r)
+	if hasIdempotencyKey {
+		spanOpts = append(spanOpts, oteltrace.WithAttributes(IdempotencyKeyKey.String(idempotencyKey)))
+		if tw.idempotencyKeys != nil {
+			if previous, ok := tw.idempotencyKeys.previous(idempotencyKey, startTime); ok {
+				spanOpts = append(spanOpts,
+					oteltrace.WithLinks(oteltrace.Link{SpanContext: previous}),
+					oteltrace.WithAttributes(IdempotencyRetryKey.Bool(true)),
+				)
+			}
+		}
+	}
+
+	// record the time spent in the queue before reaching the server
+	queueDuration, queued := tw.queueDuration(r, startTime)
+	if queued {
+		spanOpts = append(spanOpts, oteltrace.WithAttributes(QueueDurationKey.Float64(durationMillis(queueDuration))))
+	}
+	if tw.spanStartOptionsFn != nil {
+		spanOpts = append(spanOpts, tw.spanStartOptionsFn(r)...)
+	}
+	ctx, span := tw.tracer.Start(ctx, spanName, spanOpts...)
+	if hasIdempotencyKey && tw.idempotencyKeys != nil {
+		tw.idempotencyKeys.observe(idempotencyKey, span.SpanContext(), startTime)
+	}
+	var ws *websocketTracker
+	defer func() {
+		// the span of WebSocket upgrade request may outlive the handler
+		if ws != nil {
+			ws.handlerDone()
+			return
+		}
+		span.End(tw.clockEndOptions()...)
+	}()
+
+	tw.writeTraceResponseHeaders(ctx, r, w.Header())
+
+	// get recording response writer
+	rrw := otelchicore.AcquireResponseWriter(w)
+	defer rrw.Release()
+
+	// measure the time distribution of the request
+	var phases *phaseTimer
+	if tw.phaseTimings {
+		phases = &phaseTimer{now: tw.now, start: startTime}
+		rrw.WriteClock = tw.now
+	}
+
+	// record the lifecycle phases of the request
+	if tw.lifecycleEvents {
+		tw.recordLifecycleEvent(span, HeadersReadEventName)
+		rrw.Hooks.OnFirstByte = func() { tw.recordLifecycleEvent(span, FirstByteWrittenEventName) }
+	}
+
+	// write the Server-Timing header right before the response header
+	if tw.serverTimingHeader {
+		header := w.Header()
+		spanCtx := span.SpanContext()
+		rrw.Hooks.OnHeader = func() { tw.writeServerTiming(header, spanCtx, startTime) }
+	}
+
+	// emit the access log once the request is completed
+	if tw.requestLogger != nil {
+		defer func() {
+			tw.emitRequestLog(ctx, r, routeState.get(), rrw.Status(), startTime)
+		}()
+	}
+
+	// capture the request & response bodies
+	var bc *bodyCapture
+	if tw.bodyCaptureMaxBytes > 0 {
+		bc = &bodyCapture{
+			maxBytes:     tw.bodyCaptureMaxBytes,
+			contentTypes: tw.bodyCaptureContentTypes,
+			redactFn:     tw.bodyRedactFn,
+		}
+		header := w.Header()
+		rrw.Hooks.OnWrite = func(b []byte) { bc.onWrite(header, b) }
+	}
+
+	// capture the problem details response
+	var pc *problemCapture
+	if tw.problemDetailsMaxBytes > 0 {
+		pc = &problemCapture{maxBytes: tw.problemDetailsMaxBytes}
+		header := w.Header()
+		onWrite := rrw.Hooks.OnWrite
+		rrw.Hooks.OnWrite = func(b []byte) {
+			if onWrite != nil {
+				onWrite(b)
+			}
+			pc.onWrite(header, b)
+		}
+	}
+
+	// capture the response body reporting the status
+	var rsc *responseStatusCapture
+	if tw.bodyStatusFn != nil && tw.bodyStatusMaxBytes > 0 {
+		rsc = &responseStatusCapture{
+			maxBytes:     tw.bodyStatusMaxBytes,
+			contentTypes: tw.bodyStatusContentTypes,
+			fn:           tw.bodyStatusFn,
+		}
+		header := w.Header()
+		onWrite := rrw.Hooks.OnWrite
+		rrw.Hooks.OnWrite = func(b []byte) {
+			if onWrite != nil {
+				onWrite(b)
+			}
+			rsc.onWrite(header, b)
+		}
+	}
+
+	// record the flushes of Server-Sent Events stream
+	var sse *sseRecorder
+	if tw.sseInstrumentation {
+		sse = &sseRecorder{tw: tw, span: span, rrw: rrw}
+		rrw.Hooks.OnFlush = sse.onFlush
+	}
+
+	// correlate the request by its request ID
+	if len(tw.requestIDHeader) > 0 {
+		ctx = tw.withRequestID(ctx, r, w, span)
+	}
+
+	// expose response metadata to the downstream handlers
+	info := newResponseInfo()
+	rrw.Hooks.Observer = info
+	ctx = context.WithValue(ctx, responseInfoCtxKey{}, info)
+	ctx = context.WithValue(ctx, routeStateCtxKey{}, routeState)
+	ctx = context.WithValue(ctx, serverSpanCtxKey{}, span)
+
+	// expose the logger annotated with the trace & the route information
+	if tw.slogLogger != nil {
+		ctx = context.WithValue(ctx, slogLoggerCtxKey{}, newRequestSlogLogger(ctx, tw.slogLogger))
+	}
+
+	// allow the compression middleware wrapped by `WrapCompress` to report
+	// the uncompressed response size
+	var compression *compressionState
+	if tw.compressionAttributes {
+		compression = &compressionState{}
+		ctx = context.WithValue(ctx, compressionStateCtxKey{}, compression)
+	}
+
+	// allow the route handlers wrapped by `WrapHandler` to create child spans
+	if tw.handlerSpan {
+		ctx = context.WithValue(ctx, handlerSpanCtxKey{}, tw.childSpans)
+	}
+
+	// allow the middlewares wrapped by `WrapMiddleware` to create child spans
+	if tw.middlewareSpans {
+		ctx = context.WithValue(ctx, middlewareSpansCtxKey{}, tw.childSpans)
+	}
+
+	// set span name & http route attribute if route pattern cannot be determined
+	// during span creation, the route pattern is available once chi starts
+	// executing the handler, it may be called from another goroutine when the
+	// span of WebSocket upgrade request is ended on connection close
+	var routeOnce sync.Once
+	resolveRoute := func() {
+		routeOnce.Do(func() {
+			if len(routePattern) > 0 {
+				return
+			}
+
+			routePattern = tw.resolvedRoute(chi.RouteContext(r.Context()).RoutePattern())
+			route := tw.limitedRoute(routePattern)
+			routeState.set(route)
+			span.SetAttributes(tw.httpRouteAttributes(route, routePattern)...)
+			span.SetAttributes(tw.routeAttributes.Lookup(routePattern)...)
+
+			// apply the route config now that the route pattern is known
+			routeCfg = lookupRouteConfig(tw.routeConfigs, routePattern)
+			if routeCfg != nil {
+				span.SetAttributes(routeCfg.attributes...)
+			}
+
+			// keep the name given by the handler, the span is only renamed
+			// when it is still named by the raw path
+			if name, ok := currentSpanName(span); ok && name != spanName {
+				spanName = name
+				return
+			}
+			spanName = tw.spanName(spanMethod, route)
+			if routeCfg != nil && len(routeCfg.spanName) > 0 {
+				spanName = routeCfg.spanName
+			} else if isGraphQL {
+				spanName = graphQLOp.spanName(spanName)
+			}
+			span.SetName(spanName)
+		})
+	}
+
+	// track the connection hijacked for WebSocket upgrade
+	if isWebSocketRequest(r) {
+		ws = &websocketTracker{
+			span:      span,
+			mode:      tw.websocketSpanMode,
+			beforeEnd: resolveRoute,
+			endOpts:   tw.clockEndOptions,
+		}
+		rrw.Hooks.OnHijack = ws.onHijack
+	}
+
+	// execute next http handler
+	r = r.WithContext(ctx)
+	if bc != nil {
+		bc.captureRequest(r)
+	}
+	overhead.beginHandler()
+	if phases != nil {
+		phases.beginHandler()
+	}
+	tw.handler.ServeHTTP(rrw.Writer(), r)
+	if phases != nil {
+		phases.endHandler()
+	}
+	overhead.endHandler()
+	if tw.handlerSpan {
+		tw.childSpans.checkWrappedHandlers()
+	}
+
+	// the response header is written by net/http after the handler returns
+	// when the handler doesn't write the response
+	rrw.Complete()
+
+	if tw.lifecycleEvents {
+		tw.recordLifecycleEvent(span, ResponseCompleteEventName)
+	}
+
+	if sse != nil {
+		sse.end()
+	}
+	if bc != nil {
+		bc.record(span, tw.clockEventOptions()...)
+	}
+
+	resolveRoute()
+	tw.debug.spanEmitted(routeState.get())
+
+	if queued {
+		tw.recordQueueDuration(r.Context(), queueDuration, routeState.get())
+	}
+
+	// record the allowed URL parameters now that the request is routed
+	span.SetAttributes(tw.urlParamAttributes(r)...)
+
+	// record the compression attributes
+	if compression != nil {
+		span.SetAttributes(compressionAttributes(r, w.Header(), rrw.BytesWritten(), compression)...)
+	}
+
+	// record the captured response headers
+	if len(tw.capturedResponseHeaders) > 0 {
+		span.SetAttributes(headerAttributes("http.response.header.", w.Header(), tw.capturedResponseHeaders)...)
+	}
+
+	// record the captured response trailers
+	if len(tw.capturedResponseTrailers) > 0 {
+		span.SetAttributes(trailerAttributes(w.Header(), tw.capturedResponseTrailers)...)
+	}
+
+	// let the function enrich the span with the outcome of the handlers
+	if tw.postRouteEnrichment != nil {
+		tw.postRouteEnrichment(r, span)
+	}
+
+	// check if the request is a WebSocket upgrade request
+	if isWebSocketRequest(r) {
+		span.SetStatus(codes.Unset, "WebSocket upgrade request")
+		return
+	}
+
+	// set status code attribute
+	span.SetAttributes(tw.statusCodeAttributes(rrw.Status())...)
+
+	// record the time distribution of the request
+	if phases != nil {
+		span.SetAttributes(phases.attributes(rrw.WriteDuration())...)
+	}
+
+	// record the location of the redirect response
+	recordRedirect(span, rrw.Status(), w.Header(), tw.clockEventOptions()...)
+
+	// set span status
+	spanStatusFn := httpconv.ServerStatus
+	if tw.spanStatusFn != nil {
+		spanStatusFn = tw.spanStatusFn
+	}
+	code, description := spanStatusFn(rrw.Status())
+
+	// describe the error with the problem details
+	if pc != nil {
+		if problemDescription := pc.record(span, rrw.Status()); code == codes.Error && len(problemDescription) > 0 {
+			description = problemDescription
+		}
+	}
+
+	// the failure reported by the response body of JSON-RPC style APIs
+	if rsc != nil {
+		if bodyCode, bodyDescription, ok := rsc.status(rrw.Status()); ok {
+			code, description = bodyCode, bodyDescription
+		}
+	}
+
+	// the failure of the gRPC call is only reported by the trailers
+	if grpcCode, grpcDescription, failed := tw.grpcTrailerStatus(w.Header()); failed {
+		code, description = grpcCode, grpcDescription
+	}
+
+	// distinguish the request aborted by client disconnect or timeout from
+	// the actual server failure
+	if abortedCode, abortedDescription, aborted := recordAborted(r.Context(), span, tw.clockEventOptions()...); aborted {
+		code, description = abortedCode, abortedDescription
+	}
+	span.SetStatus(code, description)
+
+	// re-emit the slow request missed by the head sampling
+	if tw.latencySamplingThreshold > 0 {
+		attrs := append(spanAttributes, routeAttribute(routeState.get()))
+		attrs = append(attrs, tw.statusCodeAttributes(rrw.Status())...)
+		tw.forceSampleSlowRequest(span, spanName, spanKind, startTime, attrs, code, description)
+	}
+
+	// let the error hook enrich the span of the error response
+	if tw.errorHook != nil && rrw.Status() >= http.StatusBadRequest {
+		tw.errorHook(span, r, rrw.Status())
+	}
+}
+
+// writeTraceResponseHeaders writes the trace headers of the span in the given
+// context into the response header of the given request.
+func (tw traceware) writeTraceResponseHeaders(ctx context.Context, r *http.Request, header http.Header) {
+	// skip the request not allowed to receive the trace headers
+	if tw.traceResponseHeaderFn != nil && !tw.traceResponseHeaderFn(r) {
+		return
+	}
+
+	spanCtx := oteltrace.SpanContextFromContext(ctx)
+
+	// put trace_id to response header only when `WithTraceIDResponseHeader` is used
+	if len(tw.traceIDResponseHeaderKey) > 0 && spanCtx.HasTraceID() {
+		header.Add(tw.traceIDResponseHeaderKey, formatTraceID(spanCtx.TraceID()))
+		header.Add(tw.traceSampledResponseHeaderKey, strconv.FormatBool(spanCtx.IsSampled()))
+	}
+
+	// put W3C trace context to response header only when it is enabled in
+	// `TraceHeaderConfig`
+	if tw.traceparentResponseHeader && spanCtx.IsValid() {
+		writeTraceContextHeaders(spanCtx, header, tw.tracestateResponseHeader)
+	}
+}
+
+// writeTraceContextHeaders writes W3C `traceparent` header and optionally
+// `tracestate` header of the given span context into the response header.
+// The headers are the same as the ones injected by `propagation.TraceContext`
+// except they are formatted without going through an intermediate carrier.
+func writeTraceContextHeaders(spanCtx oteltrace.SpanContext, header http.Header, withTracestate bool) {
+	header[traceparentHeader] = []string{formatTraceparent(spanCtx)}
+	if !withTracestate {
+		return
+	}
+	if tracestate := spanCtx.TraceState().String(); len(tracestate) > 0 {
+		header[tracestateHeader] = []string{tracestate}
+	}
+}
+
+// spanName returns the span name of the given route, prefixed by the given
+// method when `WithRequestMethodInSpanName` is enabled.
+func (tw traceware) spanName(method, route string) string {
+	if tw.spanNames != nil {
+		if route == "" {
+			route = "/"
+		}
+		return tw.spanNames.get(method, route)
+	}
+	return addPrefixToSpanName(tw.requestMethodInSpanName, method, route)
+}
+
+// currentSpanName returns the current name of the given span, it returns
+// false when the span doesn't expose its name (e.g. non-recording span).
+func currentSpanName(span oteltrace.Span) (string, bool) {
+	for {
+		switch s := span.(type) {
+		case filteringSpan:
+			span = s.Span
+		case teeSpan:
+			span = s.Span
+		case interface{ Name() string }:
+			return s.Name(), true
+		default:
+			return "", false
+		}
+	}
+}
+
+func addPrefixToSpanName(shouldAdd bool, prefix, spanName string) string {
+	// in chi v5.0.8, the root route will be returned has an empty string
+	// (see https://github.com/go-chi/chi/blob/v5.0.8/context.go#L126)
+	if spanName == "" {
+		spanName = "/"
+	}
+
+	if shouldAdd && len(spanName) > 0 {
+		spanName = prefix + " " + spanName
+	}
+	return spanName
+}
+
+// isWebSocketRequest checks if an HTTP request is a WebSocket upgrade request
+// Fix: https://github.com/riandyrn/otelchi/issues/66
+func isWebSocketRequest(r *http.Request) bool {
+	// Check if the Connection header contains "Upgrade"
+	connectionHeader := r.Header.Get("Connection")
+	if !strings.Contains(strings.ToLower(connectionHeader), "upgrade") {
+		return false
+	}
+
+	// Check if the Upgrade header is "websocket"
+	upgradeHeader := r.Header.Get("Upgrade")
+	return strings.ToLower(upgradeHeader) == "websocket"
+}
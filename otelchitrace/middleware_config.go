@@ -0,0 +1,266 @@
+package otelchitrace
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Config is the struct-based counterpart of the functional options, it holds
+// the settings which could be loaded from configuration files (e.g. YAML or
+// JSON) or environment variables, so the instrumentation config could be
+// centralized. The zero value means every setting is left at its default.
+//
+// The settings involving runtime objects (e.g. tracer provider, propagators,
+// functions) are not covered, use the functional options passed into
+// `MiddlewareWithConfig` for them.
+type Config struct {
+	// ServerName describes the name of the (virtual) server handling the
+	// request.
+	ServerName string `json:"server_name" yaml:"server_name"`
+
+	// SemconvVersion is the version of the HTTP semantic conventions, either
+	// `old`, `new` or `dual` (see `WithSemconvVersion`). If it is empty, the
+	// version is determined by the `OTEL_SEMCONV_STABILITY_OPT_IN`
+	// environment variable.
+	SemconvVersion string `json:"semconv_version" yaml:"semconv_version"`
+
+	// RequestMethodInSpanName adds the request method into the span name,
+	// see `WithRequestMethodInSpanName`.
+	RequestMethodInSpanName bool `json:"request_method_in_span_name" yaml:"request_method_in_span_name"`
+
+	// FilterPathRegexps holds the regular expressions of the request paths
+	// excluded from tracing, the request matching any of them is not traced.
+	FilterPathRegexps []string `json:"filter_path_regexps" yaml:"filter_path_regexps"`
+
+	// FilterHealthEndpoints excludes the health-check requests from tracing,
+	// see `WithHealthEndpointsFiltered`. The paths are taken from
+	// HealthEndpoints, the default paths are used when it is empty.
+	FilterHealthEndpoints bool     `json:"filter_health_endpoints" yaml:"filter_health_endpoints"`
+	HealthEndpoints       []string `json:"health_endpoints" yaml:"health_endpoints"`
+
+	// CapturedRequestHeaders & CapturedResponseHeaders hold the headers
+	// recorded as span attributes, see `WithCapturedRequestHeaders` &
+	// `WithCapturedResponseHeaders`.
+	CapturedRequestHeaders  []string `json:"captured_request_headers" yaml:"captured_request_headers"`
+	CapturedResponseHeaders []string `json:"captured_response_headers" yaml:"captured_response_headers"`
+
+	// CapturedResponseTrailers holds the trailers recorded as span
+	// attributes, see `WithCapturedResponseTrailers`.
+	CapturedResponseTrailers []string `json:"captured_response_trailers" yaml:"captured_response_trailers"`
+
+	// TraceResponseHeaders writes the trace information into the response
+	// headers when it is non-nil, see `WithTraceResponseHeaders`.
+	TraceResponseHeaders *TraceHeaderConfig `json:"trace_response_headers" yaml:"trace_response_headers"`
+
+	// QueryRecording is the recording mode of the query string, either `off`,
+	// `redacted` or `full` (see `WithQueryRecording`). The redaction is
+	// customized through QueryRedactionDenylist & QueryRedactionAllowlist.
+	QueryRecording          string   `json:"query_recording" yaml:"query_recording"`
+	QueryRedactionDenylist  []string `json:"query_redaction_denylist" yaml:"query_redaction_denylist"`
+	QueryRedactionAllowlist []string `json:"query_redaction_allowlist" yaml:"query_redaction_allowlist"`
+
+	// MaxRouteCardinality limits the number of distinct routes when it is
+	// positive, see `WithMaxRouteCardinality`.
+	MaxRouteCardinality      int    `json:"max_route_cardinality" yaml:"max_route_cardinality"`
+	RouteCardinalityFallback string `json:"route_cardinality_fallback" yaml:"route_cardinality_fallback"`
+
+	// LatencyForcedSamplingThreshold re-emits the unsampled requests slower
+	// than the threshold when it is positive, see
+	// `WithLatencyForcedSampling`.
+	LatencyForcedSamplingThreshold time.Duration `json:"latency_forced_sampling_threshold" yaml:"latency_forced_sampling_threshold"`
+
+	// BodyCaptureMaxBytes records the request & response bodies when it is
+	// positive, see `WithBodyCapture`.
+	BodyCaptureMaxBytes     int      `json:"body_capture_max_bytes" yaml:"body_capture_max_bytes"`
+	BodyCaptureContentTypes []string `json:"body_capture_content_types" yaml:"body_capture_content_types"`
+
+	// ProblemDetailsMaxBytes records the problem details responses when it
+	// is positive, see `WithProblemDetailsCapture`.
+	ProblemDetailsMaxBytes int `json:"problem_details_max_bytes" yaml:"problem_details_max_bytes"`
+
+	// RequestID enables the request ID correlation, see `WithRequestID`.
+	RequestID         bool   `json:"request_id" yaml:"request_id"`
+	RequestIDHeader   string `json:"request_id_header" yaml:"request_id_header"`
+	GenerateRequestID bool   `json:"generate_request_id" yaml:"generate_request_id"`
+
+	// MethodOverride enables honoring the method override headers, see
+	// `WithMethodOverride`. The default header is used when
+	// MethodOverrideHeaders is empty.
+	MethodOverride        bool     `json:"method_override" yaml:"method_override"`
+	MethodOverrideHeaders []string `json:"method_override_headers" yaml:"method_override_headers"`
+
+	// MountPrefix is the prefix added to the route, see `WithMountPrefix`.
+	MountPrefix string `json:"mount_prefix" yaml:"mount_prefix"`
+
+	// The switches of the options which have no parameter.
+	PublicEndpoint        bool `json:"public_endpoint" yaml:"public_endpoint"`
+	NetworkAttributes     bool `json:"network_attributes" yaml:"network_attributes"`
+	CompressionAttributes bool `json:"compression_attributes" yaml:"compression_attributes"`
+	WrappedHandlerSpan    bool `json:"wrapped_handler_span" yaml:"wrapped_handler_span"`
+	MiddlewareSpans       bool `json:"middleware_spans" yaml:"middleware_spans"`
+	SSEInstrumentation    bool `json:"sse_instrumentation" yaml:"sse_instrumentation"`
+	LifecycleEvents       bool `json:"lifecycle_events" yaml:"lifecycle_events"`
+	ServerTimingHeader    bool `json:"server_timing_header" yaml:"server_timing_header"`
+	TracePropagationOnly  bool `json:"trace_propagation_only" yaml:"trace_propagation_only"`
+	LowAllocationMode     bool `json:"low_allocation_mode" yaml:"low_allocation_mode"`
+	DebugStats            bool `json:"debug_stats" yaml:"debug_stats"`
+}
+
+// Options returns the functional options equivalent to the config, it
+// returns error when the config holds invalid value.
+func (c Config) Options() ([]Option, error) {
+	var opts []Option
+
+	if len(c.SemconvVersion) > 0 {
+		mode, err := parseSemconvMode(c.SemconvVersion)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithSemconvVersion(mode))
+	}
+	if c.RequestMethodInSpanName {
+		opts = append(opts, WithRequestMethodInSpanName(true))
+	}
+	if len(c.FilterPathRegexps) > 0 {
+		filter, err := pathRegexpsFilter(c.FilterPathRegexps)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithFilter(filter))
+	}
+	if c.FilterHealthEndpoints {
+		opts = append(opts, WithHealthEndpointsFiltered(c.HealthEndpoints...))
+	}
+	if len(c.CapturedRequestHeaders) > 0 {
+		opts = append(opts, WithCapturedRequestHeaders(c.CapturedRequestHeaders...))
+	}
+	if len(c.CapturedResponseHeaders) > 0 {
+		opts = append(opts, WithCapturedResponseHeaders(c.CapturedResponseHeaders...))
+	}
+	if len(c.CapturedResponseTrailers) > 0 {
+		opts = append(opts, WithCapturedResponseTrailers(c.CapturedResponseTrailers...))
+	}
+	if c.TraceResponseHeaders != nil {
+		opts = append(opts, WithTraceResponseHeaders(*c.TraceResponseHeaders))
+	}
+	if len(c.QueryRecording) > 0 {
+		mode, err := parseQueryRecordingMode(c.QueryRecording)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithQueryRecording(mode))
+	}
+	if len(c.QueryRedactionDenylist) > 0 {
+		opts = append(opts, WithQueryRedactionDenylist(c.QueryRedactionDenylist...))
+	}
+	if len(c.QueryRedactionAllowlist) > 0 {
+		opts = append(opts, WithQueryRedactionAllowlist(c.QueryRedactionAllowlist...))
+	}
+	if c.MaxRouteCardinality > 0 {
+		opts = append(opts, WithMaxRouteCardinality(c.MaxRouteCardinality, c.RouteCardinalityFallback))
+	}
+	if c.LatencyForcedSamplingThreshold > 0 {
+		opts = append(opts, WithLatencyForcedSampling(c.LatencyForcedSamplingThreshold))
+	}
+	if c.BodyCaptureMaxBytes > 0 {
+		opts = append(opts, WithBodyCapture(c.BodyCaptureMaxBytes, c.BodyCaptureContentTypes...))
+	}
+	if c.ProblemDetailsMaxBytes > 0 {
+		opts = append(opts, WithProblemDetailsCapture(c.ProblemDetailsMaxBytes))
+	}
+	if c.RequestID {
+		opts = append(opts, WithRequestID(c.RequestIDHeader, c.GenerateRequestID))
+	}
+	if c.MethodOverride {
+		opts = append(opts, WithMethodOverride(c.MethodOverrideHeaders...))
+	}
+	if len(c.MountPrefix) > 0 {
+		opts = append(opts, WithMountPrefix(c.MountPrefix))
+	}
+
+	switches := []struct {
+		enabled bool
+		opt     Option
+	}{
+		{c.PublicEndpoint, WithPublicEndpoint()},
+		{c.NetworkAttributes, WithNetworkAttributes()},
+		{c.CompressionAttributes, WithCompressionAttributes()},
+		{c.WrappedHandlerSpan, WithWrappedHandlerSpan(true)},
+		{c.MiddlewareSpans, WithMiddlewareSpans()},
+		{c.SSEInstrumentation, WithSSEInstrumentation()},
+		{c.LifecycleEvents, WithLifecycleEvents()},
+		{c.ServerTimingHeader, WithServerTimingHeader()},
+		{c.TracePropagationOnly, WithTracePropagationOnly()},
+		{c.LowAllocationMode, WithLowAllocationMode()},
+		{c.DebugStats, WithDebugStats()},
+	}
+	for _, s := range switches {
+		if s.enabled {
+			opts = append(opts, s.opt)
+		}
+	}
+
+	return opts, nil
+}
+
+// MiddlewareWithConfig sets up a handler to start tracing the incoming
+// requests like `Middleware`, but it is configured by the given config. The
+// given options are applied after the config, so they could complement or
+// override it, e.g. for specifying the tracer provider.
+//
+// It returns error when the config holds invalid value.
+func MiddlewareWithConfig(cfg Config, opts ...Option) (func(next http.Handler) http.Handler, error) {
+	cfgOpts, err := cfg.Options()
+	if err != nil {
+		return nil, err
+	}
+	return Middleware(cfg.ServerName, append(cfgOpts, opts...)...), nil
+}
+
+// parseSemconvMode returns the semantic conventions mode by its name.
+func parseSemconvMode(name string) (SemconvMode, error) {
+	for _, mode := range []SemconvMode{SemconvOld, SemconvNew, SemconvDual} {
+		if strings.EqualFold(name, mode.String()) {
+			return mode, nil
+		}
+	}
+	return SemconvOld, fmt.Errorf("invalid semconv version %q, it must be either old, new or dual", name)
+}
+
+// parseQueryRecordingMode returns the query recording mode by its name.
+func parseQueryRecordingMode(name string) (QueryRecordingMode, error) {
+	switch strings.ToLower(name) {
+	case "off":
+		return QueryRecordingOff, nil
+	case "redacted":
+		return QueryRecordingRedacted, nil
+	case "full":
+		return QueryRecordingFull, nil
+	default:
+		return QueryRecordingOff, fmt.Errorf("invalid query recording mode %q, it must be either off, redacted or full", name)
+	}
+}
+
+// pathRegexpsFilter returns the filter excluding the requests which path
+// matches any of the given regular expressions.
+func pathRegexpsFilter(exprs []string) (Filter, error) {
+	regexps := make([]*regexp.Regexp, 0, len(exprs))
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter path regexp %q: %w", expr, err)
+		}
+		regexps = append(regexps, re)
+	}
+	return func(r *http.Request) bool {
+		for _, re := range regexps {
+			if re.MatchString(r.URL.Path) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
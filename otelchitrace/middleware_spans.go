@@ -0,0 +1,47 @@
+package otelchitrace
+
+import (
+	"net/http"
+)
+
+type middlewareSpansCtxKey struct{}
+
+// WithMiddlewareSpans enables creating a child span for every middleware
+// wrapped by `WrapMiddleware`, so the latency of the middleware chain shows
+// up in the traces instead of being lumped into the server span.
+func WithMiddlewareSpans() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.middlewareSpans = true
+	})
+}
+
+// WrapMiddleware wraps the given middleware so it is executed inside its own
+// internal span named after the given name, e.g:
+//
+//	router := chi.NewRouter()
+//	router.Use(otelchitrace.Middleware("my-server", otelchitrace.WithMiddlewareSpans()))
+//	router.Use(otelchitrace.WrapMiddleware("auth", authMiddleware))
+//	router.Use(otelchitrace.WrapMiddleware("compress", middleware.Compress(5)))
+//
+// The span covers the whole execution of the middleware including the next
+// handlers, so the spans of the subsequent middlewares & handler become its
+// children.
+//
+// The wrapped middleware is executed as it is when the request is not traced
+// by the middleware created with `WithMiddlewareSpans` option.
+func WrapMiddleware(name string, mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		handler := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			spans, ok := r.Context().Value(middlewareSpansCtxKey{}).(*childSpans)
+			if !ok {
+				handler.ServeHTTP(w, r)
+				return
+			}
+			ctx, span := spans.start(r.Context(), name)
+			defer spans.end(span)
+
+			handler.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
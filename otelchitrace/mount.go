@@ -0,0 +1,16 @@
+package otelchitrace
+
+import "strings"
+
+// WithMountPrefix specifies the prefix added to the `http.route` attribute &
+// the span name. It is useful when the router is mounted under a path which
+// is not visible to chi, e.g. through `http.StripPrefix`.
+//
+// The prefix of the chi sub-router mounted through `Mount` is detected
+// automatically, including when the routes are specified through
+// `WithChiRoutes`, so this option should not be used for it.
+func WithMountPrefix(prefix string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.mountPrefix = strings.TrimSuffix(prefix, "/")
+	})
+}
@@ -0,0 +1,57 @@
+package otelchitrace
+
+import (
+	"net/http"
+
+	"github.com/riandyrn/otelchi/internal/semconvutil"
+	"go.opentelemetry.io/otel/attribute"
+	semconvstable "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// WithNetworkAttributes enables recording the client & network attributes
+// required by the stable HTTP semantic conventions on the server span:
+// `client.address`, `user_agent.original`, `network.protocol.version`, &
+// `network.peer.address`.
+//
+// The client address is resolved based on the config given to `WithClientIP`,
+// use `ClientIPConfig.TrustedProxies` for honoring the forwarding headers only
+// when they are set by the trusted proxies.
+//
+// When the stable HTTP semantic conventions are used (see `WithSemconvVersion`),
+// these attributes are always recorded, so this option has no effect.
+func WithNetworkAttributes() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.networkAttributes = true
+	})
+}
+
+// clientAttributes returns the attributes describing the client that sends
+// the request which are not covered by the old semantic conventions.
+func (tw traceware) clientAttributes(r *http.Request) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+
+	clientIPCfg := tw.clientIP
+	if clientIPCfg == nil {
+		if !tw.networkAttributes {
+			return nil
+		}
+		clientIPCfg = &ClientIPConfig{}
+	}
+	if addr := resolveClientIP(clientIPCfg, r); len(addr) > 0 {
+		attrs = append(attrs, clientAddressKey.String(addr))
+	}
+	if !tw.networkAttributes {
+		return attrs
+	}
+
+	if userAgent := r.UserAgent(); len(userAgent) > 0 {
+		attrs = append(attrs, semconvstable.UserAgentOriginal(userAgent))
+	}
+	if _, version := semconvutil.NetworkProtocol(r.Proto); len(version) > 0 {
+		attrs = append(attrs, semconvstable.NetworkProtocolVersion(version))
+	}
+	if peer := hostOnly(r.RemoteAddr); len(peer) > 0 {
+		attrs = append(attrs, semconvstable.NetworkPeerAddress(peer))
+	}
+	return attrs
+}
@@ -0,0 +1,58 @@
+package otelchitrace
+
+import (
+	"net/http"
+)
+
+// WithNotFoundRouteLabel specifies the synthetic route recorded as
+// `http.route` attribute & used in the span name when no route matches the
+// request, e.g. `WithNotFoundRouteLabel("{not_found}")`. It keeps the span
+// names low-cardinality under scanner traffic & makes the not found requests
+// easy to group. By default the route is left empty & the span is named `/`.
+func WithNotFoundRouteLabel(label string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.notFoundRoute = label
+	})
+}
+
+// resolvedRoute returns the given route pattern resolved by chi prefixed by
+// the mount prefix, the label specified through `WithNotFoundRouteLabel` is
+// returned when no route matches the request.
+func (tw traceware) resolvedRoute(pattern string) string {
+	return tw.routes.Pattern(pattern)
+}
+
+// NewTracedNotFoundHandler returns the given not found handler wrapped with
+// the tracing middleware, so 404 responses of the requests not reaching the
+// middleware chain (e.g. when the middleware is installed through
+// `chi.Router.With` or `chi.Router.Group`) are still traced, e.g:
+//
+//	router.NotFound(otelchitrace.NewTracedNotFoundHandler(nil, "my-server"))
+//
+// If h is nil, `http.NotFound` is used.
+func NewTracedNotFoundHandler(h http.HandlerFunc, serverName string, opts ...Option) http.HandlerFunc {
+	if h == nil {
+		h = http.NotFound
+	}
+	return Middleware(serverName, opts...)(h).ServeHTTP
+}
+
+// NewTracedMethodNotAllowedHandler returns the given method not allowed
+// handler wrapped with the tracing middleware, see `NewTracedNotFoundHandler`
+// for details, e.g:
+//
+//	router.MethodNotAllowed(otelchitrace.NewTracedMethodNotAllowedHandler(nil, "my-server"))
+//
+// If h is nil, the handler responds with 405 status code.
+func NewTracedMethodNotAllowedHandler(h http.HandlerFunc, serverName string, opts ...Option) http.HandlerFunc {
+	if h == nil {
+		h = methodNotAllowed
+	}
+	return Middleware(serverName, opts...)(h).ServeHTTP
+}
+
+// methodNotAllowed replies to the request with 405 status code, it is the
+// same as the default chi handler.
+func methodNotAllowed(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusMethodNotAllowed)
+}
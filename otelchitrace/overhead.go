@@ -0,0 +1,123 @@
+package otelchitrace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	metricNameOverhead = "otelchi.middleware.overhead"
+	metricUnitOverhead = "s"
+	metricDescOverhead = "Measures the time spent by otelchi middleware itself for each request, excluding the wrapped handler."
+)
+
+// overheadBucketBoundaries are the histogram bucket boundaries (in seconds)
+// for the middleware overhead, it ranges from 1µs to 10ms.
+var overheadBucketBoundaries = []float64{
+	0.000001, 0.0000025, 0.000005, 0.00001, 0.000025, 0.00005,
+	0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01,
+}
+
+// WithOverheadMetric enables recording the time spent by the middleware
+// itself (e.g. extracting context, building attributes, writing headers) for
+// each request as `otelchi.middleware.overhead` histogram. The time spent in
+// the wrapped handler is excluded, so the metric could be used for
+// quantifying the instrumentation cost in production.
+//
+// The provider is only used for the overhead metric, the other metrics keep
+// using the one specified by `WithMeterProvider`. If the provider is `nil`,
+// the one specified by `WithMeterProvider` is used, falling back to the
+// global meter provider.
+func WithOverheadMetric(provider otelmetric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.overheadMetric = true
+		cfg.overheadMeterProvider = provider
+	})
+}
+
+// WithMeterProvider specifies the meter provider used for the metrics
+// recorded by the middleware itself (e.g. `WithOverheadMetric`,
+// `WithRequestQueueTimeMetric`). If none is specified, the global meter
+// provider is used.
+func WithMeterProvider(provider otelmetric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.meterProvider = provider
+	})
+}
+
+// newMeter returns the meter used for the metrics recorded by the middleware.
+func newMeter(cfg config) otelmetric.Meter {
+	provider := cfg.meterProvider
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	return provider.Meter(
+		cfg.instrumentationName(),
+		otelmetric.WithInstrumentationVersion(Version()),
+		otelmetric.WithSchemaURL(cfg.schemaURL),
+	)
+}
+
+// newOverheadHistogram creates the histogram for recording the middleware
+// overhead, it returns nil when the overhead metric is not enabled. When the
+// histogram cannot be created, the error is reported to the OpenTelemetry
+// error handler & the noop histogram is returned.
+func newOverheadHistogram(cfg config) otelmetric.Float64Histogram {
+	if !cfg.overheadMetric {
+		return nil
+	}
+	if cfg.overheadMeterProvider != nil {
+		cfg.meterProvider = cfg.overheadMeterProvider
+	}
+	histogram, err := newMeter(cfg).Float64Histogram(
+		metricNameOverhead,
+		otelmetric.WithDescription(metricDescOverhead),
+		otelmetric.WithUnit(metricUnitOverhead),
+		otelmetric.WithExplicitBucketBoundaries(overheadBucketBoundaries...),
+	)
+	if err != nil {
+		// the middleware keeps tracing without the metric
+		otel.Handle(fmt.Errorf("unable to create %s histogram: %w", metricNameOverhead, err))
+		return noop.Float64Histogram{}
+	}
+	return histogram
+}
+
+// overheadTimer measures the time spent by the middleware excluding the time
+// spent in the wrapped handler.
+type overheadTimer struct {
+	histogram    otelmetric.Float64Histogram
+	attrs        otelmetric.MeasurementOption
+	start        time.Time
+	handlerStart time.Time
+	handlerTime  time.Duration
+}
+
+func (t *overheadTimer) begin() {
+	if t.histogram != nil {
+		t.start = time.Now()
+	}
+}
+
+func (t *overheadTimer) beginHandler() {
+	if t.histogram != nil {
+		t.handlerStart = time.Now()
+	}
+}
+
+func (t *overheadTimer) endHandler() {
+	if t.histogram != nil {
+		t.handlerTime = time.Since(t.handlerStart)
+	}
+}
+
+func (t *overheadTimer) end(ctx context.Context) {
+	if t.histogram != nil {
+		t.histogram.Record(ctx, (time.Since(t.start) - t.handlerTime).Seconds(), t.attrs)
+	}
+}
@@ -0,0 +1,74 @@
+package otelchitrace
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// MiddlewareDurationKey is the attribute key of the time spent by the
+	// request outside of the wrapped handler (i.e. in otelchi middleware &
+	// the middlewares installed before it), in milliseconds.
+	MiddlewareDurationKey = attribute.Key("http.server.duration.middleware_ms")
+
+	// HandlerDurationKey is the attribute key of the time spent by the
+	// request in the wrapped handler (including the routing & the
+	// middlewares installed after otelchi) excluding the time spent writing
+	// the response, in milliseconds.
+	HandlerDurationKey = attribute.Key("http.server.duration.handler_ms")
+
+	// WriteDurationKey is the attribute key of the time spent writing the
+	// response header & body into the connection, in milliseconds.
+	WriteDurationKey = attribute.Key("http.server.duration.write_ms")
+)
+
+// WithPhaseTimings enables recording the time distribution of the request
+// broken into the phases as span attributes:
+//
+//   - `http.server.duration.middleware_ms`: the time spent outside of the
+//     wrapped handler, e.g. extracting the trace context.
+//   - `http.server.duration.handler_ms`: the time spent in the wrapped
+//     handler (including the routing & the downstream middlewares)
+//     excluding the time spent writing the response.
+//   - `http.server.duration.write_ms`: the time spent in the response
+//     writer, i.e. writing & flushing the response into the connection.
+//
+// This shows whether the latency comes from the middleware chain & the
+// business logic or from the response serialization (e.g. slow clients).
+// The phases are measured with the clock set by `WithClock`.
+func WithPhaseTimings() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.phaseTimings = true
+	})
+}
+
+// phaseTimer measures the time spent in the phases of the request.
+type phaseTimer struct {
+	now func() time.Time
+
+	start        time.Time
+	handlerStart time.Time
+	handler      time.Duration
+}
+
+// beginHandler marks the start of the wrapped handler.
+func (pt *phaseTimer) beginHandler() {
+	pt.handlerStart = pt.now()
+}
+
+// endHandler marks the end of the wrapped handler.
+func (pt *phaseTimer) endHandler() {
+	pt.handler = pt.now().Sub(pt.handlerStart)
+}
+
+// attributes returns the span attributes of the phase durations, the given
+// write duration is the time spent in the response writer.
+func (pt *phaseTimer) attributes(write time.Duration) []attribute.KeyValue {
+	total := pt.now().Sub(pt.start)
+	return []attribute.KeyValue{
+		MiddlewareDurationKey.Float64(durationMillis(max(total-pt.handler, 0))),
+		HandlerDurationKey.Float64(durationMillis(max(pt.handler-write, 0))),
+		WriteDurationKey.Float64(durationMillis(write)),
+	}
+}
@@ -0,0 +1,114 @@
+package otelchitrace
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconvstable "go.opentelemetry.io/otel/semconv/v1.26.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ProblemTitleKey is the attribute key of the `title` member of the
+	// RFC 9457 problem details response.
+	ProblemTitleKey = attribute.Key("problem.title")
+
+	// ProblemDetailKey is the attribute key of the `detail` member of the
+	// RFC 9457 problem details response.
+	ProblemDetailKey = attribute.Key("problem.detail")
+)
+
+// problemDetailsContentType is the media type of RFC 9457 problem details
+// response.
+const problemDetailsContentType = "application/problem+json"
+
+// WithProblemDetailsCapture enables recording the RFC 9457 problem details
+// written by the handler with `application/problem+json` content type. The
+// response body is captured up to maxBytes bytes, the problem details which
+// exceed it are not recorded.
+//
+// The problem `type` is recorded as `error.type` attribute, it falls back to
+// the response status code when the type is absent or `about:blank`. The
+// `title` & `detail` members are recorded as `problem.title` &
+// `problem.detail` attributes, and the title (or the detail when the title
+// is absent) is used as the description of the error span status.
+func WithProblemDetailsCapture(maxBytes int) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.problemDetailsMaxBytes = maxBytes
+	})
+}
+
+// problemDetails is RFC 9457 problem details object.
+type problemDetails struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+// problemCapture captures the problem details response body.
+type problemCapture struct {
+	maxBytes int
+
+	body    *cappedBuffer
+	checked bool
+}
+
+// onWrite captures the response body when it is problem details, the
+// content type is checked on the first write since the header is complete
+// by then.
+func (pc *problemCapture) onWrite(header http.Header, b []byte) {
+	if !pc.checked {
+		pc.checked = true
+		mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+		if err == nil && mediaType == problemDetailsContentType {
+			pc.body = &cappedBuffer{max: pc.maxBytes}
+		}
+	}
+	if pc.body != nil {
+		_, _ = pc.body.Write(b)
+	}
+}
+
+// problem returns the captured problem details, it returns false when the
+// response is not problem details or it couldn't be parsed.
+func (pc *problemCapture) problem() (problemDetails, bool) {
+	var problem problemDetails
+	if pc.body == nil || pc.body.truncated {
+		return problem, false
+	}
+	if err := json.Unmarshal(pc.body.Bytes(), &problem); err != nil {
+		return problem, false
+	}
+	return problem, true
+}
+
+// record records the captured problem details into the span, it returns
+// the span status description derived from the problem details.
+func (pc *problemCapture) record(span oteltrace.Span, status int) string {
+	problem, ok := pc.problem()
+	if !ok {
+		return ""
+	}
+
+	errorType := problem.Type
+	if len(errorType) == 0 || errorType == "about:blank" {
+		errorType = strconv.Itoa(status)
+	}
+	attrs := []attribute.KeyValue{semconvstable.ErrorTypeKey.String(errorType)}
+	if len(problem.Title) > 0 {
+		attrs = append(attrs, ProblemTitleKey.String(problem.Title))
+	}
+	if len(problem.Detail) > 0 {
+		attrs = append(attrs, ProblemDetailKey.String(problem.Detail))
+	}
+	span.SetAttributes(attrs...)
+
+	description := problem.Title
+	if len(description) == 0 {
+		description = problem.Detail
+	}
+	return description
+}
@@ -0,0 +1,144 @@
+package otelchitrace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// PropagationMode determines how the propagators specified by
+// `WithPropagatorsOrdered` extract the trace context.
+type PropagationMode int
+
+const (
+	// PropagationFirstValid applies the propagators in order & stops at the
+	// first one extracting a valid span context, the remaining propagators
+	// are not consulted. This is the default mode.
+	PropagationFirstValid PropagationMode = iota
+	// PropagationMergeBaggage applies all propagators, the span context is
+	// taken from the first one extracting a valid span context while the
+	// baggage extracted by all propagators is merged. When the baggage
+	// members conflict, the one extracted by the earlier propagator wins.
+	PropagationMergeBaggage
+)
+
+// WithPropagatorsOrdered specifies the propagators used for extracting the
+// trace context in the order of precedence, which is useful when migrating
+// between propagation formats where the requests may carry conflicting
+// headers, e.g. prefer W3C over B3:
+//
+//	otelchitrace.WithPropagatorsOrdered(propagation.TraceContext{}, b3.New())
+//
+// Unlike the composite propagator, the span context extracted by the later
+// propagator doesn't override the earlier one. See `WithPropagationMode` for
+// controlling whether the extraction stops at the first valid span context.
+// The trace context is injected by all propagators.
+func WithPropagatorsOrdered(propagators ...propagation.TextMapPropagator) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.propagators = orderedPropagators{propagators: propagators}
+	})
+}
+
+// WithPropagationMode specifies how the propagators specified by
+// `WithPropagatorsOrdered` extract the trace context, see `PropagationMode`
+// for details.
+func WithPropagationMode(mode PropagationMode) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.propagationMode = mode
+	})
+}
+
+// orderedPropagators is the propagator extracting the trace context from the
+// propagators in the order of precedence.
+type orderedPropagators struct {
+	propagators []propagation.TextMapPropagator
+	mode        PropagationMode
+}
+
+var _ propagation.TextMapPropagator = orderedPropagators{}
+
+func (p orderedPropagators) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	for _, prop := range p.propagators {
+		prop.Inject(ctx, carrier)
+	}
+}
+
+func (p orderedPropagators) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	parent := oteltrace.SpanContextFromContext(ctx)
+	if p.mode == PropagationFirstValid {
+		for _, prop := range p.propagators {
+			ctx = prop.Extract(ctx, carrier)
+			if sc := oteltrace.SpanContextFromContext(ctx); sc.IsValid() && !sc.Equal(parent) {
+				break
+			}
+		}
+		return ctx
+	}
+
+	var (
+		spanCtx oteltrace.SpanContext
+		bag     baggage.Baggage
+	)
+	for _, prop := range p.propagators {
+		extracted := prop.Extract(ctx, carrier)
+		if sc := oteltrace.SpanContextFromContext(extracted); !spanCtx.IsValid() && sc.IsValid() && !sc.Equal(parent) {
+			spanCtx = sc
+		}
+		bag = mergeBaggage(bag, baggage.FromContext(extracted))
+	}
+	if spanCtx.IsValid() {
+		ctx = oteltrace.ContextWithRemoteSpanContext(ctx, spanCtx)
+	}
+	if bag.Len() > 0 {
+		ctx = baggage.ContextWithBaggage(ctx, bag)
+	}
+	return ctx
+}
+
+func (p orderedPropagators) Fields() []string {
+	seen := make(map[string]struct{})
+	var fields []string
+	for _, prop := range p.propagators {
+		for _, field := range prop.Fields() {
+			if _, ok := seen[field]; ok {
+				continue
+			}
+			seen[field] = struct{}{}
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// mergeBaggage adds the members of the other baggage which don't exist in
+// the given baggage.
+func mergeBaggage(bag, other baggage.Baggage) baggage.Baggage {
+	for _, member := range other.Members() {
+		if len(bag.Member(member.Key()).Key()) > 0 {
+			continue
+		}
+		merged, err := bag.SetMember(member)
+		if err != nil {
+			continue
+		}
+		bag = merged
+	}
+	return bag
+}
+
+// WithTracePropagationOnly makes the middleware only extract the incoming
+// trace context, expose it to the handlers through the request context, and
+// write the trace response headers (see `WithTraceIDResponseHeader` &
+// `WithTraceResponseHeaders`) without starting the server span. This is useful
+// when the server span is already created by a sidecar (e.g. Envoy) so the
+// spans are not duplicated.
+//
+// The spans created by the handlers are the children of the incoming trace
+// context. The options related to the server span have no effect in this mode.
+func WithTracePropagationOnly() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.tracePropagationOnly = true
+	})
+}
@@ -0,0 +1,128 @@
+package otelchitrace
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconvstable "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// QueryRecordingMode determines how the query string of the request is
+// recorded in the `url.query` span attribute.
+type QueryRecordingMode int
+
+const (
+	// QueryRecordingOff doesn't record the query string. This is the default
+	// mode.
+	QueryRecordingOff QueryRecordingMode = iota
+	// QueryRecordingRedacted records the query string with the values of the
+	// sensitive parameters replaced by `REDACTED`, see
+	// `WithQueryRedactionDenylist` & `WithQueryRedactionAllowlist` for
+	// details.
+	QueryRecordingRedacted
+	// QueryRecordingFull records the query string as it is.
+	QueryRecordingFull
+)
+
+// defaultQueryDenylist contains the name fragments of the query parameters
+// whose values are redacted in `QueryRecordingRedacted` mode.
+var defaultQueryDenylist = []string{
+	"token",
+	"password",
+	"passwd",
+	"secret",
+	"signature",
+	"credential",
+	"auth",
+	"session",
+	"apikey",
+	"api_key",
+}
+
+// WithQueryRecording specifies how the query string of the request is
+// recorded in the `url.query` span attribute, see `QueryRecordingMode` for
+// details. If none is specified, the query string is not recorded.
+func WithQueryRecording(mode QueryRecordingMode) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.queryRecordingMode = mode
+	})
+}
+
+// WithQueryRedactionDenylist adds the name fragments of the query parameters
+// whose values are redacted in `QueryRecordingRedacted` mode. A parameter is
+// redacted when its name contains any of the fragments (case-insensitive).
+//
+// By default the parameters whose names contain `token`, `password`,
+// `passwd`, `secret`, `signature`, `credential`, `auth`, `session`, `apikey`
+// or `api_key` are redacted.
+func WithQueryRedactionDenylist(fragments ...string) Option {
+	return optionFunc(func(cfg *config) {
+		for _, fragment := range fragments {
+			cfg.queryDenylist = append(cfg.queryDenylist, strings.ToLower(fragment))
+		}
+	})
+}
+
+// WithQueryRedactionAllowlist specifies the names of the query parameters
+// (case-insensitive) whose values are never redacted in
+// `QueryRecordingRedacted` mode, even when they match the denylist.
+func WithQueryRedactionAllowlist(names ...string) Option {
+	return optionFunc(func(cfg *config) {
+		for _, name := range names {
+			cfg.queryAllowlist = append(cfg.queryAllowlist, strings.ToLower(name))
+		}
+	})
+}
+
+// queryAttributes returns the query string attribute of the request based on
+// the query recording mode.
+func (tw traceware) queryAttributes(r *http.Request) []attribute.KeyValue {
+	if tw.queryRecordingMode == QueryRecordingOff || len(r.URL.RawQuery) == 0 {
+		return nil
+	}
+	query := r.URL.RawQuery
+	if tw.queryRecordingMode == QueryRecordingRedacted {
+		query = tw.redactQuery(query)
+	}
+	return []attribute.KeyValue{semconvstable.URLQuery(query)}
+}
+
+// redactQuery replaces the values of the sensitive parameters in the raw
+// query string while preserving the order of the parameters.
+func (tw traceware) redactQuery(rawQuery string) string {
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		rawKey, _, hasValue := strings.Cut(param, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+		if hasValue && tw.isSensitiveQueryKey(strings.ToLower(key)) {
+			params[i] = rawKey + "=" + redactedQueryValue
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// isSensitiveQueryKey returns whether the value of the given lower-cased
+// query parameter must be redacted.
+func (tw traceware) isSensitiveQueryKey(key string) bool {
+	for _, name := range tw.queryAllowlist {
+		if key == name {
+			return false
+		}
+	}
+	for _, fragment := range defaultQueryDenylist {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	for _, fragment := range tw.queryDenylist {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
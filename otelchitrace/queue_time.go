@@ -0,0 +1,147 @@
+package otelchitrace
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/riandyrn/otelchi/internal/attrfilter"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// QueueDurationKey is the attribute key of the time (in milliseconds) the
+// request spent in the queue before reaching the server, see
+// `WithRequestQueueTimeMetric`.
+const QueueDurationKey = attribute.Key("http.server.queue_duration_ms")
+
+// DefaultRequestStartHeader is the request header used by
+// `WithRequestQueueTimeMetric` when no header is specified.
+const DefaultRequestStartHeader = "X-Request-Start"
+
+const (
+	metricNameQueueDuration = "http.server.queue.duration"
+	metricUnitQueueDuration = "s"
+	metricDescQueueDuration = "Measures the time the request spent in the queue between the load balancer & the server."
+)
+
+// queueDurationBucketBoundaries are the histogram bucket boundaries (in
+// seconds) for the queue duration, they are the ones recommended by the
+// semantic conventions for `http.server.request.duration`.
+var queueDurationBucketBoundaries = []float64{
+	0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10,
+}
+
+// WithRequestQueueTimeMetric enables recording the time the request spent in
+// the queue between the load balancer & the server, which is critical for
+// autoscaling decisions. The time is derived from the request start
+// timestamp set by the load balancer in the given header (e.g.
+// `X-Request-Start`), if the header is empty `X-Request-Start` is used.
+//
+// The time is recorded as `http.server.queue_duration_ms` span attribute (in
+// milliseconds) & `http.server.queue.duration` histogram (in seconds), the
+// meter provider is specified by `WithMeterProvider`.
+//
+// The header value could be a Unix timestamp in seconds (with optional
+// fraction, e.g. `t=1700000000.123` set by NGINX), milliseconds (e.g.
+// Heroku) or microseconds (e.g. `t=1700000000123456` set by Apache), with
+// optional `t=` prefix. The requests without valid timestamp or with
+// timestamp in the future (e.g. due to clock skew) are not recorded.
+func WithRequestQueueTimeMetric(header string) Option {
+	return optionFunc(func(cfg *config) {
+		if len(header) == 0 {
+			header = DefaultRequestStartHeader
+		}
+		cfg.requestStartHeader = header
+	})
+}
+
+// newQueueDurationHistogram creates the histogram for recording the queue
+// duration, it returns nil when the queue time metric is not enabled. When
+// the histogram cannot be created, the error is reported to the OpenTelemetry
+// error handler & the noop histogram is returned.
+func newQueueDurationHistogram(cfg config) otelmetric.Float64Histogram {
+	if len(cfg.requestStartHeader) == 0 {
+		return nil
+	}
+	histogram, err := newMeter(cfg).Float64Histogram(
+		metricNameQueueDuration,
+		otelmetric.WithDescription(metricDescQueueDuration),
+		otelmetric.WithUnit(metricUnitQueueDuration),
+		otelmetric.WithExplicitBucketBoundaries(queueDurationBucketBoundaries...),
+	)
+	if err != nil {
+		// the middleware keeps tracing without the metric
+		otel.Handle(fmt.Errorf("unable to create %s histogram: %w", metricNameQueueDuration, err))
+		return noop.Float64Histogram{}
+	}
+	return histogram
+}
+
+// queueDuration returns the time the request spent in the queue until the
+// given time, it returns false when the request start header is missing or
+// invalid.
+func (tw traceware) queueDuration(r *http.Request, now time.Time) (time.Duration, bool) {
+	if len(tw.requestStartHeader) == 0 {
+		return 0, false
+	}
+	start, ok := parseRequestStart(r.Header.Get(tw.requestStartHeader))
+	if !ok {
+		return 0, false
+	}
+	d := now.Sub(start)
+	if d < 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// recordQueueDuration records the queue duration into the histogram.
+func (tw traceware) recordQueueDuration(ctx context.Context, d time.Duration, route string) {
+	var attrs []attribute.KeyValue
+	if len(route) > 0 {
+		attrs = append(attrs, routeAttribute(route))
+	}
+	attrs = append(attrs, tw.staticAttributes...)
+	tw.queueDurationHistogram.Record(ctx, d.Seconds(), otelmetric.WithAttributes(attrfilter.Apply(tw.attributeFilter, attrs)...))
+}
+
+// parseRequestStart parses the request start timestamp set by the load
+// balancer, the unit of the integer timestamp is detected by its magnitude.
+func parseRequestStart(value string) (time.Time, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "t=")
+	if len(value) == 0 {
+		return time.Time{}, false
+	}
+	if strings.Contains(value, ".") {
+		secs, err := strconv.ParseFloat(value, 64)
+		if err != nil || secs <= 0 {
+			return time.Time{}, false
+		}
+		whole, frac := math.Modf(secs)
+		return time.Unix(int64(whole), int64(frac*float64(time.Second))), true
+	}
+	ts, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ts <= 0 {
+		return time.Time{}, false
+	}
+	switch {
+	case ts >= 1e15:
+		return time.UnixMicro(ts), true
+	case ts >= 1e12:
+		return time.UnixMilli(ts), true
+	default:
+		return time.Unix(ts, 0), true
+	}
+}
+
+// durationMillis returns the duration in milliseconds.
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
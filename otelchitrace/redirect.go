@@ -0,0 +1,62 @@
+package otelchitrace
+
+import (
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// RedirectEventName is the name of the span event recorded for redirect
+	// responses.
+	RedirectEventName = "http.redirect"
+
+	// RedirectLocationKey is the attribute key of the sanitized `Location`
+	// header of redirect responses.
+	RedirectLocationKey = attribute.Key("http.response.redirect_location")
+)
+
+// redactedQueryValue is the value recorded in place of the query parameter
+// values of the redirect location.
+const redactedQueryValue = "REDACTED"
+
+// recordRedirect records the location of the redirect response (e.g. written
+// by `http.Redirect`) into the span.
+func recordRedirect(span oteltrace.Span, status int, header http.Header, eventOpts ...oteltrace.EventOption) {
+	if status < http.StatusMultipleChoices || status >= http.StatusBadRequest {
+		return
+	}
+	location := header.Get("Location")
+	if len(location) == 0 {
+		return
+	}
+	attr := RedirectLocationKey.String(sanitizeLocation(location))
+	span.SetAttributes(attr)
+	span.AddEvent(RedirectEventName, append(eventOpts, oteltrace.WithAttributes(attr))...)
+}
+
+// sanitizeLocation removes the sensitive parts of the redirect location: the
+// user info & fragment are dropped, while the query parameter values (e.g.
+// OAuth authorization code) are redacted.
+func sanitizeLocation(location string) string {
+	u, err := url.Parse(location)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+	if len(u.RawQuery) > 0 {
+		query := u.Query()
+		for key, values := range query {
+			for i := range values {
+				values[i] = redactedQueryValue
+			}
+			query[key] = values
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
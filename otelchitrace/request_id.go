@@ -0,0 +1,83 @@
+package otelchitrace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDKey is the attribute key of the request ID recorded by
+// `WithRequestID`.
+const RequestIDKey = attribute.Key("http.request.id")
+
+// DefaultRequestIDHeader is the header used by `WithRequestID` when no
+// header name is specified.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+type requestIDCtxKey struct{}
+
+// WithRequestID enables the request ID correlation: the request ID is read
+// from the given request header (`X-Request-ID` when it is empty), recorded
+// as `http.request.id` span attribute and echoed in the same response
+// header. When the request doesn't have the header and generateIfMissing is
+// true, the trace ID of the span is used as the request ID, so the request
+// could be looked up in the tracing backend by it.
+//
+// The request ID is accessible by the handlers through
+// `RequestIDFromContext` as well as chi `middleware.GetReqID`, so this option
+// could replace chi `middleware.RequestID`. The request filtered out by the
+// middleware doesn't get the request ID.
+func WithRequestID(headerName string, generateIfMissing bool) Option {
+	return optionFunc(func(cfg *config) {
+		if len(headerName) == 0 {
+			headerName = DefaultRequestIDHeader
+		}
+		cfg.requestIDHeader = headerName
+		cfg.generateRequestID = generateIfMissing
+	})
+}
+
+// RequestIDFromContext returns the request ID recorded by the middleware for
+// the request owning the given context. The returned boolean is false when
+// the request has no request ID, e.g. `WithRequestID` is not used.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDCtxKey{}).(string)
+	return id, ok
+}
+
+// requestID returns the request ID of the given request, it returns empty
+// string when the request has no request ID & it should not be generated.
+func (tw traceware) requestID(r *http.Request, span oteltrace.Span) string {
+	if id := r.Header.Get(tw.requestIDHeader); len(id) > 0 {
+		return id
+	}
+	if !tw.generateRequestID {
+		return ""
+	}
+	if traceID := span.SpanContext().TraceID(); traceID.IsValid() {
+		return traceID.String()
+	}
+	// the span has no trace ID when the tracing is disabled (e.g. the no-op
+	// tracer provider is used)
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// withRequestID records the request ID into the span, the response header &
+// the returned context.
+func (tw traceware) withRequestID(ctx context.Context, r *http.Request, w http.ResponseWriter, span oteltrace.Span) context.Context {
+	id := tw.requestID(r, span)
+	if len(id) == 0 {
+		return ctx
+	}
+	span.SetAttributes(RequestIDKey.String(id))
+	w.Header().Set(tw.requestIDHeader, id)
+	ctx = context.WithValue(ctx, requestIDCtxKey{}, id)
+	return context.WithValue(ctx, middleware.RequestIDKey, id)
+}
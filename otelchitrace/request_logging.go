@@ -0,0 +1,56 @@
+package otelchitrace
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+)
+
+// WithRequestLogging enables emitting one OpenTelemetry log record per request
+// through the logger created from the given logger provider, providing access
+// log correlated with the traces. The record is emitted in the context of the
+// server span, so the SDK could attach the trace & span IDs to it, and it
+// contains the request method, route, response status code, & request
+// duration as attributes.
+//
+// The severity of the record is `INFO`, `WARN` for 4xx responses, and `ERROR`
+// for 5xx responses.
+func WithRequestLogging(loggerProvider log.LoggerProvider) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.loggerProvider = loggerProvider
+	})
+}
+
+// emitRequestLog emits the access log record of the given request.
+func (tw traceware) emitRequestLog(ctx context.Context, r *http.Request, route string, status int, startTime time.Time) {
+	endTime := tw.now()
+
+	var record log.Record
+	record.SetTimestamp(endTime)
+	record.SetSeverity(requestLogSeverity(status))
+	record.SetSeverityText(requestLogSeverity(status).String())
+	record.SetBody(log.StringValue(r.Method + " " + r.URL.Path))
+	record.AddAttributes(
+		log.String("http.request.method", r.Method),
+		log.String("url.path", r.URL.Path),
+		log.String("http.route", route),
+		log.Int("http.response.status_code", status),
+		log.Float64("http.server.request.duration", endTime.Sub(startTime).Seconds()),
+	)
+	tw.requestLogger.Emit(ctx, record)
+}
+
+// requestLogSeverity returns the severity of the access log record based on
+// the response status code.
+func requestLogSeverity(status int) log.Severity {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return log.SeverityError
+	case status >= http.StatusBadRequest:
+		return log.SeverityWarn
+	default:
+		return log.SeverityInfo
+	}
+}
@@ -0,0 +1,127 @@
+package otelchitrace
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// defaultResponseBodyStatusContentTypes are the content types of the response
+// bodies passed to the function set by `WithResponseBodyStatusFn` when no
+// content type is specified.
+var defaultResponseBodyStatusContentTypes = []string{"application/json"}
+
+// WithResponseBodyStatusFn specifies the function deriving the span status
+// from the response body, for the APIs which always respond with `200 OK`
+// and report the failure in the body instead, e.g. JSON-RPC or GraphQL. The
+// response body is captured up to maxBytes bytes when its content type
+// matches one of the given content types (which could use wildcard subtype,
+// e.g. `application/*`), by default only `application/json` body is
+// captured. The body exceeding maxBytes is not passed to the function.
+//
+// The function receives the response status code & the captured body, the
+// span status it returns overrides the one derived from the status code,
+// unless it returns `codes.Unset`. `JSONErrorStatus` could be used for
+// JSON-RPC & GraphQL responses.
+func WithResponseBodyStatusFn(maxBytes int, fn func(status int, body []byte) (codes.Code, string), contentTypes ...string) Option {
+	return optionFunc(func(cfg *config) {
+		if len(contentTypes) == 0 {
+			contentTypes = defaultResponseBodyStatusContentTypes
+		}
+		cfg.bodyStatusMaxBytes = maxBytes
+		cfg.bodyStatusFn = fn
+		cfg.bodyStatusContentTypes = contentTypes
+	})
+}
+
+// JSONErrorStatus is the function for `WithResponseBodyStatusFn` marking the
+// span as error when the JSON response body reports the failure, i.e. it has
+// non-null `error` member (JSON-RPC) or non-empty `errors` member (GraphQL).
+// The JSON-RPC batch response is failed when any of its responses is failed.
+// The description of the status is the message of the (first) error.
+func JSONErrorStatus(_ int, body []byte) (codes.Code, string) {
+	var responses []jsonErrorResponse
+	if err := json.Unmarshal(body, &responses); err != nil {
+		var response jsonErrorResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return codes.Unset, ""
+		}
+		responses = []jsonErrorResponse{response}
+	}
+	for _, response := range responses {
+		if message, failed := response.failure(); failed {
+			return codes.Error, message
+		}
+	}
+	return codes.Unset, ""
+}
+
+// jsonErrorResponse holds the error members of JSON-RPC & GraphQL responses.
+type jsonErrorResponse struct {
+	Error  json.RawMessage `json:"error"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// failure returns the message of the error reported by the response, it
+// returns false when the response is not failed.
+func (r jsonErrorResponse) failure() (string, bool) {
+	if len(r.Errors) > 0 {
+		return r.Errors[0].Message, true
+	}
+	if len(r.Error) == 0 || string(r.Error) == "null" {
+		return "", false
+	}
+	// the JSON-RPC error is an object, but some APIs report it as string
+	var rpcError struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(r.Error, &rpcError); err == nil {
+		return rpcError.Message, true
+	}
+	var message string
+	_ = json.Unmarshal(r.Error, &message)
+	return message, true
+}
+
+// responseStatusCapture captures the response body passed to the function set
+// by `WithResponseBodyStatusFn`.
+type responseStatusCapture struct {
+	maxBytes     int
+	contentTypes []string
+	fn           func(status int, body []byte) (codes.Code, string)
+
+	body    *cappedBuffer
+	checked bool
+}
+
+// onWrite captures the response body when its content type matches, the
+// content type is checked on the first write since the header is complete
+// by then.
+func (sc *responseStatusCapture) onWrite(header http.Header, b []byte) {
+	if !sc.checked {
+		sc.checked = true
+		if matchesContentType(header.Get("Content-Type"), sc.contentTypes) {
+			sc.body = &cappedBuffer{max: sc.maxBytes}
+		}
+	}
+	if sc.body != nil {
+		_, _ = sc.body.Write(b)
+	}
+}
+
+// status returns the span status derived from the captured response body, it
+// returns false when the body isn't captured or the function doesn't
+// override the status.
+func (sc *responseStatusCapture) status(status int) (codes.Code, string, bool) {
+	if sc.body == nil || sc.body.truncated {
+		return codes.Unset, "", false
+	}
+	code, description := sc.fn(status, sc.body.Bytes())
+	if code == codes.Unset {
+		return codes.Unset, "", false
+	}
+	return code, description, true
+}
@@ -0,0 +1,81 @@
+package otelchitrace
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/riandyrn/otelchi/otelchicore"
+)
+
+type responseInfoCtxKey struct{}
+
+// ResponseInfo is the snapshot of the response metadata recorded by the
+// middleware.
+type ResponseInfo struct {
+	// StatusCode is the status code of the response, it is `http.StatusOK`
+	// when the handler has not written the header explicitly.
+	StatusCode int
+	// BytesWritten is the number of bytes written into the response body.
+	BytesWritten int64
+	// Written is true when the handler has written either the header or the
+	// body of the response.
+	Written bool
+}
+
+// ResponseInfoFromContext returns the response metadata recorded by the
+// middleware for the request owning the given context. This allows other
+// middlewares (e.g. access logger) to reuse the measurements made by otelchi
+// instead of wrapping the response writer again.
+//
+// Since the metadata keeps changing while the request is being handled, the
+// function should be called after the handler is executed, e.g:
+//
+//	func logger(next http.Handler) http.Handler {
+//		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//			next.ServeHTTP(w, r)
+//			info, _ := otelchitrace.ResponseInfoFromContext(r.Context())
+//			log.Printf("%s %s %d %d", r.Method, r.URL.Path, info.StatusCode, info.BytesWritten)
+//		})
+//	}
+//
+// The returned boolean is false when the request is not handled by the
+// middleware (e.g. it is filtered out). The metadata is owned by the request,
+// so it is safe to be read concurrently & after the request is completed,
+// e.g. from the goroutine spawned by the handler.
+func ResponseInfoFromContext(ctx context.Context) (ResponseInfo, bool) {
+	info, ok := ctx.Value(responseInfoCtxKey{}).(*responseInfo)
+	if !ok {
+		return ResponseInfo{}, false
+	}
+	return info.load(), true
+}
+
+// responseInfo holds the response metadata of a single request, it is
+// updated by the recording response writer of the request.
+type responseInfo struct {
+	statusCode   atomic.Int64
+	bytesWritten atomic.Int64
+	written      atomic.Bool
+}
+
+func newResponseInfo() *responseInfo {
+	info := &responseInfo{}
+	info.statusCode.Store(http.StatusOK)
+	return info
+}
+
+// ObserveResponse stores the response metadata recorded by the given writer.
+func (i *responseInfo) ObserveResponse(rw *otelchicore.ResponseWriter) {
+	i.statusCode.Store(int64(rw.Status()))
+	i.bytesWritten.Store(rw.BytesWritten())
+	i.written.Store(rw.Written())
+}
+
+func (i *responseInfo) load() ResponseInfo {
+	return ResponseInfo{
+		StatusCode:   int(i.statusCode.Load()),
+		BytesWritten: i.bytesWritten.Load(),
+		Written:      i.written.Load(),
+	}
+}
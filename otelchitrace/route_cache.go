@@ -0,0 +1,35 @@
+package otelchitrace
+
+import (
+	"github.com/riandyrn/otelchi/otelchicore"
+)
+
+// RouteCache caches the route patterns resolved from the routes specified by
+// `WithChiRoutes`, keyed by the request method & path. It is safe for
+// concurrent use.
+//
+// The same cache could be shared with the metric recorders through
+// `otelchimetric.WithRouteCache` as long as they use the same routes. Call
+// `Invalidate` when the routes are changed after the requests are served.
+type RouteCache = otelchicore.RouteCache
+
+// DefaultRouteCacheSize is the recommended maximum number of entries of the
+// route cache.
+const DefaultRouteCacheSize = otelchicore.DefaultRouteCacheSize
+
+// NewRouteCache returns a new route cache holding at most size entries, the
+// cache is purged entirely once it is full so the memory stays bounded when
+// the paths contain identifiers (e.g. `/users/123`).
+func NewRouteCache(size int) *RouteCache {
+	return otelchicore.NewRouteCache(size)
+}
+
+// WithRouteCache specifies the cache used for resolving the route pattern
+// from the routes specified by `WithChiRoutes`, which saves matching the
+// routes on every request. This option has no effect without
+// `WithChiRoutes`.
+func WithRouteCache(cache *RouteCache) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.routeCache = cache
+	})
+}
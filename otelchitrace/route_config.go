@@ -0,0 +1,105 @@
+package otelchitrace
+
+import (
+	"strings"
+
+	"github.com/riandyrn/otelchi/internal/routeattr"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// routeConfig is used to override the middleware behavior for specific route.
+type routeConfig struct {
+	pattern         string
+	tracingDisabled bool
+	spanName        string
+	attributes      []attribute.KeyValue
+}
+
+// RouteOption specifies the override applied for specific route, see
+// `WithRouteConfig` for details.
+type RouteOption interface {
+	applyRoute(*routeConfig)
+}
+
+type routeOptionFunc func(*routeConfig)
+
+func (o routeOptionFunc) applyRoute(c *routeConfig) {
+	o(c)
+}
+
+// RouteDisableTracing disables tracing for the route.
+//
+// Since the route must be resolved before the span is started, this option
+// only takes effect when `WithChiRoutes` is also used.
+func RouteDisableTracing() RouteOption {
+	return routeOptionFunc(func(cfg *routeConfig) {
+		cfg.tracingDisabled = true
+	})
+}
+
+// RouteSpanName forces the span name of the route to the given name.
+func RouteSpanName(name string) RouteOption {
+	return routeOptionFunc(func(cfg *routeConfig) {
+		cfg.spanName = name
+	})
+}
+
+// RouteAttributes adds the given attributes to the span of the route.
+func RouteAttributes(attrs ...attribute.KeyValue) RouteOption {
+	return routeOptionFunc(func(cfg *routeConfig) {
+		cfg.attributes = append(cfg.attributes, attrs...)
+	})
+}
+
+// WithRouteConfig overrides the middleware behavior for the route matching the
+// given chi route pattern, e.g. disabling tracing for `/healthz` or adding
+// attributes for `/admin/*`.
+//
+// The pattern is matched against the route pattern resolved by chi, so it
+// must be written exactly as it is registered in the router (e.g.
+// `/users/{id}`). A pattern ending with `/*` also matches every route under
+// the prefix, e.g. `/admin/*` matches `/admin/users/{id}`. When multiple
+// configs match a route, the exact match takes precedence over the prefix
+// match, otherwise the first registered config is used.
+func WithRouteConfig(pattern string, opts ...RouteOption) Option {
+	return optionFunc(func(cfg *config) {
+		routeCfg := routeConfig{pattern: pattern}
+		for _, opt := range opts {
+			opt.applyRoute(&routeCfg)
+		}
+		cfg.routeConfigs = append(cfg.routeConfigs, routeCfg)
+	})
+}
+
+// WithRouteAttributes adds the static attributes (e.g. owner team, API tier,
+// deprecation flag) to the span of the routes keyed by chi route pattern,
+// driving ownership-based dashboards & alerts. The patterns are matched the
+// same way as `WithRouteConfig`. Use `otelchimetric.WithRouteAttributes` for
+// adding the same attributes to the metrics.
+//
+// When this option is used multiple times, the attributes are accumulated.
+func WithRouteAttributes(attrs map[string][]attribute.KeyValue) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.routeAttributes = append(cfg.routeAttributes, routeattr.New(attrs)...)
+	})
+}
+
+// lookupRouteConfig returns the config matching the given route pattern, it
+// returns nil when there is no matching config.
+func lookupRouteConfig(configs []routeConfig, routePattern string) *routeConfig {
+	if len(routePattern) == 0 {
+		return nil
+	}
+	var prefixMatch *routeConfig
+	for i := range configs {
+		pattern := configs[i].pattern
+		if pattern == routePattern {
+			return &configs[i]
+		}
+		if prefixMatch == nil && strings.HasSuffix(pattern, "/*") &&
+			strings.HasPrefix(routePattern, strings.TrimSuffix(pattern, "*")) {
+			prefixMatch = &configs[i]
+		}
+	}
+	return prefixMatch
+}
@@ -0,0 +1,116 @@
+package otelchitrace
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi/otelchicore"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type routeStateCtxKey struct{}
+
+// RouteOriginalKey is the span attribute key of the route pattern as it is
+// registered in chi, it is recorded when the pattern is changed by the
+// normalizer specified through `WithRoutePatternNormalizer`.
+const RouteOriginalKey = attribute.Key("http.route.original")
+
+// WithRoutePatternNormalizer specifies the function normalizing the route
+// pattern used in the span name & the `http.route` attribute, e.g.
+// `StripRouteRegexps` for removing the regular expression constraints which
+// some backends can't handle (`/users/{id:[0-9]+}` becomes `/users/{id}`).
+// The original pattern is kept as `http.route.original` attribute when it is
+// changed by the normalizer. The route configs (see `WithRouteConfig`) are
+// still matched against the original pattern.
+func WithRoutePatternNormalizer(fn func(pattern string) string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.routeNormalizer = fn
+	})
+}
+
+// StripRouteRegexps returns the given chi route pattern without the regular
+// expression constraints of its parameters, e.g. `/users/{id:[0-9]+}` becomes
+// `/users/{id}`. It could be used with `WithRoutePatternNormalizer`.
+func StripRouteRegexps(pattern string) string {
+	return otelchicore.StripRouteRegexps(pattern)
+}
+
+// newRouteResolver returns the resolver deriving the `http.route` value from
+// the chi route pattern, it is created once per middleware & shared by the
+// middleware and `RoutePattern`, so both always agree on the route.
+func newRouteResolver(cfg config) *otelchicore.RouteResolver {
+	return otelchicore.NewRouteResolver(otelchicore.RouteConfig{
+		Routes:        cfg.chiRoutes,
+		Cache:         cfg.routeCache,
+		MountPrefix:   cfg.mountPrefix,
+		NotFoundLabel: cfg.notFoundRoute,
+		Normalize:     cfg.routeNormalizer,
+		Limiter:       cfg.routeLimiter,
+	})
+}
+
+// limitedRoute returns the given route pattern normalized & limited as it is
+// recorded by the middleware.
+func (tw traceware) limitedRoute(pattern string) string {
+	return tw.routes.HTTPRoute(pattern)
+}
+
+// httpRouteAttributes returns the `http.route` attribute of the given route,
+// along with the original pattern when it is changed by the normalizer.
+func (tw traceware) httpRouteAttributes(route, pattern string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{routeAttribute(route)}
+	if tw.routeNormalizer != nil && len(pattern) > 0 && tw.routeNormalizer(pattern) != pattern {
+		attrs = append(attrs, RouteOriginalKey.String(pattern))
+	}
+	return attrs
+}
+
+// routeState holds the route pattern resolved by the middleware for a
+// request.
+type routeState struct {
+	pattern atomic.Pointer[string]
+	routes  *otelchicore.RouteResolver
+}
+
+func (s *routeState) set(pattern string) {
+	s.pattern.Store(&pattern)
+}
+
+// get returns the resolved route pattern, it is empty when the route is not
+// resolved yet.
+func (s *routeState) get() string {
+	if pattern := s.pattern.Load(); pattern != nil {
+		return *pattern
+	}
+	return ""
+}
+
+// RoutePattern returns the chi route pattern resolved by the middleware for
+// the request owning the given context, it is the same value recorded as
+// `http.route` attribute (including the fallback route set by
+// `WithMaxRouteCardinality`). This allows other middlewares, loggers, &
+// metric recorders to reuse the route resolved by the middleware instead of
+// deriving it again.
+//
+// When the route cannot be resolved before the span is started (i.e.
+// `WithChiRoutes` is not used), the route is available once chi has routed
+// the request, e.g. inside the handler or after the handler returns.
+//
+// The returned boolean is false when the route is not resolved yet or the
+// request is not handled by the middleware.
+func RoutePattern(ctx context.Context) (string, bool) {
+	state, ok := ctx.Value(routeStateCtxKey{}).(*routeState)
+	if !ok {
+		return "", false
+	}
+	if pattern := state.pattern.Load(); pattern != nil {
+		return *pattern, true
+	}
+	// the route is not resolved by the middleware yet, but chi might have
+	// routed the request already
+	if pattern, ok := state.routes.Routed(chi.RouteContext(ctx)); ok {
+		return state.routes.HTTPRoute(pattern), true
+	}
+	return "", false
+}
@@ -0,0 +1,46 @@
+package otelchitrace
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NewRouter returns a new chi router with the tracing middleware already
+// installed. The router is passed to the middleware through `WithChiRoutes`,
+// so the route pattern is resolved before the span is started, e.g:
+//
+//	router := otelchitrace.NewRouter("my-server")
+//	router.Get("/users/{id}", getUser)
+//
+// Use `WithRouterMiddlewares` for installing other middlewares (e.g. the
+// metric recorders) right after the tracing middleware.
+func NewRouter(serverName string, opts ...Option) *chi.Mux {
+	router := chi.NewRouter()
+
+	opts = append(opts, WithChiRoutes(router))
+	router.Use(Middleware(serverName, opts...))
+
+	var cfg config
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	router.Use(cfg.routerMiddlewares...)
+
+	return router
+}
+
+// WithRouterMiddlewares specifies the middlewares installed by `NewRouter`
+// right after the tracing middleware, e.g. the metric recorders:
+//
+//	router := otelchitrace.NewRouter(
+//		"my-server",
+//		otelchitrace.WithRouterMiddlewares(otelchimetric.MustNewAllMiddlewares(metricCfg)...),
+//	)
+//
+// This option is ignored by `Middleware`.
+func WithRouterMiddlewares(middlewares ...func(http.Handler) http.Handler) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.routerMiddlewares = append(cfg.routerMiddlewares, middlewares...)
+	})
+}
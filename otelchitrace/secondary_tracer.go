@@ -0,0 +1,103 @@
+package otelchitrace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+// WithSecondaryTracerProvider specifies the tracer provider receiving a copy
+// of every span recorded by the middleware in addition to the primary tracer
+// provider. This allows evaluating a new collector pipeline or sampling
+// policy side-by-side with the production pipeline.
+//
+// The primary span is the one put into the request context, so the trace
+// context propagated downstream & the trace ID written into the response
+// headers always come from the primary tracer provider. The secondary span
+// shares the parent of the incoming trace context, but when there is none
+// its trace ID is generated independently by the secondary tracer provider.
+//
+// The attributes, events & status set by the handlers on the span taken from
+// the request context are recorded into both spans.
+func WithSecondaryTracerProvider(provider oteltrace.TracerProvider) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.secondaryTracerProvider = provider
+	})
+}
+
+// teeTracer is the tracer starting every span on both primary & secondary
+// tracers.
+type teeTracer struct {
+	embedded.Tracer
+
+	primary   oteltrace.Tracer
+	secondary oteltrace.Tracer
+}
+
+var _ oteltrace.Tracer = teeTracer{}
+
+// Start starts the span on both tracers, the returned context holds the
+// span recording into both of them.
+func (t teeTracer) Start(ctx context.Context, spanName string, opts ...oteltrace.SpanStartOption) (context.Context, oteltrace.Span) {
+	// the secondary span is the child of the secondary span of the parent
+	secondaryCtx := ctx
+	if parent, ok := oteltrace.SpanFromContext(ctx).(teeSpan); ok {
+		secondaryCtx = oteltrace.ContextWithSpan(ctx, parent.secondary)
+	}
+	_, secondary := t.secondary.Start(secondaryCtx, spanName, opts...)
+	ctx, primary := t.primary.Start(ctx, spanName, opts...)
+
+	span := teeSpan{Span: primary, secondary: secondary}
+	return oteltrace.ContextWithSpan(ctx, span), span
+}
+
+// teeSpan is the span recording into both primary & secondary spans, its
+// span context is the one of the primary span.
+type teeSpan struct {
+	oteltrace.Span
+	secondary oteltrace.Span
+}
+
+var _ oteltrace.Span = teeSpan{}
+
+func (s teeSpan) End(options ...oteltrace.SpanEndOption) {
+	s.Span.End(options...)
+	s.secondary.End(options...)
+}
+
+func (s teeSpan) AddEvent(name string, options ...oteltrace.EventOption) {
+	s.Span.AddEvent(name, options...)
+	s.secondary.AddEvent(name, options...)
+}
+
+func (s teeSpan) AddLink(link oteltrace.Link) {
+	s.Span.AddLink(link)
+	s.secondary.AddLink(link)
+}
+
+func (s teeSpan) IsRecording() bool {
+	return s.Span.IsRecording() || s.secondary.IsRecording()
+}
+
+func (s teeSpan) RecordError(err error, options ...oteltrace.EventOption) {
+	s.Span.RecordError(err, options...)
+	s.secondary.RecordError(err, options...)
+}
+
+func (s teeSpan) SetStatus(code codes.Code, description string) {
+	s.Span.SetStatus(code, description)
+	s.secondary.SetStatus(code, description)
+}
+
+func (s teeSpan) SetName(name string) {
+	s.Span.SetName(name)
+	s.secondary.SetName(name)
+}
+
+func (s teeSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.Span.SetAttributes(kv...)
+	s.secondary.SetAttributes(kv...)
+}
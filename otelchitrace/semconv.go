@@ -0,0 +1,115 @@
+package otelchitrace
+
+import (
+	"net/http"
+
+	"github.com/riandyrn/otelchi/internal/semconvutil"
+	"github.com/riandyrn/otelchi/otelchicore"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/semconv/v1.20.0/httpconv"
+	semconvstable "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// SemconvMode determines which version of the HTTP semantic conventions is
+// used for the span attributes. The same mode could be used for the metric
+// recorders through `otelchimetric.WithSemconvVersion`.
+type SemconvMode = otelchicore.SemconvMode
+
+const (
+	// SemconvOld emits the attributes from semantic conventions v1.20.0 (e.g.
+	// `http.method`, `http.status_code`, `net.host.name`). This is the default
+	// mode.
+	SemconvOld = otelchicore.SemconvOld
+	// SemconvNew emits the attributes from the stable HTTP semantic
+	// conventions (e.g. `http.request.method`, `http.response.status_code`,
+	// `server.address`, `url.path`).
+	SemconvNew = otelchicore.SemconvNew
+	// SemconvDual emits the attributes from both old & stable HTTP semantic
+	// conventions, it is useful during the migration of dashboards & alerts.
+	SemconvDual = otelchicore.SemconvDual
+)
+
+// WithSemconvVersion specifies which version of the HTTP semantic conventions
+// is used for the span attributes, see `SemconvMode` for details.
+//
+// If this option is not set, the mode is determined by the
+// `OTEL_SEMCONV_STABILITY_OPT_IN` environment variable: `http` selects
+// `SemconvNew`, `http/dup` selects `SemconvDual`, otherwise `SemconvOld` is
+// used.
+func WithSemconvVersion(mode SemconvMode) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.semconvMode = mode
+	})
+}
+
+// semconvSchemaURL returns the schema URL matching the semantic conventions
+// mode.
+func semconvSchemaURL(m SemconvMode) string {
+	if m == SemconvNew {
+		return semconvstable.SchemaURL
+	}
+	return semconv.SchemaURL
+}
+
+// appendRequestAttributes appends the attributes describing the given request
+// based on the semantic conventions mode to attrs.
+func (tw traceware) appendRequestAttributes(attrs []attribute.KeyValue, r *http.Request) []attribute.KeyValue {
+	serverName := tw.currentServerName(r)
+
+	if tw.semconvMode.EmitsOld() {
+		if tw.lowAllocationMode {
+			attrs = tw.appendServerRequestAttributes(attrs, serverName, r)
+		} else {
+			attrs = append(attrs, httpconv.ServerRequest(serverName, r)...)
+		}
+	}
+	if tw.semconvMode.EmitsNew() {
+		clientIPCfg := tw.clientIP
+		if clientIPCfg == nil {
+			clientIPCfg = &ClientIPConfig{}
+		}
+
+		scheme, host := forwardedOrigin(clientIPCfg, r)
+		req := r
+		if len(host) > 0 {
+			// the server address & port are resolved from the host requested
+			// by the client instead of the one requested by the proxy
+			origin := *r
+			origin.Host = host
+			req = &origin
+		}
+		start := len(attrs)
+		attrs = append(attrs, semconvutil.HTTPServerRequest(serverName, req)...)
+		if len(scheme) > 0 {
+			for i := start; i < len(attrs); i++ {
+				if attrs[i].Key == semconvstable.URLSchemeKey {
+					attrs[i] = semconvstable.URLScheme(scheme)
+				}
+			}
+		}
+		if addr := resolveClientIP(clientIPCfg, r); len(addr) > 0 {
+			attrs = append(attrs, semconvstable.ClientAddress(addr))
+		}
+	}
+	return attrs
+}
+
+// routeAttribute returns the route attribute, its key is the same in both old
+// & stable semantic conventions.
+func routeAttribute(route string) attribute.KeyValue {
+	return semconv.HTTPRoute(route)
+}
+
+// statusCodeAttributes returns the response status code attributes based on
+// the semantic conventions mode.
+func (tw traceware) statusCodeAttributes(status int) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if tw.semconvMode.EmitsOld() {
+		attrs = append(attrs, semconv.HTTPStatusCode(status))
+	}
+	if tw.semconvMode.EmitsNew() {
+		attrs = append(attrs, semconvstable.HTTPResponseStatusCode(status))
+	}
+	return attrs
+}
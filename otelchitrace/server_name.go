@@ -0,0 +1,58 @@
+package otelchitrace
+
+import (
+	"net/http"
+
+	"github.com/riandyrn/otelchi/otelchicore"
+)
+
+// ServerName holds the server name which could be updated at runtime, it is
+// safe for concurrent use. This is useful for multi-tenant gateways that only
+// learn their public hostname after startup.
+//
+// The same holder could be shared with the metric recorders through
+// `otelchimetric.WithDynamicServerName`.
+type ServerName = otelchicore.ServerName
+
+// NewServerName returns a new server name holder initialized with the given
+// name. Use `Set` to update the name at runtime.
+func NewServerName(name string) *ServerName {
+	return otelchicore.NewServerName(name)
+}
+
+// WithDynamicServerName specifies the server name holder used for the server
+// name attribute. When set, the serverName parameter passed to `Middleware`
+// is ignored and the latest name stored in the holder is used for every
+// request instead.
+func WithDynamicServerName(name *ServerName) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.dynamicServerName = name
+	})
+}
+
+// WithServerNameFn specifies the function used for deriving the server name
+// attribute (`net.host.name` or `server.address`) per request, e.g. from the
+// `Host` header for multi-domain deployments. When the function returns an
+// empty string, the server name falls back to the one given to
+// `WithDynamicServerName` or `Middleware`.
+//
+// Use `otelchimetric.WithServerNameFn` for the metric recorders.
+func WithServerNameFn(fn func(r *http.Request) string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.serverNameFn = fn
+	})
+}
+
+// currentServerName returns the effective server name of the middleware for
+// the given request.
+func (tw traceware) currentServerName(r *http.Request) string {
+	if tw.serverNameFn != nil {
+		if name := tw.serverNameFn(r); len(name) > 0 {
+			return name
+		}
+	}
+	if tw.dynamicServerName != nil {
+		return tw.dynamicServerName.Get()
+	}
+	return tw.serverName
+}
@@ -0,0 +1,40 @@
+package otelchitrace
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// serverTimingHeader is the response header used for communicating the
+// server timing metrics to the browser.
+const serverTimingHeader = "Server-Timing"
+
+// WithServerTimingHeader enables writing the `Server-Timing` response header
+// reflecting the server processing duration & the trace context of the span,
+// e.g:
+//
+//	Server-Timing: traceparent;desc="00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", app;dur=12.345
+//
+// The `app` duration is measured until the response header is written, in
+// milliseconds. The header allows the browser devtools & RUM agents to
+// correlate the page loads with the backend traces. The `traceparent` entry
+// is omitted when the span context is invalid.
+func WithServerTimingHeader() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.serverTimingHeader = true
+	})
+}
+
+// writeServerTiming writes the `Server-Timing` header of the request started
+// at the given time into the response header.
+func (tw traceware) writeServerTiming(header http.Header, spanCtx oteltrace.SpanContext, start time.Time) {
+	var value string
+	if spanCtx.IsValid() {
+		value = `traceparent;desc="00-` + spanCtx.TraceID().String() + "-" + spanCtx.SpanID().String() + "-" + spanCtx.TraceFlags().String() + `", `
+	}
+	value += "app;dur=" + strconv.FormatFloat(durationMillis(tw.now().Sub(start)), 'f', 3, 64)
+	header.Add(serverTimingHeader, value)
+}
@@ -0,0 +1,34 @@
+package otelchitrace
+
+import (
+	"net/http"
+
+	"github.com/riandyrn/otelchi/internal/shadow"
+)
+
+// ShadowRequestKey is the attribute key used for marking shadow (mirrored)
+// requests.
+const ShadowRequestKey = shadow.Key
+
+// DefaultShadowRequestHeader is the request header used for detecting shadow
+// requests by `WithShadowTraffic` when no detection function is specified.
+const DefaultShadowRequestHeader = shadow.DefaultHeader
+
+// WithShadowTraffic enables tagging the spans of shadow (mirrored) requests
+// with `request.shadow=true` attribute, so the traffic replayed during
+// traffic-mirroring rollouts could be told apart from production traffic.
+//
+// The fn is used for detecting the shadow requests. If it is set to `nil`,
+// requests having `X-Shadow-Request: true` header are considered as shadow
+// requests.
+//
+// Use `otelchimetric.WithShadowTraffic` for excluding shadow requests from
+// metrics.
+func WithShadowTraffic(fn func(r *http.Request) bool) Option {
+	return optionFunc(func(cfg *config) {
+		if fn == nil {
+			fn = shadow.FromDefaultHeader
+		}
+		cfg.shadowRequestFn = fn
+	})
+}
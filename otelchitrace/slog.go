@@ -0,0 +1,98 @@
+package otelchitrace
+
+import (
+	"context"
+	"log/slog"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// The keys of the attributes added by the handler returned by `SlogHandler`.
+const (
+	SlogTraceIDKey = "trace_id"
+	SlogSpanIDKey  = "span_id"
+	SlogRouteKey   = "http.route"
+)
+
+// SlogHandler returns the slog handler adding the trace id, the span id & the
+// route of the request owning the context of the log record into the record
+// before passing it to the inner handler, so the logs could be correlated with
+// the traces, e.g:
+//
+//	logger := slog.New(otelchitrace.SlogHandler(slog.NewJSONHandler(os.Stdout, nil)))
+//	logger.InfoContext(r.Context(), "user created")
+//
+// The attributes are only added when they are available in the context.
+func SlogHandler(inner slog.Handler) slog.Handler {
+	return slogHandler{inner: inner}
+}
+
+// WithSlogLogger enables storing the given logger pre-annotated with the trace
+// id, the span id & the route of the request (see `SlogHandler`) into the
+// request context, it is retrievable through `LoggerFromContext`. The logger
+// is annotated even when it is used without the request context, e.g.
+// `LoggerFromContext(ctx).Info("user created")`.
+func WithSlogLogger(logger *slog.Logger) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.slogLogger = logger
+	})
+}
+
+type slogLoggerCtxKey struct{}
+
+// LoggerFromContext returns the logger stored into the given request context
+// by the middleware when `WithSlogLogger` is used. Otherwise, it returns the
+// default logger annotated with the trace & the route information available
+// in the given context.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(slogLoggerCtxKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return newRequestSlogLogger(ctx, slog.Default())
+}
+
+// newRequestSlogLogger returns the given logger annotated with the trace &
+// the route information of the given request context.
+func newRequestSlogLogger(ctx context.Context, logger *slog.Logger) *slog.Logger {
+	return slog.New(slogHandler{inner: logger.Handler(), ctx: ctx})
+}
+
+// slogHandler is the slog handler adding the trace & the route information
+// into the log records.
+type slogHandler struct {
+	inner slog.Handler
+	// ctx is the request context used when the context of the record doesn't
+	// carry the span, it is optional
+	ctx context.Context
+}
+
+var _ slog.Handler = slogHandler{}
+
+func (h slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	reqCtx := ctx
+	if h.ctx != nil && !oteltrace.SpanContextFromContext(ctx).IsValid() {
+		reqCtx = h.ctx
+	}
+	if spanCtx := oteltrace.SpanContextFromContext(reqCtx); spanCtx.IsValid() {
+		record.AddAttrs(
+			slog.String(SlogTraceIDKey, spanCtx.TraceID().String()),
+			slog.String(SlogSpanIDKey, spanCtx.SpanID().String()),
+		)
+	}
+	if route, ok := RoutePattern(reqCtx); ok {
+		record.AddAttrs(slog.String(SlogRouteKey, route))
+	}
+	return h.inner.Handle(ctx, record)
+}
+
+func (h slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return slogHandler{inner: h.inner.WithAttrs(attrs), ctx: h.ctx}
+}
+
+func (h slogHandler) WithGroup(name string) slog.Handler {
+	return slogHandler{inner: h.inner.WithGroup(name), ctx: h.ctx}
+}
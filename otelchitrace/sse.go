@@ -0,0 +1,86 @@
+package otelchitrace
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/riandyrn/otelchi/otelchicore"
+	"go.opentelemetry.io/otel/attribute"
+	semconvstable "go.opentelemetry.io/otel/semconv/v1.26.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// SSEFlushEventName is the name of the span event added on each flush
+	// of Server-Sent Events stream.
+	SSEFlushEventName = "sse.flush"
+
+	// SSEBytesSentKey is the attribute key of the number of bytes sent into
+	// the Server-Sent Events stream so far.
+	SSEBytesSentKey = attribute.Key("sse.bytes_sent")
+
+	// SSEFlushCountKey is the attribute key of the number of flushes made
+	// into the Server-Sent Events stream.
+	SSEFlushCountKey = attribute.Key("sse.flush_count")
+)
+
+const eventStreamContentType = "text/event-stream"
+
+// WithSSEInstrumentation enables the instrumentation of Server-Sent Events
+// responses, i.e. responses with `text/event-stream` content type.
+//
+// The server span covers the whole duration of the stream, on each flush
+// the middleware adds `sse.flush` event to the span containing the number of
+// bytes sent so far. When the stream is completed, the total number of bytes
+// sent is recorded as `http.response.body.size` attribute.
+func WithSSEInstrumentation() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.sseInstrumentation = true
+	})
+}
+
+// sseRecorder records the flushes of Server-Sent Events stream into the span.
+type sseRecorder struct {
+	tw         traceware
+	span       oteltrace.Span
+	rrw        *otelchicore.ResponseWriter
+	flushCount int64
+}
+
+// onFlush is called after the response writer is flushed.
+func (s *sseRecorder) onFlush() {
+	if !isEventStream(s.rrw.Writer().Header()) {
+		return
+	}
+	s.flushCount++
+	opts := []oteltrace.EventOption{
+		oteltrace.WithAttributes(
+			SSEBytesSentKey.Int64(s.rrw.BytesWritten()),
+			SSEFlushCountKey.Int64(s.flushCount),
+		),
+	}
+	opts = append(opts, s.tw.clockEventOptions()...)
+	s.span.AddEvent(SSEFlushEventName, opts...)
+}
+
+// end records the summary of the stream into the span.
+func (s *sseRecorder) end() {
+	if !isEventStream(s.rrw.Writer().Header()) {
+		return
+	}
+	s.span.SetAttributes(
+		semconvstable.HTTPResponseBodySize(int(s.rrw.BytesWritten())),
+		SSEFlushCountKey.Int64(s.flushCount),
+	)
+}
+
+// isEventStream checks whether the response header declares Server-Sent
+// Events stream.
+func isEventStream(header http.Header) bool {
+	contentType := header.Get("Content-Type")
+	if len(contentType) == 0 {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == eventStreamContentType
+}
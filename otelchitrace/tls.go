@@ -0,0 +1,52 @@
+package otelchitrace
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	tlsClientSubjectKey           = attribute.Key("tls.client.subject")
+	tlsClientSubjectCommonNameKey = attribute.Key("tls.client.subject.common_name")
+	tlsClientSubjectAltNamesKey   = attribute.Key("tls.client.subject.alt_names")
+)
+
+// tlsClientAttributes returns the attributes describing the identity of the
+// verified client certificate, it returns nil when the client certificate is
+// not present or not verified.
+func tlsClientAttributes(r *http.Request, redactFn func(value string) string) []attribute.KeyValue {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	if redactFn == nil {
+		redactFn = func(value string) string { return value }
+	}
+
+	// the first certificate in the chain is the leaf certificate
+	cert := r.TLS.VerifiedChains[0][0]
+	attrs := []attribute.KeyValue{
+		tlsClientSubjectKey.String(redactFn(cert.Subject.String())),
+	}
+	if len(cert.Subject.CommonName) > 0 {
+		attrs = append(attrs, tlsClientSubjectCommonNameKey.String(redactFn(cert.Subject.CommonName)))
+	}
+
+	var altNames []string
+	altNames = append(altNames, cert.DNSNames...)
+	altNames = append(altNames, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		altNames = append(altNames, uri.String())
+	}
+	for _, ip := range cert.IPAddresses {
+		altNames = append(altNames, ip.String())
+	}
+	if len(altNames) > 0 {
+		for i := range altNames {
+			altNames[i] = redactFn(altNames[i])
+		}
+		attrs = append(attrs, tlsClientSubjectAltNamesKey.StringSlice(altNames))
+	}
+
+	return attrs
+}
@@ -0,0 +1,38 @@
+package otelchitrace
+
+import (
+	"encoding/hex"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// traceparentLen is the length of the version 00 `traceparent` header value,
+// e.g. `00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01`.
+const traceparentLen = 2 + 1 + 32 + 1 + 16 + 1 + 2
+
+// formatTraceID returns the hex encoded trace id, it is the same as
+// `TraceID.String` except the encoding is done in a stack buffer so only the
+// returned string is allocated.
+func formatTraceID(traceID oteltrace.TraceID) string {
+	var buf [32]byte
+	hex.Encode(buf[:], traceID[:])
+	return string(buf[:])
+}
+
+// formatTraceparent returns the `traceparent` header value of the given span
+// context the same way as `propagation.TraceContext` does, without going
+// through fmt.Sprintf. Only the sampled flag is propagated.
+func formatTraceparent(spanCtx oteltrace.SpanContext) string {
+	traceID := spanCtx.TraceID()
+	spanID := spanCtx.SpanID()
+	flags := [1]byte{byte(spanCtx.TraceFlags() & oteltrace.FlagsSampled)}
+
+	var buf [traceparentLen]byte
+	b := append(buf[:0], "00-"...)
+	b = hex.AppendEncode(b, traceID[:])
+	b = append(b, '-')
+	b = hex.AppendEncode(b, spanID[:])
+	b = append(b, '-')
+	b = hex.AppendEncode(b, flags[:])
+	return string(b)
+}
@@ -0,0 +1,63 @@
+package otelchitrace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+// WithDynamicTracerProvider makes the middleware resolve the tracer from the
+// global tracer provider (`otel.GetTracerProvider`) on every request instead
+// of once when the middleware is created. This allows the SDK to be
+// initialized after the router is built, and the global tracer provider to be
+// swapped at runtime (e.g. in tests or serverless functions).
+//
+// This option has no effect when `WithTracerProvider` is used.
+func WithDynamicTracerProvider() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.dynamicTracerProvider = true
+	})
+}
+
+// WithTracerName overrides the instrumentation scope name of the tracer (as
+// well as the meter & the logger) used by the middleware, so platform wrappers
+// could attribute the spans to their own scope. The default name is the
+// module path `github.com/riandyrn/otelchi`.
+func WithTracerName(name string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.tracerName = name
+	})
+}
+
+// WithTracer specifies the tracer used for creating the spans, e.g. the one
+// created with custom tracer options. It takes precedence over
+// `WithTracerProvider`, `WithDynamicTracerProvider` & `WithTracerName`.
+func WithTracer(tracer oteltrace.Tracer) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.tracer = tracer
+	})
+}
+
+// instrumentationName returns the instrumentation scope name of the tracer,
+// the meter & the logger used by the middleware.
+func (cfg config) instrumentationName() string {
+	if len(cfg.tracerName) > 0 {
+		return cfg.tracerName
+	}
+	return tracerName
+}
+
+// globalTracer is a tracer which delegates every span creation to the tracer
+// obtained from the current global tracer provider.
+type globalTracer struct {
+	embedded.Tracer
+
+	name string
+	opts []oteltrace.TracerOption
+}
+
+func (t globalTracer) Start(ctx context.Context, spanName string, opts ...oteltrace.SpanStartOption) (context.Context, oteltrace.Span) {
+	return otel.GetTracerProvider().Tracer(t.name, t.opts...).Start(ctx, spanName, opts...)
+}
@@ -0,0 +1,87 @@
+package otelchitrace
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// grpcStatusTrailer & grpcMessageTrailer are the trailers carrying the status
+// of the gRPC call, e.g. the grpc-web responses proxied through chi.
+const (
+	grpcStatusTrailer  = "Grpc-Status"
+	grpcMessageTrailer = "Grpc-Message"
+)
+
+// WithCapturedResponseTrailers enables recording the given response trailers
+// as `http.response.trailer.<key>` span attributes once the handler finishes,
+// where `<key>` is the lowercase trailer name, e.g.
+// `http.response.trailer.grpc-status`. Both the trailers declared through the
+// `Trailer` header & the ones set with `http.TrailerPrefix` are captured.
+//
+// When `Grpc-Status` trailer is captured & it is not `0` (OK), the span status
+// is set to error described by `Grpc-Message` trailer, since the gRPC failures
+// (e.g. of grpc-web traffic) are responded with 200 status code.
+//
+// When this option is used multiple times, the trailers are accumulated.
+func WithCapturedResponseTrailers(keys ...string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.capturedResponseTrailers = append(cfg.capturedResponseTrailers, keys...)
+	})
+}
+
+// trailerAttributes returns the attributes of the given trailers which are
+// set in h after the handler finishes.
+func trailerAttributes(h http.Header, keys []string) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for _, key := range keys {
+		if values := trailerValues(h, key); len(values) > 0 {
+			attrs = append(attrs, attribute.StringSlice("http.response.trailer."+strings.ToLower(key), values))
+		}
+	}
+	return attrs
+}
+
+// trailerValues returns the values of the given trailer, it is either
+// declared through the `Trailer` header or set with `http.TrailerPrefix`.
+func trailerValues(h http.Header, key string) []string {
+	key = http.CanonicalHeaderKey(key)
+	if values := h[http.TrailerPrefix+key]; len(values) > 0 {
+		return values
+	}
+	for _, declared := range h.Values("Trailer") {
+		for _, name := range strings.Split(declared, ",") {
+			if http.CanonicalHeaderKey(strings.TrimSpace(name)) == key {
+				return h[key]
+			}
+		}
+	}
+	return nil
+}
+
+// grpcTrailerStatus returns the span status of the gRPC call described by the
+// response trailers, it returns false when the trailers are not captured or
+// the call succeeded.
+func (tw traceware) grpcTrailerStatus(h http.Header) (codes.Code, string, bool) {
+	captured := false
+	for _, key := range tw.capturedResponseTrailers {
+		if http.CanonicalHeaderKey(key) == grpcStatusTrailer {
+			captured = true
+			break
+		}
+	}
+	if !captured {
+		return codes.Unset, "", false
+	}
+	status := trailerValues(h, grpcStatusTrailer)
+	if len(status) == 0 || status[0] == "0" {
+		return codes.Unset, "", false
+	}
+	description := "grpc-status " + status[0]
+	if message := trailerValues(h, grpcMessageTrailer); len(message) > 0 {
+		description = message[0]
+	}
+	return codes.Error, description, true
+}
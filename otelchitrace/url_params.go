@@ -0,0 +1,50 @@
+package otelchitrace
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// urlParamAttributePrefix is the prefix of the attribute keys of the URL
+// parameters recorded by `WithURLParamsAsAttributes`.
+const urlParamAttributePrefix = "http.route.param."
+
+// WithURLParamsAsAttributes enables recording the chi URL parameters whose
+// names are in the allowlist as `http.route.param.<name>` span attributes,
+// e.g. `{org}` in `/orgs/{org}/projects` is recorded as `http.route.param.org`.
+// This is useful for filtering traces by resource identifiers.
+//
+// Only the parameters in the allowlist are recorded to avoid leaking
+// sensitive values such as tokens. When this option is used multiple times,
+// the allowlists are accumulated.
+func WithURLParamsAsAttributes(allowlist ...string) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.urlParamsAllowlist = append(cfg.urlParamsAllowlist, allowlist...)
+	})
+}
+
+// urlParamAttributes returns the attributes of the allowed URL parameters
+// matched by chi for the given request.
+func (tw traceware) urlParamAttributes(r *http.Request) []attribute.KeyValue {
+	if len(tw.urlParamsAllowlist) == 0 {
+		return nil
+	}
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return nil
+	}
+	var attrs []attribute.KeyValue
+	for _, name := range tw.urlParamsAllowlist {
+		for i := len(rctx.URLParams.Keys) - 1; i >= 0; i-- {
+			// the params of the nested routers are appended, so the last
+			// one takes precedence like `chi.URLParam`
+			if rctx.URLParams.Keys[i] == name {
+				attrs = append(attrs, attribute.String(urlParamAttributePrefix+name, rctx.URLParams.Values[i]))
+				break
+			}
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,49 @@
+package otelchitrace
+
+import (
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// modulePath is the module path of otelchi, it is also used as the
+// instrumentation scope name.
+const modulePath = "github.com/riandyrn/otelchi"
+
+// releaseVersion is the current release version of otelchi, it is used when
+// the module version cannot be determined from the build info (e.g. when
+// otelchi is the main module or it is replaced by local directory).
+const releaseVersion = "0.11.0"
+
+// Version is the current release version of otelchi in use. The version is
+// taken from the build info of the binary when it is available, so it
+// reflects the version of the module actually compiled in.
+func Version() string {
+	return buildInfoVersion()
+}
+
+// SemVersion is the semantic version to be supplied to tracer/meter creation.
+func SemVersion() string {
+	return "semver:" + Version()
+}
+
+// buildInfoVersion returns the version of otelchi module recorded in the
+// build info, it falls back to releaseVersion when it is not available.
+var buildInfoVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return releaseVersion
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		if version := strings.TrimPrefix(dep.Version, "v"); len(version) > 0 && version != dep.Version {
+			return version
+		}
+	}
+	return releaseVersion
+})
@@ -0,0 +1,91 @@
+package otelchitrace
+
+import (
+	"net"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// WebsocketUpgradeKey is the attribute key used for marking requests which
+// connection is hijacked for WebSocket upgrade.
+const WebsocketUpgradeKey = attribute.Key("http.websocket.upgrade")
+
+// WebsocketSpanMode determines when the server span of WebSocket upgrade
+// request is ended.
+type WebsocketSpanMode int
+
+const (
+	// WebsocketEndOnUpgrade ends the server span as soon as the connection is
+	// hijacked for WebSocket upgrade, so the span only covers the handshake.
+	WebsocketEndOnUpgrade WebsocketSpanMode = iota + 1
+	// WebsocketEndOnClose ends the server span when the hijacked connection
+	// is closed, so the span covers the whole connection lifetime even when
+	// the handler returns right after the upgrade.
+	WebsocketEndOnClose
+)
+
+// WithWebsocketSpanMode specifies when the server span of WebSocket upgrade
+// request is ended, see `WebsocketSpanMode` for details. If this option is
+// not set, the span is ended when the handler returns.
+func WithWebsocketSpanMode(mode WebsocketSpanMode) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.websocketSpanMode = mode
+	})
+}
+
+// websocketTracker tracks the connection hijacked for WebSocket upgrade and
+// ends the server span according to the configured mode.
+type websocketTracker struct {
+	span oteltrace.Span
+	mode WebsocketSpanMode
+	// beforeEnd is called right before the span is ended, it is used for
+	// completing the span (e.g. resolving the span name) when the span is
+	// ended before the handler returns
+	beforeEnd func()
+	endOpts   func() []oteltrace.SpanEndOption
+	hijacked  bool
+	once      sync.Once
+}
+
+// onHijack is called after the connection is successfully hijacked.
+func (wt *websocketTracker) onHijack(conn net.Conn) net.Conn {
+	wt.hijacked = true
+	wt.span.SetAttributes(WebsocketUpgradeKey.Bool(true))
+	switch wt.mode {
+	case WebsocketEndOnUpgrade:
+		wt.end()
+	case WebsocketEndOnClose:
+		return &websocketConn{Conn: conn, onClose: wt.end}
+	}
+	return conn
+}
+
+// handlerDone is called after the handler returns, the span is ended unless
+// it is supposed to be ended when the hijacked connection is closed.
+func (wt *websocketTracker) handlerDone() {
+	if wt.mode == WebsocketEndOnClose && wt.hijacked {
+		return
+	}
+	wt.end()
+}
+
+func (wt *websocketTracker) end() {
+	wt.once.Do(func() {
+		wt.beforeEnd()
+		wt.span.End(wt.endOpts()...)
+	})
+}
+
+// websocketConn notifies the tracker when the hijacked connection is closed.
+type websocketConn struct {
+	net.Conn
+	onClose func()
+}
+
+func (c *websocketConn) Close() error {
+	err := c.Conn.Close()
+	c.onClose()
+	return err
+}
@@ -0,0 +1,77 @@
+// Package sampling provides the samplers making use of the span attributes
+// provided by otelchi middleware at span start, e.g:
+//
+//	tracerProvider := sdktrace.NewTracerProvider(
+//		sdktrace.WithSampler(sdktrace.ParentBased(sampling.RouteSampler(
+//			map[string]float64{"/checkout": 1, "/assets/*": 0.01},
+//			sdktrace.TraceIDRatioBased(0.1),
+//		))),
+//	)
+//	router := chi.NewRouter()
+//	router.Use(otelchi.Middleware("my-server",
+//		otelchi.WithChiRoutes(router),
+//		otelchi.WithTracerProvider(tracerProvider),
+//	))
+package sampling
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+// RouteSampler returns the sampler sampling the server spans by the route
+// pattern they serve, e.g. `{"/checkout": 1, "/assets/*": 0.01}` samples all
+// the checkout requests & only 1% of the assets requests. The ratio is
+// applied the same way as `sdktrace.TraceIDRatioBased`, the spans whose
+// route has no rule (or is unknown) are sampled by the fallback sampler,
+// which defaults to `sdktrace.AlwaysSample` when nil.
+//
+// The route is read from the `http.route` attribute given at span start,
+// the middleware provides it only when it could resolve the route before
+// routing, so `otelchi.WithChiRoutes` must be set. Wrap the sampler with
+// `sdktrace.ParentBased` to follow the sampling decision of the remote
+// parent.
+func RouteSampler(rules map[string]float64, fallback sdktrace.Sampler) sdktrace.Sampler {
+	if fallback == nil {
+		fallback = sdktrace.AlwaysSample()
+	}
+	s := routeSampler{
+		rules:    make(map[string]sdktrace.Sampler, len(rules)),
+		fallback: fallback,
+	}
+	for route, ratio := range rules {
+		s.rules[route] = sdktrace.TraceIDRatioBased(ratio)
+	}
+	return s
+}
+
+type routeSampler struct {
+	rules    map[string]sdktrace.Sampler
+	fallback sdktrace.Sampler
+}
+
+func (s routeSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, attr := range p.Attributes {
+		if attr.Key != semconv.HTTPRouteKey {
+			continue
+		}
+		if sampler, ok := s.rules[attr.Value.AsString()]; ok {
+			return sampler.ShouldSample(p)
+		}
+		break
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+func (s routeSampler) Description() string {
+	rules := make([]string, 0, len(s.rules))
+	for route, sampler := range s.rules {
+		rules = append(rules, fmt.Sprintf("%s:%s", route, sampler.Description()))
+	}
+	sort.Strings(rules)
+	return fmt.Sprintf("RouteSampler{%s,fallback:%s}", strings.Join(rules, ","), s.fallback.Description())
+}
@@ -0,0 +1,75 @@
+package otelchi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithAbortedRequest(t *testing.T) {
+	errClientGone := errors.New("client gone")
+	testCases := []struct {
+		Name      string
+		Cancel    func(r *http.Request) *http.Request
+		ExpStatus codes.Code
+		ExpCause  string
+	}{
+		{
+			Name: "Client Disconnect",
+			Cancel: func(r *http.Request) *http.Request {
+				ctx, cancel := context.WithCancelCause(r.Context())
+				cancel(errClientGone)
+				return r.WithContext(ctx)
+			},
+			ExpStatus: codes.Unset,
+			ExpCause:  "client gone",
+		},
+		{
+			Name: "Timeout",
+			Cancel: func(r *http.Request) *http.Request {
+				ctx, cancel := context.WithDeadline(r.Context(), time.Now())
+				t.Cleanup(cancel)
+				return r.WithContext(ctx)
+			},
+			ExpStatus: codes.Error,
+			ExpCause:  context.DeadlineExceeded.Error(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// prepare router and span recorder
+			router, sr := newSDKTestRouter("foobar", true)
+			router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+				// the handler fails because its context is canceled
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+
+			// execute aborted request
+			r := testCase.Cancel(httptest.NewRequest("GET", "/user/123", nil))
+			executeRequests(router, []*http.Request{r})
+
+			// ensure the abortion is recorded
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, 1)
+			assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, testCase.ExpStatus,
+				attribute.Bool("http.request.aborted", true),
+				attribute.Int("http.status_code", http.StatusInternalServerError),
+			)
+			events := recordedSpans[0].Events()
+			require.Len(t, events, 1)
+			assert.Equal(t, otelchi.RequestAbortedEventName, events[0].Name)
+			assert.Contains(t, events[0].Attributes, attribute.String("http.request.abort_cause", testCase.ExpCause))
+		})
+	}
+}
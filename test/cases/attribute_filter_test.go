@@ -0,0 +1,52 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithAttributeFilter(t *testing.T) {
+	// prepare router and span recorder, the filter drops the user agent, the
+	// redirect location & the status code
+	dropped := map[attribute.Key]bool{
+		"user_agent.original":             true,
+		otelchi.RedirectLocationKey:       true,
+		attribute.Key("http.status_code"): true,
+	}
+	router, sr := newSDKTestRouter("foobar", true,
+		otelchi.WithAttributeFilter(func(kv attribute.KeyValue) bool {
+			return !dropped[kv.Key]
+		}),
+	)
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login?next=/user", http.StatusFound)
+	})
+
+	// execute request
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	r.Header.Set("User-Agent", "test-agent")
+	executeRequests(router, []*http.Request{r})
+
+	// ensure the filtered attributes are dropped from the span & its events
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	span := recordedSpans[0]
+	assertSpan(t, span, "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.route", "/user/{id}"),
+		attribute.String("http.method", "GET"),
+	)
+	for _, attr := range span.Attributes() {
+		assert.False(t, dropped[attr.Key], "unexpected attribute %s", attr.Key)
+	}
+	require.Len(t, span.Events(), 1)
+	assert.Equal(t, otelchi.RedirectEventName, span.Events()[0].Name)
+	assert.Empty(t, span.Events()[0].Attributes)
+}
@@ -0,0 +1,70 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithAttributeValueLengthLimit(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true,
+		otelchi.WithAttributeValueLengthLimit(16),
+		otelchi.WithCapturedRequestHeaders("X-Token"),
+	)
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute request with the oversized user agent & captured header
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	r.Header.Set("User-Agent", strings.Repeat("a", 1024))
+	r.Header.Set("X-Token", "short")
+	r.Header.Add("X-Token", strings.Repeat("é", 17))
+	executeRequests(router, []*http.Request{r})
+
+	// ensure the oversized values are truncated & the truncation is noted
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	span := recordedSpans[0]
+	assertSpan(t, span, "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.route", "/user/{id}"),
+		attribute.String("user_agent.original", strings.Repeat("a", 16)+"..."),
+		attribute.StringSlice("http.request.header.x-token", []string{"short", strings.Repeat("é", 16) + "..."}),
+	)
+	var truncated []string
+	for _, attr := range span.Attributes() {
+		if attr.Key == otelchi.TruncatedAttributesKey {
+			truncated = attr.Value.AsStringSlice()
+		}
+	}
+	assert.ElementsMatch(t, []string{"user_agent.original", "http.request.header.x-token"}, truncated)
+}
+
+func TestSDKIntegrationWithAttributeValueLengthLimitNotExceeded(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithAttributeValueLengthLimit(1024))
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute request
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	r.Header.Set("User-Agent", "test-agent")
+	executeRequests(router, []*http.Request{r})
+
+	// ensure nothing is truncated
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	span := recordedSpans[0]
+	assertSpan(t, span, "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("user_agent.original", "test-agent"),
+	)
+	for _, attr := range span.Attributes() {
+		assert.NotEqual(t, otelchi.TruncatedAttributesKey, attr.Key)
+	}
+}
@@ -0,0 +1,109 @@
+package otelchi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithStaticAttributes(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter(
+		"foobar",
+		true,
+		otelchi.WithStaticAttributes(attribute.String("service.component", "api-gateway")),
+		otelchi.WithStaticAttributes(attribute.String("deployment.tier", "edge")),
+	)
+	router.HandleFunc("/user/{id}", ok)
+	router.HandleFunc("/book/{title}", ok)
+
+	// execute requests
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/user/123", nil),
+		httptest.NewRequest("GET", "/book/foo", nil),
+	})
+
+	// ensure every span has the static attributes
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	for i, name := range []string{"/user/{id}", "/book/{title}"} {
+		assertSpan(t, recordedSpans[i], name, trace.SpanKindServer, codes.Unset,
+			attribute.String("service.component", "api-gateway"),
+			attribute.String("deployment.tier", "edge"),
+		)
+	}
+}
+
+func TestSDKIntegrationWithStaticAttributesOnMetrics(t *testing.T) {
+	// prepare router & metric reader enabling the metrics of the middleware
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	router, _ := newSDKTestRouter(
+		"foobar",
+		true,
+		otelchi.WithMeterProvider(provider),
+		otelchi.WithOverheadMetric(nil),
+		otelchi.WithRequestQueueTimeMetric(""),
+		otelchi.WithStaticAttributes(attribute.String("service.component", "api-gateway")),
+	)
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute request
+	req := httptest.NewRequest("GET", "/user/123", nil)
+	req.Header.Set(otelchi.DefaultRequestStartHeader, strconv.FormatInt(time.Now().Add(-time.Second).UnixMilli(), 10))
+	executeRequests(router, []*http.Request{req})
+
+	// ensure every metric has the static attributes
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	metrics := rm.ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 2)
+	for _, m := range metrics {
+		hist, ok := m.Data.(metricdata.Histogram[float64])
+		require.True(t, ok, m.Name)
+		require.Len(t, hist.DataPoints, 1, m.Name)
+		value, ok := hist.DataPoints[0].Attributes.Value("service.component")
+		require.True(t, ok, m.Name)
+		assert.Equal(t, "api-gateway", value.AsString(), m.Name)
+	}
+}
+
+func TestSDKIntegrationWithSpanAttributesFn(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithSpanAttributesFn(func(r *http.Request) []attribute.KeyValue {
+		return []attribute.KeyValue{attribute.String("tenant.id", r.Header.Get("X-Tenant-ID"))}
+	}))
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		// ensure the attribute is already available when the handler is executed
+		roSpan, ok := trace.SpanFromContext(r.Context()).(sdktrace.ReadOnlySpan)
+		require.True(t, ok)
+		require.Contains(t, roSpan.Attributes(), attribute.String("tenant.id", "acme"))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute request
+	req := httptest.NewRequest("GET", "/user/123", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	executeRequests(router, []*http.Request{req})
+
+	// ensure the span has the attribute
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("tenant.id", "acme"),
+	)
+}
@@ -0,0 +1,132 @@
+package otelchi_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	batchTraceparent1 = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	batchTraceparent2 = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+)
+
+func TestLinkFromCarrier(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true)
+	var linked, invalidLinked bool
+	router.HandleFunc("/batch", func(w http.ResponseWriter, r *http.Request) {
+		span := trace.SpanFromContext(r.Context())
+		linked = otelchi.LinkFromCarrier(span, propagation.MapCarrier{"traceparent": batchTraceparent1}, attribute.String("item.id", "1"))
+		invalidLinked = otelchi.LinkFromCarrier(span, propagation.MapCarrier{"traceparent": "invalid"})
+	})
+
+	executeRequests(router, []*http.Request{httptest.NewRequest("POST", "/batch", nil)})
+
+	// ensure only the valid trace context is linked
+	assert.True(t, linked)
+	assert.False(t, invalidLinked)
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	links := recordedSpans[0].Links()
+	require.Len(t, links, 1)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", links[0].SpanContext.TraceID().String())
+	assert.Equal(t, "b7ad6b7169203331", links[0].SpanContext.SpanID().String())
+	assert.Equal(t, []attribute.KeyValue{attribute.String("item.id", "1")}, links[0].Attributes)
+}
+
+func TestSDKIntegrationWithBatchLinksFromHeaders(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithBatchLinksFromHeaders("X-Event-Traceparent"))
+	router.HandleFunc("/webhook", ok)
+
+	req := httptest.NewRequest("POST", "/webhook", nil)
+	req.Header.Add("X-Event-Traceparent", batchTraceparent1+", invalid")
+	req.Header.Add("X-Event-Traceparent", batchTraceparent2)
+	executeRequests(router, []*http.Request{req})
+
+	// ensure the valid trace contexts are linked
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	links := recordedSpans[0].Links()
+	require.Len(t, links, 2)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", links[0].SpanContext.TraceID().String())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", links[1].SpanContext.TraceID().String())
+}
+
+func TestSDKIntegrationWithBatchLinksFromJSON(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		ContentType string
+		Body        string
+		MaxBytes    int
+		WantTraces  []string
+		WantIndexes []int
+	}{
+		{
+			Name:        "Array Of Items",
+			ContentType: "application/json",
+			Body:        `[{"meta":{"traceparent":"` + batchTraceparent1 + `"}},{"meta":{}},{"meta":{"traceparent":"` + batchTraceparent2 + `"}}]`,
+			MaxBytes:    1024,
+			WantTraces:  []string{"0af7651916cd43dd8448eb211c80319c", "4bf92f3577b34da6a3ce929d0e0e4736"},
+			WantIndexes: []int{0, 2},
+		},
+		{
+			Name:        "Single Item",
+			ContentType: "application/cloudevents+json; charset=utf-8",
+			Body:        `{"meta":{"traceparent":"` + batchTraceparent2 + `"}}`,
+			MaxBytes:    1024,
+			WantTraces:  []string{"4bf92f3577b34da6a3ce929d0e0e4736"},
+			WantIndexes: []int{0},
+		},
+		{
+			Name:        "Body Exceeding Limit",
+			ContentType: "application/json",
+			Body:        `[{"meta":{"traceparent":"` + batchTraceparent1 + `"}}]`,
+			MaxBytes:    16,
+		},
+		{
+			Name:        "Non JSON Body",
+			ContentType: "text/plain",
+			Body:        `{"meta":{"traceparent":"` + batchTraceparent1 + `"}}`,
+			MaxBytes:    1024,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// prepare router and span recorder, the handler ensures the body
+			// is restored
+			router, sr := newSDKTestRouter("foobar", true, otelchi.WithBatchLinksFromJSON("meta.traceparent", testCase.MaxBytes))
+			var gotBody string
+			router.HandleFunc("/ingest", func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				gotBody = string(body)
+			})
+
+			req := httptest.NewRequest("POST", "/ingest", strings.NewReader(testCase.Body))
+			req.Header.Set("Content-Type", testCase.ContentType)
+			executeRequests(router, []*http.Request{req})
+			assert.Equal(t, testCase.Body, gotBody)
+
+			// ensure the trace contexts of the items are linked
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, 1)
+			links := recordedSpans[0].Links()
+			require.Len(t, links, len(testCase.WantTraces))
+			for i, link := range links {
+				assert.Equal(t, testCase.WantTraces[i], link.SpanContext.TraceID().String())
+				assert.Equal(t, []attribute.KeyValue{otelchi.BatchItemIndexKey.Int(testCase.WantIndexes[i])}, link.Attributes)
+			}
+		})
+	}
+}
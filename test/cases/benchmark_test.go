@@ -0,0 +1,71 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// newBenchmarkRouter returns the router instrumented by the middleware with
+// the SDK tracer provider sampling every span without exporting it.
+func newBenchmarkRouter(withChiRoutes bool, opts ...otelchi.Option) *chi.Mux {
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	opts = append(opts, otelchi.WithTracerProvider(tracerProvider))
+
+	router := chi.NewRouter()
+	if withChiRoutes {
+		opts = append(opts, otelchi.WithChiRoutes(router))
+	}
+	router.Use(otelchi.Middleware("foobar", opts...))
+	router.Get("/", ok)
+	router.Get("/users/{id}", ok)
+	router.Get("/orgs/{org}/projects/{project}", ok)
+	router.Get("/orgs/{org}/projects/{project}/members/{member}", ok)
+	return router
+}
+
+func runBenchmark(b *testing.B, router http.Handler) {
+	r := httptest.NewRequest("GET", "/orgs/acme/projects/rocket/members/123", nil)
+	w := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		router.ServeHTTP(w, r)
+	}
+}
+
+func BenchmarkMiddleware(b *testing.B) {
+	runBenchmark(b, newBenchmarkRouter(false))
+}
+
+func BenchmarkMiddlewareWithChiRoutes(b *testing.B) {
+	runBenchmark(b, newBenchmarkRouter(true))
+}
+
+func BenchmarkMiddlewareWithChiRoutesAndRouteCache(b *testing.B) {
+	runBenchmark(b, newBenchmarkRouter(true, otelchi.WithRouteCache(otelchi.NewRouteCache(otelchi.DefaultRouteCacheSize))))
+}
+
+func BenchmarkMiddlewareWithLowAllocationMode(b *testing.B) {
+	runBenchmark(b, newBenchmarkRouter(false, otelchi.WithLowAllocationMode()))
+}
+
+func BenchmarkMiddlewareWithRequestMethodInSpanName(b *testing.B) {
+	runBenchmark(b, newBenchmarkRouter(false, otelchi.WithRequestMethodInSpanName(true)))
+}
+
+func BenchmarkMiddlewareWithRequestMethodInSpanNameAndLowAllocationMode(b *testing.B) {
+	runBenchmark(b, newBenchmarkRouter(false, otelchi.WithRequestMethodInSpanName(true), otelchi.WithLowAllocationMode()))
+}
+
+func BenchmarkMiddlewareWithTraceResponseHeaders(b *testing.B) {
+	runBenchmark(b, newBenchmarkRouter(false, otelchi.WithTraceResponseHeaders(otelchi.TraceHeaderConfig{
+		EmitTraceparent: true,
+		EmitTracestate:  true,
+	})))
+}
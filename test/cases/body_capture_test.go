@@ -0,0 +1,66 @@
+package otelchi_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestSDKIntegrationWithBodyCapture(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true,
+		otelchi.WithBodyCapture(16, "application/json", "text/*"),
+		otelchi.WithBodyRedactFn(func(contentType string, body []byte) []byte {
+			return bytes.ReplaceAll(body, []byte("secret"), []byte("******"))
+		}),
+	)
+	router.Post("/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		_, _ = w.Write(body)
+	})
+
+	// execute requests
+	newRequest := func(contentType, body string) *http.Request {
+		r := httptest.NewRequest("POST", "/echo", strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+		return r
+	}
+	executeRequests(router, []*http.Request{
+		newRequest("application/json; charset=utf-8", `{"key":"secret"}`),
+		newRequest("text/plain", "a long text exceeding the limit"),
+		newRequest("application/octet-stream", "binary"),
+	})
+
+	// ensure the matching bodies are recorded
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 3)
+	expEvents := [][]attribute.KeyValue{
+		{
+			attribute.String("http.body.content", `{"key":"******"}`),
+			attribute.Bool("http.body.truncated", false),
+		},
+		{
+			attribute.String("http.body.content", "a long text exce"),
+			attribute.Bool("http.body.truncated", true),
+		},
+	}
+	for i, expAttrs := range expEvents {
+		events := recordedSpans[i].Events()
+		require.Len(t, events, 2)
+		assert.Equal(t, "http.request.body", events[0].Name)
+		assert.Equal(t, expAttrs, events[0].Attributes)
+		assert.Equal(t, "http.response.body", events[1].Name)
+		assert.Equal(t, expAttrs, events[1].Attributes)
+	}
+	assert.Empty(t, recordedSpans[2].Events())
+}
@@ -0,0 +1,60 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithMaxRouteCardinality(t *testing.T) {
+	for _, withChiRoutes := range []bool{true, false} {
+		// prepare router and span recorder
+		router, sr := newSDKTestRouter("foobar", withChiRoutes, otelchi.WithMaxRouteCardinality(2, ""))
+		router.HandleFunc("/user/{id}", ok)
+		router.HandleFunc("/book/{title}", ok)
+		router.HandleFunc("/live", ok)
+
+		// execute requests
+		executeRequests(router, []*http.Request{
+			httptest.NewRequest("GET", "/user/123", nil),
+			httptest.NewRequest("GET", "/book/foo", nil),
+			httptest.NewRequest("GET", "/live", nil),
+			httptest.NewRequest("GET", "/user/456", nil),
+		})
+
+		// ensure the routes exceeding the limit are collapsed
+		recordedSpans := sr.Ended()
+		require.Len(t, recordedSpans, 4)
+		checkSpans(t, recordedSpans, []spanValueCheck{
+			{
+				Name:       "/user/{id}",
+				Kind:       trace.SpanKindServer,
+				Status:     codes.Unset,
+				Attributes: getSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}"),
+			},
+			{
+				Name:       "/book/{title}",
+				Kind:       trace.SpanKindServer,
+				Status:     codes.Unset,
+				Attributes: getSemanticAttributes("foobar", http.StatusOK, "GET", "/book/{title}"),
+			},
+			{
+				Name:       otelchi.DefaultRouteCardinalityFallback,
+				Kind:       trace.SpanKindServer,
+				Status:     codes.Unset,
+				Attributes: getSemanticAttributes("foobar", http.StatusOK, "GET", otelchi.DefaultRouteCardinalityFallback),
+			},
+			{
+				Name:       "/user/{id}",
+				Kind:       trace.SpanKindServer,
+				Status:     codes.Unset,
+				Attributes: getSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}"),
+			},
+		})
+	}
+}
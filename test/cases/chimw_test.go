@@ -0,0 +1,131 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/riandyrn/otelchi/chimw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithChimwTimeout(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true)
+	router.Use(chimw.Timeout(10 * time.Millisecond))
+	router.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute requests
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/slow", nil),
+		httptest.NewRequest("GET", "/user/123", nil),
+	})
+
+	// ensure only the timed out request is marked
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	assertSpan(t, recordedSpans[0], "/slow", trace.SpanKindServer, codes.Error,
+		attribute.Bool("http.server.timeout", true),
+		attribute.Int("http.status_code", http.StatusGatewayTimeout),
+	)
+	for _, attr := range recordedSpans[1].Attributes() {
+		assert.NotEqual(t, chimw.TimeoutKey, attr.Key)
+	}
+}
+
+func TestSDKIntegrationWithChimwThrottle(t *testing.T) {
+	// prepare router and span recorder, only one request is processed at a
+	// time while another one could wait in the queue
+	router, sr := newSDKTestRouter("foobar", true)
+	router.Use(chimw.ThrottleBacklog(1, 1, time.Second))
+	started := make(chan string, 3)
+	release := make(chan struct{})
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		started <- r.URL.Path
+		<-release
+	}
+	router.HandleFunc("/first", handler)
+	router.HandleFunc("/second", handler)
+	router.HandleFunc("/third", handler)
+
+	// execute the first request holding the capacity
+	var wg sync.WaitGroup
+	serve := func(path string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		}()
+	}
+	serve("/first")
+	<-started
+
+	// the second request is queued, while the third one is rejected since
+	// the queue is full
+	serve("/second")
+	require.Eventually(t, func() bool {
+		return len(sr.Started()) == 2
+	}, time.Second, time.Millisecond)
+	// give the second request time to reach the queue after its span is
+	// started
+	time.Sleep(10 * time.Millisecond)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/third", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	// release the requests
+	close(release)
+	wg.Wait()
+
+	// check the recorded spans
+	spans := map[string][]attribute.KeyValue{}
+	var queuedEvents []string
+	for _, span := range sr.Ended() {
+		spans[span.Name()] = span.Attributes()
+		for _, event := range span.Events() {
+			queuedEvents = append(queuedEvents, span.Name()+" "+event.Name)
+		}
+	}
+	assert.Equal(t, []string{"/second " + chimw.ThrottleQueuedEventName}, queuedEvents)
+	assert.Contains(t, spans["/third"], chimw.ThrottleRejectedKey.Bool(true))
+	assert.NotContains(t, spans["/first"], chimw.ThrottleRejectedKey.Bool(true))
+	assert.NotContains(t, spans["/second"], chimw.ThrottleRejectedKey.Bool(true))
+}
+
+func TestSDKIntegrationWithChimwCompress(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithCompressionAttributes())
+	router.Use(chimw.Compress(5, "text/plain"))
+	router.HandleFunc("/text", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("hello world"))
+	})
+
+	// execute requests with & without accepting the compression
+	r0 := httptest.NewRequest("GET", "/text", nil)
+	r0.Header.Set("Accept-Encoding", "gzip")
+	router.ServeHTTP(httptest.NewRecorder(), r0)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/text", nil))
+
+	// ensure the encoding is only recorded for the compressed response
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	assertSpan(t, recordedSpans[0], "/text", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.response.encoding", "gzip"),
+		attribute.Int64("http.response.body.uncompressed_size", 11),
+	)
+	for _, attr := range recordedSpans[1].Attributes() {
+		assert.NotEqual(t, chimw.ResponseEncodingKey, attr.Key)
+	}
+}
@@ -0,0 +1,306 @@
+package otelchi_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type proxyProtocolCtxKey struct{}
+
+func TestSDKIntegrationWithClientIP(t *testing.T) {
+	// define test cases
+	testCases := []struct {
+		Name       string
+		Config     otelchi.ClientIPConfig
+		PrepareReq func(r *http.Request) *http.Request
+		ExpAddress string
+	}{
+		{
+			Name:       "Remote Address",
+			Config:     otelchi.ClientIPConfig{},
+			PrepareReq: func(r *http.Request) *http.Request { return r },
+			ExpAddress: "192.0.2.1",
+		},
+		{
+			Name:   "X-Forwarded-For",
+			Config: otelchi.ClientIPConfig{},
+			PrepareReq: func(r *http.Request) *http.Request {
+				r.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+				return r
+			},
+			ExpAddress: "203.0.113.7",
+		},
+		{
+			Name:   "PROXY Protocol Context Key",
+			Config: otelchi.ClientIPConfig{ProxyProtocolContextKey: proxyProtocolCtxKey{}},
+			PrepareReq: func(r *http.Request) *http.Request {
+				r.Header.Set("X-Forwarded-For", "203.0.113.7")
+				addr := &net.TCPAddr{IP: net.ParseIP("198.51.100.3"), Port: 4567}
+				return r.WithContext(context.WithValue(r.Context(), proxyProtocolCtxKey{}, addr))
+			},
+			ExpAddress: "198.51.100.3",
+		},
+		{
+			Name:   "PROXY Protocol Header",
+			Config: otelchi.ClientIPConfig{ProxyProtocolHeader: "X-Proxy-Source"},
+			PrepareReq: func(r *http.Request) *http.Request {
+				r.Header.Set("X-Proxy-Source", "198.51.100.4:1234")
+				return r
+			},
+			ExpAddress: "198.51.100.4",
+		},
+		{
+			Name:   "Trusted Proxies Untrusted Peer",
+			Config: otelchi.ClientIPConfig{TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}},
+			PrepareReq: func(r *http.Request) *http.Request {
+				r.Header.Set("X-Forwarded-For", "203.0.113.7")
+				return r
+			},
+			ExpAddress: "192.0.2.1",
+		},
+		{
+			Name: "Trusted Proxies Untrusted Peer Proxy Protocol Header",
+			Config: otelchi.ClientIPConfig{
+				ProxyProtocolHeader: "X-Proxy-Source",
+				TrustedProxies:      []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			},
+			PrepareReq: func(r *http.Request) *http.Request {
+				r.Header.Set("X-Proxy-Source", "203.0.113.7:4711")
+				return r
+			},
+			ExpAddress: "192.0.2.1",
+		},
+		{
+			Name: "Trusted Proxies Trusted Peer Proxy Protocol Header",
+			Config: otelchi.ClientIPConfig{
+				ProxyProtocolHeader: "X-Proxy-Source",
+				TrustedProxies:      []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")},
+			},
+			PrepareReq: func(r *http.Request) *http.Request {
+				r.Header.Set("X-Proxy-Source", "203.0.113.7:4711")
+				return r
+			},
+			ExpAddress: "203.0.113.7",
+		},
+		{
+			Name: "Trusted Proxies X-Forwarded-For",
+			Config: otelchi.ClientIPConfig{TrustedProxies: []netip.Prefix{
+				netip.MustParsePrefix("192.0.2.0/24"),
+				netip.MustParsePrefix("10.0.0.0/8"),
+			}},
+			PrepareReq: func(r *http.Request) *http.Request {
+				r.Header.Set("X-Forwarded-For", "198.51.100.9, 203.0.113.7, 10.0.0.1")
+				return r
+			},
+			ExpAddress: "203.0.113.7",
+		},
+		{
+			Name: "Trusted Proxies Multiple X-Forwarded-For Lines",
+			Config: otelchi.ClientIPConfig{TrustedProxies: []netip.Prefix{
+				netip.MustParsePrefix("192.0.2.0/24"),
+				netip.MustParsePrefix("10.0.0.0/8"),
+			}},
+			PrepareReq: func(r *http.Request) *http.Request {
+				// the first line is spoofed by the client, the trusted proxy
+				// adds its own line instead of appending to it
+				r.Header.Add("X-Forwarded-For", "198.51.100.9")
+				r.Header.Add("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+				return r
+			},
+			ExpAddress: "203.0.113.7",
+		},
+		{
+			Name:   "Multiple X-Forwarded-For Lines",
+			Config: otelchi.ClientIPConfig{},
+			PrepareReq: func(r *http.Request) *http.Request {
+				r.Header.Add("X-Forwarded-For", "198.51.100.9, 10.0.0.2")
+				r.Header.Add("X-Forwarded-For", "10.0.0.1")
+				return r
+			},
+			ExpAddress: "198.51.100.9",
+		},
+		{
+			Name: "Trusted Proxies Forwarded",
+			Config: otelchi.ClientIPConfig{
+				Strategy: otelchi.ClientIPStrategyForwarded,
+				TrustedProxies: []netip.Prefix{
+					netip.MustParsePrefix("192.0.2.0/24"),
+					netip.MustParsePrefix("10.0.0.0/8"),
+				},
+			},
+			PrepareReq: func(r *http.Request) *http.Request {
+				r.Header.Set("Forwarded", `for=198.51.100.9, for="[2001:db8::1]:4711"`)
+				r.Header.Add("Forwarded", "for=10.0.0.2")
+				return r
+			},
+			ExpAddress: "2001:db8::1",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			router, sr := newSDKTestRouter("foobar", true, otelchi.WithClientIP(testCase.Config))
+			router.HandleFunc("/user/{id}", ok)
+
+			req := testCase.PrepareReq(httptest.NewRequest("GET", "/user/123", nil))
+			executeRequests(router, []*http.Request{req})
+
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, 1)
+			assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset,
+				attribute.String("client.address", testCase.ExpAddress),
+			)
+		})
+	}
+}
+
+func TestSDKIntegrationWithClientIPStrategy(t *testing.T) {
+	// define test cases
+	testCases := []struct {
+		Name       string
+		Strategy   otelchi.ClientIPStrategy
+		Headers    map[string]string
+		ExpAddress string
+	}{
+		{
+			Name:       "Forwarded IPv4",
+			Strategy:   otelchi.ClientIPStrategyForwarded,
+			Headers:    map[string]string{"Forwarded": "for=203.0.113.7;proto=https, for=10.0.0.1"},
+			ExpAddress: "203.0.113.7",
+		},
+		{
+			Name:       "Forwarded IPv6 With Port",
+			Strategy:   otelchi.ClientIPStrategyForwarded,
+			Headers:    map[string]string{"Forwarded": `For="[2001:db8:cafe::17]:4711"`},
+			ExpAddress: "2001:db8:cafe::17",
+		},
+		{
+			Name:       "Forwarded Obfuscated Falls Back To Remote Address",
+			Strategy:   otelchi.ClientIPStrategyForwarded,
+			Headers:    map[string]string{"Forwarded": "for=_hidden", "X-Forwarded-For": "203.0.113.7"},
+			ExpAddress: "192.0.2.1",
+		},
+		{
+			Name:       "Forwarded Then X-Forwarded-For",
+			Strategy:   otelchi.ClientIPStrategyForwardedThenXForwardedFor,
+			Headers:    map[string]string{"X-Forwarded-For": "203.0.113.8"},
+			ExpAddress: "203.0.113.8",
+		},
+		{
+			Name:       "Remote Address Only",
+			Strategy:   otelchi.ClientIPStrategyRemoteAddr,
+			Headers:    map[string]string{"Forwarded": "for=203.0.113.7", "X-Forwarded-For": "203.0.113.8"},
+			ExpAddress: "192.0.2.1",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			router, sr := newSDKTestRouter("foobar", true, otelchi.WithClientIP(otelchi.ClientIPConfig{
+				Strategy: testCase.Strategy,
+			}))
+			router.HandleFunc("/user/{id}", ok)
+
+			req := httptest.NewRequest("GET", "/user/123", nil)
+			for key, value := range testCase.Headers {
+				req.Header.Set(key, value)
+			}
+			executeRequests(router, []*http.Request{req})
+
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, 1)
+			assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset,
+				attribute.String("client.address", testCase.ExpAddress),
+			)
+		})
+	}
+}
+
+func TestSDKIntegrationWithClientIPForwardedOrigin(t *testing.T) {
+	// define test cases
+	testCases := []struct {
+		Name       string
+		ServerName string
+		Config     otelchi.ClientIPConfig
+		ExpAttrs   []attribute.KeyValue
+	}{
+		{
+			Name:   "Forwarded Proto & Host",
+			Config: otelchi.ClientIPConfig{Strategy: otelchi.ClientIPStrategyForwarded},
+			ExpAttrs: []attribute.KeyValue{
+				attribute.String("url.scheme", "https"),
+				attribute.String("server.address", "api.example.org"),
+				attribute.Int("server.port", 8443),
+			},
+		},
+		{
+			Name:       "Server Name Takes Precedence Over Forwarded Host",
+			ServerName: "foobar",
+			Config:     otelchi.ClientIPConfig{Strategy: otelchi.ClientIPStrategyForwardedThenXForwardedFor},
+			ExpAttrs: []attribute.KeyValue{
+				attribute.String("url.scheme", "https"),
+				attribute.String("server.address", "foobar"),
+				attribute.Int("server.port", 8443),
+			},
+		},
+		{
+			// the element added for the internal proxy is skipped
+			Name: "Trusted Proxies Forwarded Proto & Host",
+			Config: otelchi.ClientIPConfig{
+				Strategy:       otelchi.ClientIPStrategyForwarded,
+				TrustedProxies: []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")},
+			},
+			ExpAttrs: []attribute.KeyValue{
+				attribute.String("url.scheme", "https"),
+				attribute.String("server.address", "api.example.org"),
+				attribute.Int("server.port", 8443),
+			},
+		},
+		{
+			Name: "Trusted Proxies Untrusted Peer",
+			Config: otelchi.ClientIPConfig{
+				Strategy:       otelchi.ClientIPStrategyForwarded,
+				TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			},
+			ExpAttrs: []attribute.KeyValue{
+				attribute.String("url.scheme", "http"),
+				attribute.String("server.address", "example.com"),
+			},
+		},
+		{
+			Name:   "X-Forwarded-For Strategy Ignores Forwarded",
+			Config: otelchi.ClientIPConfig{Strategy: otelchi.ClientIPStrategyXForwardedFor},
+			ExpAttrs: []attribute.KeyValue{
+				attribute.String("url.scheme", "http"),
+				attribute.String("server.address", "example.com"),
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			router, sr := newSDKTestRouter(testCase.ServerName, true,
+				otelchi.WithSemconvVersion(otelchi.SemconvNew),
+				otelchi.WithClientIP(testCase.Config),
+			)
+			router.HandleFunc("/user/{id}", ok)
+
+			req := httptest.NewRequest("GET", "/user/123", nil)
+			// the first element is added by the edge proxy, the second one
+			// by the internal proxy connected to the server
+			req.Header.Set("Forwarded", `for=203.0.113.7;proto=https;host="api.example.org:8443", for=192.0.2.9;proto=http;host=internal.example.org`)
+			executeRequests(router, []*http.Request{req})
+
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, 1)
+			assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset, testCase.ExpAttrs...)
+		})
+	}
+}
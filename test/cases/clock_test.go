@@ -0,0 +1,74 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock which only moves when it is advanced explicitly.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestSDKIntegrationWithClock(t *testing.T) {
+	// prepare router and span recorder
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithClock(clock))
+	router.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		// simulate slow request without real sleep
+		clock.Advance(3 * time.Second)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/slow", nil)})
+
+	// ensure the span timestamps come from the clock
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), recordedSpans[0].StartTime())
+	assert.Equal(t, 3*time.Second, recordedSpans[0].EndTime().Sub(recordedSpans[0].StartTime()))
+}
+
+func TestSDKIntegrationWithTimeNow(t *testing.T) {
+	// prepare router and span recorder, the current time is simulated by
+	// the handler
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithTimeNow(func() time.Time {
+		return now
+	}))
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		now = now.Add(2 * time.Second)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+	// ensure the span timestamps come from the function
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assert.Equal(t, start, recordedSpans[0].StartTime())
+	assert.Equal(t, start.Add(2*time.Second), recordedSpans[0].EndTime())
+}
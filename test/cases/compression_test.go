@@ -0,0 +1,44 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithCompressionAttributes(t *testing.T) {
+	// prepare router and span recorder
+	body := strings.Repeat("hello world ", 100)
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithCompressionAttributes())
+	router.Use(otelchi.WrapCompress(middleware.Compress(5)))
+	router.HandleFunc("/text", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(body))
+	})
+
+	// execute request
+	r := httptest.NewRequest("GET", "/text", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	// ensure the compression attributes are recorded
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/text", trace.SpanKindServer, codes.Unset,
+		attribute.StringSlice("http.request.header.accept-encoding", []string{"gzip"}),
+		attribute.StringSlice("http.response.header.content-encoding", []string{"gzip"}),
+		attribute.Int("http.response.body.size", w.Body.Len()),
+		attribute.Int64("http.response.body.uncompressed_size", int64(len(body))),
+	)
+	assert.Less(t, w.Body.Len(), len(body))
+}
@@ -0,0 +1,78 @@
+package otelchi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestDebugHandler(t *testing.T) {
+	// prepare router with debug stats
+	router, _ := newSDKTestRouter("debug-server", true,
+		otelchi.WithDebugStats(),
+		otelchi.WithPropagators(propagation.TraceContext{}),
+		otelchi.WithRouteCache(otelchi.NewRouteCache(otelchi.DefaultRouteCacheSize)),
+		otelchi.WithHealthEndpointsFiltered(),
+		otelchi.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/internal"
+		}),
+	)
+	router.HandleFunc("/user/{id}", ok)
+	router.HandleFunc("/healthz", ok)
+	router.HandleFunc("/internal", ok)
+
+	// execute requests
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/user/123", nil),
+		httptest.NewRequest("GET", "/user/456", nil),
+		httptest.NewRequest("GET", "/healthz", nil),
+		httptest.NewRequest("GET", "/internal", nil),
+	})
+
+	// fetch the debug report
+	w := httptest.NewRecorder()
+	otelchi.DebugHandler().ServeHTTP(w, httptest.NewRequest("GET", "/debug/otelchi", nil))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var report otelchi.DebugReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+
+	var info *otelchi.MiddlewareDebugInfo
+	for i := range report.Middlewares {
+		if report.Middlewares[i].ServerName == "debug-server" {
+			info = &report.Middlewares[i]
+		}
+	}
+	require.NotNil(t, info, "middleware is not registered")
+
+	// check the reported state
+	assert.Equal(t, otelchi.Version(), info.Version)
+	assert.Equal(t, "old", info.SemconvMode)
+	assert.Subset(t, info.ActiveOptions, []string{
+		"WithTracerProvider",
+		"WithChiRoutes",
+		"WithFilter",
+		"WithHealthEndpointsFiltered",
+		"WithRouteCache",
+	})
+	assert.NotContains(t, info.ActiveOptions, "WithDebugStats")
+	assert.Equal(t, []string{"traceparent", "tracestate"}, info.PropagatorFields)
+	assert.Equal(t, []otelchi.RouteCacheInfo{
+		{Method: "GET", Path: "/healthz", Route: "/healthz", Matched: true},
+		{Method: "GET", Path: "/user/123", Route: "/user/{id}", Matched: true},
+		{Method: "GET", Path: "/user/456", Route: "/user/{id}", Matched: true},
+	}, info.RouteCache)
+	assert.Equal(t, map[string]int64{
+		"filter":       1,
+		"route_filter": 0,
+		"route_config": 0,
+		"health_check": 1,
+	}, info.FilterHits)
+	assert.Equal(t, map[string]int64{"/user/{id}": 2}, info.SpansPerRoute)
+}
@@ -0,0 +1,71 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationMarkDraining(t *testing.T) {
+	defer otelchi.UnmarkDraining()
+
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true)
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute request before and after the server is marked as draining
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+	otelchi.MarkDraining()
+	require.True(t, otelchi.IsDraining())
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/456", nil)})
+
+	// ensure only the span served during draining has the attribute
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	for _, attr := range recordedSpans[0].Attributes() {
+		assert.NotEqual(t, otelchi.ServerDrainingKey, attr.Key)
+	}
+	assertSpan(t, recordedSpans[1], "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.Bool("server.draining", true),
+	)
+}
+
+func TestSDKIntegrationDrainStatus(t *testing.T) {
+	defer otelchi.UnmarkDraining()
+
+	// prepare router and span recorder, the server is marked as draining
+	// while the first request is in flight
+	router, sr := newSDKTestRouter("foobar", true)
+	var inFlightStatus otelchi.DrainSnapshot
+	router.HandleFunc("/deploy", func(w http.ResponseWriter, r *http.Request) {
+		otelchi.MarkDraining()
+		inFlightStatus = otelchi.DrainStatus()
+	})
+	router.HandleFunc("/user/{id}", ok)
+
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/deploy", nil),
+		httptest.NewRequest("GET", "/user/123", nil),
+	})
+
+	// ensure the snapshot reports the request in flight when the server is
+	// marked as draining
+	assert.Equal(t, otelchi.DrainSnapshot{Draining: true, InFlight: 1, InFlightAtMark: 1}, inFlightStatus)
+	assert.Equal(t, otelchi.DrainSnapshot{Draining: true, InFlight: 0, InFlightAtMark: 1}, otelchi.DrainStatus())
+
+	// ensure the request served during draining is annotated with the
+	// number of requests in flight
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	assertSpan(t, recordedSpans[1], "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.Bool("server.draining", true),
+		attribute.Int64("server.draining.requests_inflight", 1),
+	)
+}
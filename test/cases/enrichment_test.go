@@ -0,0 +1,60 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithSpanEnrichment(t *testing.T) {
+	// prepare router, the handler runs inside its own span
+	router, sr := newSDKTestRouter(
+		"foobar",
+		true,
+		otelchi.WithWrappedHandlerSpan(true),
+		otelchi.WithPostRouteEnrichment(func(r *http.Request, span trace.Span) {
+			info, _ := otelchi.ResponseInfoFromContext(r.Context())
+			span.SetAttributes(
+				attribute.String("account.id", chi.URLParam(r, "account")),
+				attribute.Int64("response.bytes", info.BytesWritten),
+			)
+		}),
+	)
+	// authentication middleware installed after the tracing middleware
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			otelchi.EnrichSpan(r.Context(), attribute.String("enduser.id", "alice"))
+			next.ServeHTTP(w, r)
+		})
+	})
+	router.With(otelchi.WrapHandler).HandleFunc("/accounts/{account}", func(w http.ResponseWriter, r *http.Request) {
+		otelchi.EnrichSpan(r.Context(), attribute.StringSlice("enduser.scope", []string{"read"}))
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/accounts/42", nil)})
+
+	// ensure the server span is enriched, not the handler span
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	serverSpan, handlerSpan := recordedSpans[1], recordedSpans[0]
+	require.Equal(t, trace.SpanKindServer, serverSpan.SpanKind())
+	assertSpan(t, serverSpan, "/accounts/{account}", trace.SpanKindServer, codes.Unset,
+		attribute.String("enduser.id", "alice"),
+		attribute.StringSlice("enduser.scope", []string{"read"}),
+		attribute.String("account.id", "42"),
+		attribute.Int64("response.bytes", 2),
+	)
+	for _, attr := range handlerSpan.Attributes() {
+		assert.NotEqual(t, attribute.Key("enduser.id"), attr.Key)
+	}
+}
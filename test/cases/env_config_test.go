@@ -0,0 +1,64 @@
+package otelchi_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithEnvConfig(t *testing.T) {
+	// set the environment variables, the invalid one is ignored
+	t.Setenv("OTELCHI_FILTER_PATHS", "^/internal/, ^/healthz$")
+	t.Setenv("OTELCHI_TRACE_RESPONSE_HEADERS", "true")
+	t.Setenv("OTELCHI_CAPTURE_HEADERS", "X-Tenant-ID")
+	t.Setenv("OTELCHI_REQUEST_METHOD_IN_SPAN_NAME", "yes")
+
+	var handledErrs []error
+	defer func(h otel.ErrorHandler) { otel.SetErrorHandler(h) }(otel.GetErrorHandler())
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		handledErrs = append(handledErrs, err)
+	}))
+
+	// prepare router and span recorder, the explicit options complement the
+	// environment variables
+	router, sr := newSDKTestRouter("foobar", true,
+		otelchi.WithCapturedRequestHeaders("X-Region"),
+		otelchi.WithTraceResponseHeaders(otelchi.TraceHeaderConfig{TraceIDHeader: "X-Custom-Trace-ID"}),
+	)
+	router.HandleFunc("/user/{id}", ok)
+	router.HandleFunc("/internal/cache", ok)
+	router.HandleFunc("/healthz", ok)
+
+	// execute requests
+	r0 := httptest.NewRequest("GET", "/user/123", nil)
+	r0.Header.Set("X-Tenant-ID", "acme")
+	r0.Header.Set("X-Region", "eu")
+	w0 := httptest.NewRecorder()
+	router.ServeHTTP(w0, r0)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/internal/cache", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+
+	// ensure the filtered requests are not traced
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.route", "/user/{id}"),
+		attribute.StringSlice("http.request.header.x-tenant-id", []string{"acme"}),
+		attribute.StringSlice("http.request.header.x-region", []string{"eu"}),
+	)
+
+	// ensure the explicit option overrides the environment variable
+	assert.Equal(t, recordedSpans[0].SpanContext().TraceID().String(), w0.Header().Get("X-Custom-Trace-ID"))
+	assert.Empty(t, w0.Header().Get(otelchi.DefaultTraceIDResponseHeaderKey))
+
+	// ensure the invalid environment variable is reported
+	require.Len(t, handledErrs, 1)
+	assert.ErrorContains(t, handledErrs[0], "OTELCHI_REQUEST_METHOD_IN_SPAN_NAME")
+}
@@ -0,0 +1,129 @@
+package otelchi_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithGraphQLSupport(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		WithChiRoutes bool
+		Request       func() *http.Request
+		WantSpanName  string
+		WantAttrs     []attribute.KeyValue
+	}{
+		{
+			Name:          "Named Query",
+			WithChiRoutes: true,
+			Request: func() *http.Request {
+				req := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query":"# get user\nquery GetUser($id: ID!) { user(id: $id) { name } }","variables":{"id":"1"}}`))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			WantSpanName: "POST /graphql GetUser",
+			WantAttrs: []attribute.KeyValue{
+				attribute.String("graphql.operation.name", "GetUser"),
+				attribute.String("graphql.operation.type", "query"),
+			},
+		},
+		{
+			Name:          "Selected Operation",
+			WithChiRoutes: false,
+			Request: func() *http.Request {
+				body := `{"query":"fragment F on User { name } query GetUser { user { ...F } } mutation UpdateUser(\"x\": String) { update { id } }","operationName":"UpdateUser"}`
+				req := httptest.NewRequest("POST", "/graphql", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			WantSpanName: "POST /graphql UpdateUser",
+			WantAttrs: []attribute.KeyValue{
+				attribute.String("graphql.operation.name", "UpdateUser"),
+				attribute.String("graphql.operation.type", "mutation"),
+			},
+		},
+		{
+			Name:          "Anonymous Query From Query Params",
+			WithChiRoutes: true,
+			Request: func() *http.Request {
+				return httptest.NewRequest("GET", "/graphql?query="+url.QueryEscape(`{ viewer { login } }`), nil)
+			},
+			WantSpanName: "GET /graphql",
+			WantAttrs: []attribute.KeyValue{
+				attribute.String("graphql.operation.type", "query"),
+			},
+		},
+		{
+			Name:          "GraphQL Content Type",
+			WithChiRoutes: true,
+			Request: func() *http.Request {
+				req := httptest.NewRequest("POST", "/graphql", strings.NewReader(`subscription OnMessage { message { text } }`))
+				req.Header.Set("Content-Type", "application/graphql")
+				return req
+			},
+			WantSpanName: "POST /graphql OnMessage",
+			WantAttrs: []attribute.KeyValue{
+				attribute.String("graphql.operation.name", "OnMessage"),
+				attribute.String("graphql.operation.type", "subscription"),
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// prepare router and span recorder, the handler ensures the body
+			// is restored
+			router, sr := newSDKTestRouter(
+				"foobar",
+				testCase.WithChiRoutes,
+				otelchi.WithGraphQLSupport("/graphql"),
+				otelchi.WithRequestMethodInSpanName(true),
+			)
+			req := testCase.Request()
+			var wantBody []byte
+			if req.Body != nil {
+				wantBody, _ = io.ReadAll(req.Body)
+				req.Body = io.NopCloser(strings.NewReader(string(wantBody)))
+			}
+			var gotBody []byte
+			router.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+				gotBody, _ = io.ReadAll(r.Body)
+			})
+
+			executeRequests(router, []*http.Request{req})
+			assert.Equal(t, string(wantBody), string(gotBody))
+
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, 1)
+			assertSpan(t, recordedSpans[0], testCase.WantSpanName, trace.SpanKindServer, codes.Unset, testCase.WantAttrs...)
+		})
+	}
+}
+
+func TestSDKIntegrationWithGraphQLSupportOtherPath(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithGraphQLSupport("/graphql"))
+	router.HandleFunc("/user", ok)
+
+	req := httptest.NewRequest("POST", "/user", strings.NewReader(`{"query":"query GetUser { user { name } }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	executeRequests(router, []*http.Request{req})
+
+	// ensure the request to other path is not treated as GraphQL request
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assert.Equal(t, "/user", recordedSpans[0].Name())
+	for _, attr := range recordedSpans[0].Attributes() {
+		assert.NotEqual(t, otelchi.GraphQLOperationTypeKey, attr.Key)
+	}
+}
@@ -0,0 +1,130 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithWrappedHandlerSpan(t *testing.T) {
+	for _, withChiRoutes := range []bool{true, false} {
+		// prepare router and span recorder, the middleware installed after
+		// the tracing middleware records when it hands over to the handler
+		var handedOver []time.Time
+		router, sr := newSDKTestRouter("foobar", withChiRoutes, otelchi.WithWrappedHandlerSpan(true))
+		router.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(time.Millisecond)
+				handedOver = append(handedOver, time.Now())
+				next.ServeHTTP(w, r)
+			})
+		})
+		router.With(otelchi.WrapHandler).HandleFunc("/user/{id}", getUser)
+		router.Group(func(r chi.Router) {
+			r.Use(otelchi.WrapHandler)
+			r.Handle("/health", healthHandler{})
+		})
+		router.HandleFunc("/book/{title}", ok)
+
+		// execute requests
+		executeRequests(router, []*http.Request{
+			httptest.NewRequest("GET", "/user/123", nil),
+			httptest.NewRequest("GET", "/health", nil),
+			httptest.NewRequest("GET", "/book/foo", nil),
+		})
+
+		// ensure the handler spans are the children of the server spans &
+		// exclude the middlewares
+		recordedSpans := sr.Ended()
+		require.Len(t, recordedSpans, 5)
+		for i, serverSpanName := range []string{"/user/{id}", "/health"} {
+			handlerSpan, serverSpan := recordedSpans[i*2], recordedSpans[i*2+1]
+			assertSpan(t, serverSpan, serverSpanName, trace.SpanKindServer, codes.Unset)
+			assert.Equal(t, trace.SpanKindInternal, handlerSpan.SpanKind())
+			assert.Equal(t, serverSpan.SpanContext().SpanID(), handlerSpan.Parent().SpanID())
+			assert.False(t, handlerSpan.StartTime().Before(handedOver[i]))
+		}
+		assert.True(t, strings.HasSuffix(recordedSpans[0].Name(), ".getUser"), recordedSpans[0].Name())
+		assert.Equal(t, "otelchi_test.healthHandler", recordedSpans[2].Name())
+
+		// ensure the handler which is not wrapped has no handler span
+		assertSpan(t, recordedSpans[4], "/book/{title}", trace.SpanKindServer, codes.Unset)
+	}
+}
+
+func TestWrapHandlerWithoutHandlerSpan(t *testing.T) {
+	// prepare router without the handler span option
+	router, sr := newSDKTestRouter("foobar", true)
+	router.With(otelchi.WrapHandler).HandleFunc("/user/{id}", getUser)
+
+	// execute request
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/user/123", nil))
+
+	// ensure the handler is executed without the handler span
+	assert.Equal(t, http.StatusOK, w.Code)
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset)
+}
+
+func TestWrappedHandlerSpanWithoutWrappedHandler(t *testing.T) {
+	// the handlers wrapped by WrapHandler are counted per process, so the
+	// test is run in a fresh process where no other test wraps a handler
+	if os.Getenv("TEST_UNWRAPPED_HANDLER_SUBPROCESS") != "1" {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestWrappedHandlerSpanWithoutWrappedHandler$")
+		cmd.Env = append(os.Environ(), "TEST_UNWRAPPED_HANDLER_SUBPROCESS=1")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return
+	}
+
+	// capture the errors reported to the global error handler
+	var errs []error
+	defer func(h otel.ErrorHandler) { otel.SetErrorHandler(h) }(otel.GetErrorHandler())
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	// prepare router enabling the handler span without wrapping any handler
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithWrappedHandlerSpan(true))
+	router.HandleFunc("/user/{id}", getUser)
+
+	// execute requests
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/user/123", nil),
+		httptest.NewRequest("GET", "/user/456", nil),
+	})
+
+	// ensure the misconfiguration is reported once & no handler span is
+	// created
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "no route handler is wrapped by WrapHandler")
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	for _, span := range recordedSpans {
+		assertSpan(t, span, "/user/{id}", trace.SpanKindServer, codes.Unset)
+	}
+}
+
+func getUser(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+type healthHandler struct{}
+
+func (healthHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,82 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewHandlerWithChiRouter(t *testing.T) {
+	// prepare span recorder
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	provider.RegisterSpanProcessor(sr)
+
+	// prepare handler, the route pattern is resolved before the span is started
+	var spanNameInHandler string
+	router := chi.NewRouter()
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		spanNameInHandler = sr.Started()[0].Name()
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := otelchi.NewHandler(router, "foobar", otelchi.WithTracerProvider(provider))
+
+	// execute requests
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/user/123", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/unknown", nil))
+
+	// check the recorded spans
+	assert.Equal(t, "/user/{id}", spanNameInHandler)
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.route", "/user/{id}"),
+		attribute.Int("http.status_code", http.StatusOK),
+	)
+	assertSpan(t, recordedSpans[1], "/", trace.SpanKindServer, codes.Unset,
+		attribute.Int("http.status_code", http.StatusNotFound),
+	)
+}
+
+func TestNewHandlerWithMountedChiRouter(t *testing.T) {
+	// prepare span recorder
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	provider.RegisterSpanProcessor(sr)
+
+	// prepare handler, the chi router is mounted inside a non-chi mux
+	router := chi.NewRouter()
+	router.HandleFunc("/api/user/{id}", ok)
+	mux := http.NewServeMux()
+	mux.Handle("/api/", router)
+	mux.HandleFunc("/healthz", ok)
+	handler := otelchi.NewHandler(mux, "foobar",
+		otelchi.WithTracerProvider(provider),
+		otelchi.WithRequestMethodInSpanName(true),
+	)
+
+	// execute requests
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/user/123", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+
+	// check the recorded spans
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	assertSpan(t, recordedSpans[0], "GET /api/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.route", "/api/user/{id}"),
+		attribute.Int("http.status_code", http.StatusOK),
+	)
+	assertSpan(t, recordedSpans[1], "GET /", trace.SpanKindServer, codes.Unset,
+		attribute.Int("http.status_code", http.StatusOK),
+	)
+}
@@ -0,0 +1,48 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithCapturedHeaders(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter(
+		"foobar",
+		true,
+		otelchi.WithCapturedRequestHeaders("X-Request-ID", "Authorization", "X-Missing"),
+		otelchi.WithCapturedResponseHeaders("Content-Type", "Set-Cookie"),
+	)
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute request
+	req := httptest.NewRequest("GET", "/user/123", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	req.Header.Set("Authorization", "Bearer secret")
+	executeRequests(router, []*http.Request{req})
+
+	// ensure the headers are captured & sensitive values are redacted
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.StringSlice("http.request.header.x-request-id", []string{"req-1"}),
+		attribute.StringSlice("http.request.header.authorization", []string{otelchi.RedactedHeaderValue}),
+		attribute.StringSlice("http.response.header.content-type", []string{"application/json"}),
+		attribute.StringSlice("http.response.header.set-cookie", []string{otelchi.RedactedHeaderValue}),
+	)
+	for _, attr := range recordedSpans[0].Attributes() {
+		assert.NotEqual(t, attribute.Key("http.request.header.x-missing"), attr.Key)
+	}
+}
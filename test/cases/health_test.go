@@ -0,0 +1,82 @@
+package otelchi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithHealthEndpointsFiltered(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Paths     []string
+		WantSpans []string
+	}{
+		{
+			Name:      "Default Paths",
+			Paths:     nil,
+			WantSpans: []string{"/status", "/user/{id}"},
+		},
+		{
+			Name:      "Custom Paths",
+			Paths:     []string{"/status"},
+			WantSpans: []string{"/healthz", "/ping", "/user/{id}"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// prepare router and span recorder
+			router, sr := newSDKTestRouter("foobar", true, otelchi.WithHealthEndpointsFiltered(testCase.Paths...))
+			router.HandleFunc("/healthz", ok)
+			router.HandleFunc("/ping", ok)
+			router.HandleFunc("/status", ok)
+			router.HandleFunc("/user/{id}", ok)
+
+			// execute requests
+			executeRequests(router, []*http.Request{
+				httptest.NewRequest("GET", "/healthz", nil),
+				httptest.NewRequest("GET", "/ping", nil),
+				httptest.NewRequest("GET", "/status", nil),
+				httptest.NewRequest("GET", "/user/123", nil),
+			})
+
+			// ensure the health-check requests are not traced
+			var spanNames []string
+			for _, span := range sr.Ended() {
+				spanNames = append(spanNames, span.Name())
+			}
+			require.Equal(t, testCase.WantSpans, spanNames)
+		})
+	}
+}
+
+func TestSDKIntegrationWithHealthEndpointsForcedTracing(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true,
+		otelchi.WithHealthEndpointsFiltered(),
+		otelchi.WithPropagators(propagation.TraceContext{}),
+	)
+	router.HandleFunc("/healthz", ok)
+
+	// execute request carrying the sampled trace context
+	r := httptest.NewRequest("GET", "/healthz", nil)
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), sc)
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(r.Header))
+	executeRequests(router, []*http.Request{r})
+
+	// ensure the forced span is marked as health-check
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/healthz", trace.SpanKindServer, codes.Unset,
+		attribute.Bool("http.route.health_check", true),
+	)
+}
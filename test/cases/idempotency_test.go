@@ -0,0 +1,93 @@
+package otelchi_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithIdempotencyKeyCapture(t *testing.T) {
+	// prepare router, the retries within a minute are linked
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	router, sr := newSDKTestRouter(
+		"foobar",
+		true,
+		otelchi.WithClock(clock),
+		otelchi.WithIdempotencyKeyCapture(""),
+		otelchi.WithIdempotencyKeyRetryLinks(time.Minute),
+	)
+	router.HandleFunc("/payments", ok)
+
+	// execute requests
+	newRequest := func(key string) *http.Request {
+		req := httptest.NewRequest("POST", "/payments", nil)
+		if len(key) > 0 {
+			req.Header.Set(otelchi.DefaultIdempotencyKeyHeader, key)
+		}
+		return req
+	}
+	executeRequests(router, []*http.Request{newRequest("key-1")})
+	clock.Advance(10 * time.Second)
+	executeRequests(router, []*http.Request{newRequest("key-1"), newRequest("")})
+	clock.Advance(2 * time.Minute)
+	executeRequests(router, []*http.Request{newRequest("key-1")})
+
+	// ensure the hashed key is recorded & only the retry within the window
+	// is linked to the previous request
+	sum := sha256.Sum256([]byte("key-1"))
+	hashed := hex.EncodeToString(sum[:16])
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 4)
+
+	assertSpan(t, recordedSpans[0], "/payments", trace.SpanKindServer, codes.Unset,
+		otelchi.IdempotencyKeyKey.String(hashed),
+	)
+	assert.Empty(t, recordedSpans[0].Links())
+
+	assertSpan(t, recordedSpans[1], "/payments", trace.SpanKindServer, codes.Unset,
+		otelchi.IdempotencyKeyKey.String(hashed),
+		otelchi.IdempotencyRetryKey.Bool(true),
+	)
+	require.Len(t, recordedSpans[1].Links(), 1)
+	assert.Equal(t, recordedSpans[0].SpanContext(), recordedSpans[1].Links()[0].SpanContext)
+
+	for _, attr := range recordedSpans[2].Attributes() {
+		assert.NotEqual(t, otelchi.IdempotencyKeyKey, attr.Key)
+	}
+
+	assert.Empty(t, recordedSpans[3].Links())
+	assert.NotContains(t, recordedSpans[3].Attributes(), otelchi.IdempotencyRetryKey.Bool(true))
+}
+
+func TestSDKIntegrationWithIdempotencyKeyUnhashed(t *testing.T) {
+	// prepare router
+	router, sr := newSDKTestRouter(
+		"foobar",
+		true,
+		otelchi.WithIdempotencyKeyCapture("X-Request-Key"),
+		otelchi.WithIdempotencyKeyUnhashed(),
+	)
+	router.HandleFunc("/payments", ok)
+
+	// execute request
+	req := httptest.NewRequest("POST", "/payments", nil)
+	req.Header.Set("X-Request-Key", "key-1")
+	executeRequests(router, []*http.Request{req})
+
+	// ensure the key is recorded as is
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/payments", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.request.idempotency_key", "key-1"),
+	)
+}
@@ -0,0 +1,86 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithLatencyForcedSampling(t *testing.T) {
+	// prepare router and span recorder, the spans are never sampled by the
+	// head sampling
+	sr := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.NeverSample())),
+		sdktrace.WithSpanProcessor(sr),
+	)
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware("foobar",
+		otelchi.WithTracerProvider(tracerProvider),
+		otelchi.WithClock(clock),
+		otelchi.WithLatencyForcedSampling(time.Second),
+	))
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("slow") {
+			clock.Advance(2 * time.Second)
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	// execute requests
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/user/123", nil),
+		httptest.NewRequest("GET", "/user/456?slow", nil),
+	})
+
+	// ensure only the slow request is re-emitted
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	span := recordedSpans[0]
+	assertSpan(t, span, "/user/{id}", trace.SpanKindServer, codes.Error,
+		attribute.String("net.host.name", "foobar"),
+		attribute.String("http.route", "/user/{id}"),
+		attribute.Int("http.status_code", http.StatusServiceUnavailable),
+		attribute.Bool("otelchi.sampling.forced", true),
+	)
+	assert.Equal(t, 2*time.Second, span.EndTime().Sub(span.StartTime()))
+	assert.True(t, span.SpanContext().IsSampled())
+	assert.True(t, span.Parent().IsValid())
+	assert.Equal(t, span.SpanContext().TraceID(), span.Parent().TraceID())
+}
+
+func TestSDKIntegrationWithLatencyForcedSamplingSampledSpan(t *testing.T) {
+	// prepare router and span recorder
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	router, sr := newSDKTestRouter("foobar", true,
+		otelchi.WithClock(clock),
+		otelchi.WithLatencyForcedSampling(time.Second),
+	)
+	router.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		clock.Advance(2 * time.Second)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/slow", nil)})
+
+	// ensure the sampled span is not duplicated
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	for _, attr := range recordedSpans[0].Attributes() {
+		assert.NotEqual(t, otelchi.ForcedSamplingKey, attr.Key)
+	}
+}
@@ -0,0 +1,55 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDKIntegrationWithLifecycleEvents(t *testing.T) {
+	// prepare router and span recorder
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithLifecycleEvents(), otelchi.WithClock(clock))
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		clock.Advance(100 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("hello"))
+		clock.Advance(200 * time.Millisecond)
+		_, _ = w.Write([]byte("world"))
+		clock.Advance(300 * time.Millisecond)
+	})
+	router.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// execute requests
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/user/123", nil),
+		httptest.NewRequest("GET", "/empty", nil),
+	})
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+
+	// ensure the lifecycle events are recorded in order with the timestamps
+	events := recordedSpans[0].Events()
+	require.Len(t, events, 3)
+	assert.Equal(t, otelchi.HeadersReadEventName, events[0].Name)
+	assert.Equal(t, otelchi.FirstByteWrittenEventName, events[1].Name)
+	assert.Equal(t, otelchi.ResponseCompleteEventName, events[2].Name)
+
+	start := recordedSpans[0].StartTime()
+	assert.Equal(t, time.Duration(0), events[0].Time.Sub(start))
+	assert.Equal(t, 100*time.Millisecond, events[1].Time.Sub(start))
+	assert.Equal(t, 600*time.Millisecond, events[2].Time.Sub(start))
+
+	// ensure the first byte event is not recorded without response body
+	events = recordedSpans[1].Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, otelchi.HeadersReadEventName, events[0].Name)
+	assert.Equal(t, otelchi.ResponseCompleteEventName, events[1].Name)
+}
@@ -0,0 +1,118 @@
+package otelchi_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestSDKIntegrationWithLowAllocationMode(t *testing.T) {
+	newRequests := func() []*http.Request {
+		r0 := httptest.NewRequest("GET", "/user/123", nil)
+
+		r1 := httptest.NewRequest("POST", "https://example.com:8443/user/123", nil)
+		r1.TLS = &tls.ConnectionState{}
+		r1.Proto = "HTTP/2"
+		r1.Header.Set("User-Agent", "test-agent")
+		r1.Header.Set("X-Forwarded-For", "203.0.113.1, 10.0.0.1")
+
+		r2 := httptest.NewRequest("GET", "/user/123", nil)
+		r2.Host = "[::1]:8080"
+		r2.RemoteAddr = "[2001:db8::1]:4321"
+		r2.Proto = "SPDY/3"
+		r2.SetBasicAuth("alice", "secret")
+
+		return []*http.Request{r0, r1, r2}
+	}
+
+	testCases := []struct {
+		Name       string
+		ServerName string
+		Options    []otelchi.Option
+	}{
+		{
+			Name:       "Static Server Name",
+			ServerName: "foobar",
+		},
+		{
+			Name:       "Static Server Name With Port",
+			ServerName: "foobar:9090",
+		},
+		{
+			Name:       "Empty Server Name",
+			ServerName: "",
+		},
+		{
+			Name:       "Server Name Fn",
+			ServerName: "foobar",
+			Options: []otelchi.Option{
+				otelchi.WithServerNameFn(func(r *http.Request) string { return r.Host }),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// prepare routers with & without low allocation mode
+			router, sr := newSDKTestRouter(testCase.ServerName, true, testCase.Options...)
+			router.HandleFunc("/user/{id}", ok)
+			lowAllocRouter, lowAllocSR := newSDKTestRouter(testCase.ServerName, true,
+				append(testCase.Options, otelchi.WithLowAllocationMode())...,
+			)
+			lowAllocRouter.HandleFunc("/user/{id}", ok)
+
+			// execute requests
+			executeRequests(router, newRequests())
+			executeRequests(lowAllocRouter, newRequests())
+
+			// ensure the attributes are the same except `enduser.id`
+			spans := sr.Ended()
+			lowAllocSpans := lowAllocSR.Ended()
+			require.Len(t, spans, 3)
+			require.Len(t, lowAllocSpans, 3)
+			for i := range spans {
+				var want []attribute.KeyValue
+				for _, attr := range spans[i].Attributes() {
+					if attr.Key != "enduser.id" {
+						want = append(want, attr)
+					}
+				}
+				assert.ElementsMatch(t, want, lowAllocSpans[i].Attributes())
+			}
+		})
+	}
+}
+
+func TestSDKIntegrationWithLowAllocationModeSpanNames(t *testing.T) {
+	for _, withChiRoutes := range []bool{true, false} {
+		// prepare router with the cached span names
+		router, sr := newSDKTestRouter("foobar", withChiRoutes,
+			otelchi.WithRequestMethodInSpanName(true),
+			otelchi.WithLowAllocationMode(),
+		)
+		router.HandleFunc("/", ok)
+		router.HandleFunc("/user/{id}", ok)
+
+		// execute requests, the cached names are reused by the repeated ones
+		executeRequests(router, []*http.Request{
+			httptest.NewRequest("GET", "/", nil),
+			httptest.NewRequest("GET", "/user/123", nil),
+			httptest.NewRequest("POST", "/user/123", nil),
+			httptest.NewRequest("GET", "/user/456", nil),
+		})
+
+		// ensure the span names are prefixed by the request method
+		spans := sr.Ended()
+		require.Len(t, spans, 4)
+		assert.Equal(t, "GET /", spans[0].Name())
+		assert.Equal(t, "GET /user/{id}", spans[1].Name())
+		assert.Equal(t, "POST /user/{id}", spans[2].Name())
+		assert.Equal(t, "GET /user/{id}", spans[3].Name())
+	}
+}
@@ -0,0 +1,70 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithMethodOverride(t *testing.T) {
+	for _, withChiRoutes := range []bool{true, false} {
+		// prepare router and span recorder
+		router, sr := newSDKTestRouter("foobar", withChiRoutes,
+			otelchi.WithRequestMethodInSpanName(true),
+			otelchi.WithMethodOverride(),
+		)
+		router.Post("/user/{id}", ok)
+
+		// execute requests
+		tunneled := httptest.NewRequest("POST", "/user/123", nil)
+		tunneled.Header.Set("X-HTTP-Method-Override", "delete")
+		invalid := httptest.NewRequest("POST", "/user/123", nil)
+		invalid.Header.Set("X-HTTP-Method-Override", "PURGE")
+		executeRequests(router, []*http.Request{tunneled, invalid})
+
+		// check the recorded spans
+		recordedSpans := sr.Ended()
+		require.Len(t, recordedSpans, 2)
+		assertSpan(t, recordedSpans[0], "DELETE /user/{id}", trace.SpanKindServer, codes.Unset,
+			attribute.String("http.method", "POST"),
+			attribute.String("http.request.method_override", "DELETE"),
+		)
+		assertSpan(t, recordedSpans[1], "POST /user/{id}", trace.SpanKindServer, codes.Unset,
+			attribute.String("http.method", "POST"),
+		)
+		for _, attr := range recordedSpans[1].Attributes() {
+			assert.NotEqual(t, otelchi.MethodOverrideKey, attr.Key)
+		}
+	}
+}
+
+func TestSDKIntegrationWithCustomMethodOverrideHeaders(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true,
+		otelchi.WithRequestMethodInSpanName(true),
+		otelchi.WithMethodOverride("X-Method-Override", "X-HTTP-Method"),
+	)
+	router.Post("/user/{id}", ok)
+
+	// execute requests, the default header is not honored
+	custom := httptest.NewRequest("POST", "/user/123", nil)
+	custom.Header.Set("X-HTTP-Method", "PUT")
+	standard := httptest.NewRequest("POST", "/user/123", nil)
+	standard.Header.Set("X-HTTP-Method-Override", "PUT")
+	executeRequests(router, []*http.Request{custom, standard})
+
+	// check the recorded spans
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	assertSpan(t, recordedSpans[0], "PUT /user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.request.method_override", "PUT"),
+	)
+	assertSpan(t, recordedSpans[1], "POST /user/{id}", trace.SpanKindServer, codes.Unset)
+}
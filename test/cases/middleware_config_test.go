@@ -0,0 +1,91 @@
+package otelchi_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationMiddlewareWithConfig(t *testing.T) {
+	// load the config
+	var cfg otelchi.Config
+	err := json.Unmarshal([]byte(`{
+		"server_name": "foobar",
+		"semconv_version": "new",
+		"request_method_in_span_name": true,
+		"filter_path_regexps": ["^/internal/"],
+		"captured_request_headers": ["X-Tenant-ID"],
+		"trace_response_headers": {"trace_id_header": "X-Custom-Trace-ID"}
+	}`), &cfg)
+	require.NoError(t, err)
+
+	// prepare router and span recorder, the tracer provider is specified
+	// through the functional option
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanProcessor(sr),
+	)
+	middleware, err := otelchi.MiddlewareWithConfig(cfg, otelchi.WithTracerProvider(provider))
+	require.NoError(t, err)
+
+	router := chi.NewRouter()
+	router.Use(middleware)
+	router.HandleFunc("/user/{id}", ok)
+	router.HandleFunc("/internal/cache", ok)
+
+	// execute requests
+	r0 := httptest.NewRequest("GET", "/user/123", nil)
+	r0.Header.Set("X-Tenant-ID", "acme")
+	w0 := httptest.NewRecorder()
+	router.ServeHTTP(w0, r0)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/internal/cache", nil))
+
+	// ensure the config is applied
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "GET /user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("server.address", "foobar"),
+		attribute.String("http.request.method", "GET"),
+		attribute.String("http.route", "/user/{id}"),
+		attribute.StringSlice("http.request.header.x-tenant-id", []string{"acme"}),
+	)
+	assert.Equal(t, recordedSpans[0].SpanContext().TraceID().String(), w0.Header().Get("X-Custom-Trace-ID"))
+}
+
+func TestMiddlewareWithInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Cfg  otelchi.Config
+	}{
+		{
+			Name: "Invalid Semconv Version",
+			Cfg:  otelchi.Config{SemconvVersion: "v2"},
+		},
+		{
+			Name: "Invalid Query Recording Mode",
+			Cfg:  otelchi.Config{QueryRecording: "partial"},
+		},
+		{
+			Name: "Invalid Filter Path Regexp",
+			Cfg:  otelchi.Config{FilterPathRegexps: []string{"("}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			middleware, err := otelchi.MiddlewareWithConfig(testCase.Cfg)
+			assert.Error(t, err)
+			assert.Nil(t, middleware)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithMiddlewareSpans(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithMiddlewareSpans())
+	router.Use(otelchi.WrapMiddleware("auth", passthrough))
+	router.Use(otelchi.WrapMiddleware("rate-limit", passthrough))
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+	// ensure each wrapped middleware has its own child span
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 3)
+	rateLimitSpan, authSpan, serverSpan := recordedSpans[0], recordedSpans[1], recordedSpans[2]
+	assertSpan(t, serverSpan, "/user/{id}", trace.SpanKindServer, codes.Unset)
+	assertSpan(t, authSpan, "auth", trace.SpanKindInternal, codes.Unset)
+	assertSpan(t, rateLimitSpan, "rate-limit", trace.SpanKindInternal, codes.Unset)
+	assert.Equal(t, serverSpan.SpanContext().SpanID(), authSpan.Parent().SpanID())
+	assert.Equal(t, authSpan.SpanContext().SpanID(), rateLimitSpan.Parent().SpanID())
+}
+
+func TestWrapMiddlewareWithoutMiddlewareSpans(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true)
+	var called bool
+	router.Use(otelchi.WrapMiddleware("auth", func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			next.ServeHTTP(w, r)
+		})
+	}))
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+	// ensure the middleware is executed without creating child span
+	assert.True(t, called)
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset)
+
+	// ensure the middleware works without the tracing middleware
+	called = false
+	plain := chi.NewRouter()
+	plain.Use(otelchi.WrapMiddleware("auth", passthrough))
+	plain.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	plain.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/user/123", nil))
+	assert.True(t, called)
+}
+
+func passthrough(next http.Handler) http.Handler {
+	return next
+}
@@ -7,6 +7,8 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
@@ -96,6 +98,55 @@ func TestPropagationWithCustomPropagators(t *testing.T) {
 	assert.True(t, called, "failed to run test")
 }
 
+func TestPropagationWithCarrierFn(t *testing.T) {
+	prop := propagation.TraceContext{}
+
+	// put the trace context into the query parameters
+	query := url.Values{}
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), sc)
+	prop.Inject(ctx, queryCarrier(query))
+	r := httptest.NewRequest("GET", "/user/123?"+query.Encode(), nil)
+	w := httptest.NewRecorder()
+
+	var called bool
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware(
+		"foobar",
+		otelchi.WithPropagators(prop),
+		otelchi.WithCarrierFn(func(r *http.Request) propagation.TextMapCarrier {
+			return queryCarrier(r.URL.Query())
+		}),
+	))
+	router.HandleFunc("/user/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		span := trace.SpanFromContext(r.Context())
+		assert.Equal(t, sc, span.SpanContext())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	router.ServeHTTP(w, r)
+	assert.True(t, called, "failed to run test")
+}
+
+// queryCarrier adapts url.Values to satisfy the TextMapCarrier interface.
+type queryCarrier url.Values
+
+func (c queryCarrier) Get(key string) string {
+	return url.Values(c).Get(key)
+}
+
+func (c queryCarrier) Set(key string, value string) {
+	url.Values(c).Set(key, value)
+}
+
+func (c queryCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 func TestResponseWriterInterfaces(t *testing.T) {
 	// make sure the recordingResponseWriter preserves interfaces implemented by the wrapped writer
 	router := chi.NewRouter()
@@ -148,3 +199,38 @@ func (rw *testResponseWriter) Flush() {
 func (rw *testResponseWriter) ReadFrom(r io.Reader) (n int64, err error) {
 	return 0, nil
 }
+
+func TestResponseWriterUnwrapAndReadFrom(t *testing.T) {
+	// make sure the recordingResponseWriter could be used with http.ResponseController
+	// and still counts the bytes written through io.ReaderFrom
+	var info otelchi.ResponseInfo
+	recorder := httptest.NewRecorder()
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware("foobar"))
+	router.HandleFunc("/stream", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, http.NewResponseController(w).Flush())
+		_, err := io.Copy(w, io.LimitReader(strings.NewReader("hello"), 5))
+		assert.NoError(t, err)
+		info, _ = otelchi.ResponseInfoFromContext(r.Context())
+	}))
+
+	r := httptest.NewRequest("GET", "/stream", nil)
+	w := &readerFromResponseWriter{ResponseRecorder: recorder}
+
+	router.ServeHTTP(w, r)
+	assert.True(t, recorder.Flushed)
+	assert.True(t, w.readFromCalled)
+	assert.Equal(t, "hello", recorder.Body.String())
+	assert.Equal(t, int64(5), info.BytesWritten)
+}
+
+type readerFromResponseWriter struct {
+	*httptest.ResponseRecorder
+	readFromCalled bool
+}
+
+// implement io.ReaderFrom
+func (rw *readerFromResponseWriter) ReadFrom(r io.Reader) (int64, error) {
+	rw.readFromCalled = true
+	return io.Copy(rw.ResponseRecorder, r)
+}
@@ -0,0 +1,67 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithMountedSubrouter(t *testing.T) {
+	for _, withChiRoutes := range []bool{false, true} {
+		// prepare the sub-router & record the route seen by the route filter
+		var filteredRoutes []string
+		subrouter, sr := newSDKTestRouter("foobar", withChiRoutes, otelchi.WithRouteFilter(func(route string, r *http.Request) bool {
+			filteredRoutes = append(filteredRoutes, route)
+			return true
+		}))
+		subrouter.HandleFunc("/users/{id}", ok)
+
+		router := chi.NewRouter()
+		router.Route("/api/{version}", func(r chi.Router) {
+			r.Mount("/v1", subrouter)
+		})
+
+		// execute request
+		executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/api/foo/v1/users/123", nil)})
+
+		// check the recorded span
+		recordedSpans := sr.Ended()
+		require.Len(t, recordedSpans, 1)
+		assertSpan(t, recordedSpans[0], "/api/{version}/v1/users/{id}", trace.SpanKindServer, codes.Unset,
+			attribute.String("http.route", "/api/{version}/v1/users/{id}"),
+		)
+
+		// the route is resolved before the span is started when the routes
+		// are specified
+		if withChiRoutes {
+			assert.Equal(t, []string{"/api/{version}/v1/users/{id}"}, filteredRoutes)
+		}
+	}
+}
+
+func TestSDKIntegrationWithMountPrefix(t *testing.T) {
+	// prepare the router mounted outside of chi
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithMountPrefix("/api/"))
+	router.HandleFunc("/users/{id}", ok)
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", http.StripPrefix("/api", router))
+
+	// execute request
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/users/123", nil))
+
+	// check the recorded span
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/api/users/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.route", "/api/users/{id}"),
+	)
+}
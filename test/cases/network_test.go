@@ -0,0 +1,35 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithNetworkAttributes(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithNetworkAttributes())
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute request
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	r.Header.Set("User-Agent", "test-agent/1.0")
+	r.Header.Set("X-Forwarded-For", "203.0.113.7")
+	executeRequests(router, []*http.Request{r})
+
+	// ensure the network attributes are recorded
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("client.address", "203.0.113.7"),
+		attribute.String("user_agent.original", "test-agent/1.0"),
+		attribute.String("network.protocol.version", "1.1"),
+		attribute.String("network.peer.address", "192.0.2.1"),
+	)
+}
@@ -0,0 +1,84 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracedNotFoundAndMethodNotAllowedHandlers(t *testing.T) {
+	// prepare span recorder
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	provider.RegisterSpanProcessor(sr)
+
+	// prepare router, the middleware is only installed for the api group
+	router := chi.NewRouter()
+	router.Group(func(r chi.Router) {
+		r.Use(otelchi.Middleware("foobar", otelchi.WithTracerProvider(provider)))
+		r.Get("/user/{id}", ok)
+	})
+	router.NotFound(otelchi.NewTracedNotFoundHandler(nil, "foobar", otelchi.WithTracerProvider(provider)))
+	router.MethodNotAllowed(otelchi.NewTracedMethodNotAllowedHandler(nil, "foobar", otelchi.WithTracerProvider(provider)))
+
+	// execute requests
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/user/123", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	// ensure both responses are traced
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	assertSpan(t, recordedSpans[0], "/", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.method", "GET"),
+		attribute.Int("http.status_code", http.StatusNotFound),
+	)
+	assertSpan(t, recordedSpans[1], "/", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.method", "DELETE"),
+		attribute.Int("http.status_code", http.StatusMethodNotAllowed),
+	)
+}
+
+func TestSDKIntegrationWithNotFoundRouteLabel(t *testing.T) {
+	for _, withChiRoutes := range []bool{true, false} {
+		// prepare router & span recorder
+		router, sr := newSDKTestRouter("foobar", withChiRoutes,
+			otelchi.WithRequestMethodInSpanName(true),
+			otelchi.WithNotFoundRouteLabel("{not_found}"),
+		)
+		router.HandleFunc("/user/{id}", ok)
+
+		// execute requests
+		executeRequests(router, []*http.Request{
+			httptest.NewRequest("GET", "/user/123", nil),
+			httptest.NewRequest("GET", "/wp-admin/setup.php", nil),
+			httptest.NewRequest("GET", "/.env", nil),
+		})
+
+		// ensure the unmatched requests are recorded with the synthetic route
+		recordedSpans := sr.Ended()
+		require.Len(t, recordedSpans, 3)
+		assertSpan(t, recordedSpans[0], "GET /user/{id}", trace.SpanKindServer, codes.Unset,
+			attribute.String("http.route", "/user/{id}"),
+		)
+		for _, span := range recordedSpans[1:] {
+			assertSpan(t, span, "GET {not_found}", trace.SpanKindServer, codes.Unset,
+				attribute.String("http.route", "{not_found}"),
+				attribute.Int("http.status_code", http.StatusNotFound),
+			)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package otelchi_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/riandyrn/otelchi/otelchitest"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestOtelchitestRecordingRouter(t *testing.T) {
+	router, sr := otelchitest.NewRecordingRouter(otelchi.WithRequestMethodInSpanName(true))
+	router.HandleFunc("/user/{id}", ok)
+	router.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/user/123", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/fail", nil))
+
+	assert.True(t, otelchitest.AssertSpans(t, sr.Ended(),
+		otelchitest.SpanWant{
+			Name:   "GET /user/{id}",
+			Kind:   trace.SpanKindServer,
+			Status: codes.Unset,
+			Attributes: []attribute.KeyValue{
+				attribute.String("net.host.name", otelchitest.ServerName),
+				attribute.String("http.route", "/user/{id}"),
+				attribute.Int("http.status_code", http.StatusOK),
+			},
+		},
+		otelchitest.SpanWant{
+			Name:   "POST /fail",
+			Kind:   trace.SpanKindServer,
+			Status: codes.Error,
+			Attributes: []attribute.KeyValue{
+				attribute.Int("http.status_code", http.StatusInternalServerError),
+			},
+		},
+	))
+}
+
+func TestOtelchitestAssertSpanMismatch(t *testing.T) {
+	router, sr := otelchitest.NewRecordingRouter()
+	router.HandleFunc("/user/{id}", ok)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/user/123", nil))
+
+	spans := sr.Ended()
+	assert.Len(t, spans, 1)
+
+	mockT := &failureRecorder{TB: t}
+	assert.False(t, otelchitest.AssertSpan(mockT, spans[0], otelchitest.SpanWant{
+		Name: "/users/{id}",
+		Kind: trace.SpanKindServer,
+		Attributes: []attribute.KeyValue{
+			attribute.String("missing", "value"),
+		},
+	}))
+	assert.Len(t, mockT.failures, 2)
+}
+
+// failureRecorder records the failures instead of failing the test.
+type failureRecorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *failureRecorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
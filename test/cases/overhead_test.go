@@ -0,0 +1,129 @@
+package otelchi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithOverheadMetric(t *testing.T) {
+	// prepare router, span recorder & metric reader
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	router, _ := newSDKTestRouter("foobar", true, otelchi.WithOverheadMetric(provider))
+
+	handlerLatency := 20 * time.Millisecond
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(handlerLatency)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+	// read the recorded metrics
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	metrics := rm.ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "otelchi.middleware.overhead", metrics[0].Name)
+
+	hist, ok := metrics[0].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+
+	// ensure the handler latency is excluded from the overhead
+	dp := hist.DataPoints[0]
+	assert.Equal(t, uint64(1), dp.Count)
+	assert.Greater(t, dp.Sum, float64(0))
+	assert.Less(t, dp.Sum, handlerLatency.Seconds())
+}
+
+func TestSDKIntegrationWithOverheadMetricProvider(t *testing.T) {
+	// prepare router with separate providers for the overhead & other metrics
+	overheadReader := sdkmetric.NewManualReader()
+	overheadProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(overheadReader))
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	router, _ := newSDKTestRouter("foobar", true,
+		otelchi.WithMeterProvider(provider),
+		otelchi.WithOverheadMetric(overheadProvider),
+		otelchi.WithRequestQueueTimeMetric(""),
+	)
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute request
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	r.Header.Set("X-Request-Start", strconv.FormatInt(time.Now().Add(-time.Second).UnixMilli(), 10))
+	executeRequests(router, []*http.Request{r})
+
+	// the overhead metric is only recorded by its own provider
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, overheadReader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	assert.Equal(t, "otelchi.middleware.overhead", rm.ScopeMetrics[0].Metrics[0].Name)
+
+	// the queue metric keeps using the provider specified by WithMeterProvider
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	assert.Equal(t, "http.server.queue.duration", rm.ScopeMetrics[0].Metrics[0].Name)
+}
+
+// failingMeterProvider provides the meter failing to create any float64
+// histogram.
+type failingMeterProvider struct {
+	noop.MeterProvider
+}
+
+func (failingMeterProvider) Meter(string, ...otelmetric.MeterOption) otelmetric.Meter {
+	return failingMeter{}
+}
+
+type failingMeter struct {
+	noop.Meter
+}
+
+func (failingMeter) Float64Histogram(string, ...otelmetric.Float64HistogramOption) (otelmetric.Float64Histogram, error) {
+	return nil, errors.New("instrument limit exceeded")
+}
+
+func TestSDKIntegrationWithOverheadMetricInstrumentError(t *testing.T) {
+	var handledErrs []error
+	defer func(h otel.ErrorHandler) { otel.SetErrorHandler(h) }(otel.GetErrorHandler())
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		handledErrs = append(handledErrs, err)
+	}))
+
+	// ensure the middleware is created without panicking & the error is
+	// reported to the error handler
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithOverheadMetric(failingMeterProvider{}))
+	router.HandleFunc("/user/{id}", ok)
+	require.Len(t, handledErrs, 1)
+	assert.EqualError(t, handledErrs[0], "unable to create otelchi.middleware.overhead histogram: instrument limit exceeded")
+
+	// ensure the request is still traced
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset)
+}
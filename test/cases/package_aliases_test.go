@@ -0,0 +1,67 @@
+package otelchi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/riandyrn/otelchi/metric"
+	"github.com/riandyrn/otelchi/otelchicore"
+	"github.com/riandyrn/otelchi/otelchimetric"
+	"github.com/riandyrn/otelchi/otelchitrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+func TestPackageAliasesAreInterchangeable(t *testing.T) {
+	// the server name & the route cache are shared by the tracing middleware
+	// & the metric recorders through the core types
+	serverName := otelchicore.NewServerName("old-name")
+	routeCache := otelchi.NewRouteCache(otelchicore.DefaultRouteCacheSize)
+
+	reader := sdkmetric.NewManualReader()
+	// the options of the old packages are accepted by the new ones & vice versa
+	metricCfg := metric.NewBaseConfig(
+		"ignored",
+		otelchimetric.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		metric.WithDynamicServerName(serverName),
+		otelchimetric.WithRouteCache(routeCache),
+	)
+	router, sr := newSDKTestRouter(
+		"ignored", true,
+		otelchitrace.WithDynamicServerName(serverName),
+		otelchi.WithRouteCache(routeCache),
+	)
+	router.Use(otelchimetric.MustNewRequestDuration(metricCfg))
+	router.Get("/user/{id}", ok)
+
+	serverName.Set("new-name")
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user/123", nil))
+
+	// the span is recorded with the shared server name & route
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "/user/{id}", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), semconv.NetHostName("new-name"))
+
+	// the metric is recorded with the same server name & route
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	// the scope name is kept from before the split
+	assert.Equal(t, "github.com/riandyrn/otelchi/metric", rm.ScopeMetrics[0].Scope.Name)
+	histogram, isHistogram := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[int64])
+	require.True(t, isHistogram)
+	require.Len(t, histogram.DataPoints, 1)
+	attrs := histogram.DataPoints[0].Attributes
+	host, _ := attrs.Value(semconv.NetHostNameKey)
+	assert.Equal(t, "new-name", host.AsString())
+	route, _ := attrs.Value(semconv.HTTPRouteKey)
+	assert.Equal(t, attribute.StringValue("/user/{id}"), route)
+}
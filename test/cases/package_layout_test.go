@@ -0,0 +1,93 @@
+package otelchi_test
+
+import (
+	"go/build"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const modulePath = "github.com/riandyrn/otelchi"
+
+func TestPackageLayoutDependencies(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Package    string
+		Disallowed []string
+	}{
+		{
+			Name:    "Core",
+			Package: modulePath + "/otelchicore",
+			Disallowed: []string{
+				modulePath, modulePath + "/metric", modulePath + "/otelchitrace", modulePath + "/otelchimetric",
+				"go.opentelemetry.io/otel/sdk",
+			},
+		},
+		{
+			Name:       "Tracing",
+			Package:    modulePath + "/otelchitrace",
+			Disallowed: []string{modulePath, modulePath + "/metric", modulePath + "/otelchimetric", "go.opentelemetry.io/otel/sdk"},
+		},
+		{
+			Name:       "Metric",
+			Package:    modulePath + "/otelchimetric",
+			Disallowed: []string{modulePath, modulePath + "/metric", modulePath + "/otelchitrace", "go.opentelemetry.io/otel/sdk"},
+		},
+		{
+			Name:       "TracingAliases",
+			Package:    modulePath,
+			Disallowed: []string{modulePath + "/metric", modulePath + "/otelchimetric", "go.opentelemetry.io/otel/sdk"},
+		},
+		{
+			Name:       "MetricAliases",
+			Package:    modulePath + "/metric",
+			Disallowed: []string{modulePath, modulePath + "/otelchitrace", "go.opentelemetry.io/otel/sdk"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			imports := moduleImports(t, testCase.Package)
+			for _, imp := range imports {
+				for _, disallowed := range testCase.Disallowed {
+					// the module path only disallows the root package, the
+					// internal packages are shared
+					isDisallowed := imp == disallowed ||
+						(disallowed != modulePath && strings.HasPrefix(imp, disallowed+"/"))
+					assert.False(t, isDisallowed, "%s must not depend on %s", testCase.Package, imp)
+				}
+			}
+		})
+	}
+}
+
+// moduleImports returns the imports of the given package, including the ones
+// of the module packages it depends on transitively.
+func moduleImports(t *testing.T, pkgPath string) []string {
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	require.NoError(t, err)
+
+	var imports []string
+	visited := map[string]bool{}
+	var visit func(pkgPath string)
+	visit = func(pkgPath string) {
+		if visited[pkgPath] {
+			return
+		}
+		visited[pkgPath] = true
+
+		dir := filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(pkgPath, modulePath)))
+		pkg, err := build.ImportDir(dir, 0)
+		require.NoError(t, err)
+		for _, imp := range pkg.Imports {
+			imports = append(imports, imp)
+			if strings.HasPrefix(imp, modulePath+"/") {
+				visit(imp)
+			}
+		}
+	}
+	visit(pkgPath)
+	return imports
+}
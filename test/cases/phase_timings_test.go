@@ -0,0 +1,58 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// slowResponseWriter is the response writer advancing the clock on every
+// write, simulating the slow client.
+type slowResponseWriter struct {
+	*httptest.ResponseRecorder
+
+	clock *fakeClock
+	delay time.Duration
+}
+
+func (w slowResponseWriter) WriteHeader(statusCode int) {
+	w.clock.Advance(w.delay)
+	w.ResponseRecorder.WriteHeader(statusCode)
+}
+
+func (w slowResponseWriter) Write(b []byte) (int, error) {
+	w.clock.Advance(w.delay)
+	return w.ResponseRecorder.Write(b)
+}
+
+func TestSDKIntegrationWithPhaseTimings(t *testing.T) {
+	// prepare router and span recorder
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithClock(clock), otelchi.WithPhaseTimings())
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		// simulate the business logic followed by the slow serialization
+		clock.Advance(30 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	w := slowResponseWriter{ResponseRecorder: httptest.NewRecorder(), clock: clock, delay: 10 * time.Millisecond}
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/user/123", nil))
+
+	// ensure the time spent writing the response is separated from the time
+	// spent in the handler
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.Float64("http.server.duration.middleware_ms", 0),
+		attribute.Float64("http.server.duration.handler_ms", 30),
+		attribute.Float64("http.server.duration.write_ms", 20),
+	)
+}
@@ -0,0 +1,98 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithProblemDetailsCapture(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithProblemDetailsCapture(1024))
+	writeProblem := func(status int, body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}
+	}
+	router.Get("/orders/{id}", writeProblem(http.StatusNotFound,
+		`{"type":"https://example.com/probs/not-found","title":"Order not found","detail":"Order 123 does not exist"}`,
+	))
+	router.Get("/payments/{id}", writeProblem(http.StatusBadGateway,
+		`{"type":"about:blank","title":"Payment provider unavailable"}`,
+	))
+	router.Get("/invalid", writeProblem(http.StatusInternalServerError, `{"title":`))
+	router.Get("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"title":"Bad request"}`))
+	})
+
+	// execute requests
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/orders/123", nil),
+		httptest.NewRequest("GET", "/payments/123", nil),
+		httptest.NewRequest("GET", "/invalid", nil),
+		httptest.NewRequest("GET", "/json", nil),
+	})
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 4)
+
+	// the problem type is recorded as error type
+	assertSpan(t, recordedSpans[0], "/orders/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("error.type", "https://example.com/probs/not-found"),
+		attribute.String("problem.title", "Order not found"),
+		attribute.String("problem.detail", "Order 123 does not exist"),
+	)
+
+	// the status code is recorded as error type for `about:blank` type, and
+	// the title describes the error status
+	assertSpan(t, recordedSpans[1], "/payments/{id}", trace.SpanKindServer, codes.Error,
+		attribute.String("error.type", "502"),
+		attribute.String("problem.title", "Payment provider unavailable"),
+	)
+	assert.Equal(t, "Payment provider unavailable", recordedSpans[1].Status().Description)
+
+	// the malformed problem details & other content types are not recorded
+	for _, span := range recordedSpans[2:] {
+		for _, attr := range span.Attributes() {
+			assert.NotEqual(t, attribute.Key("error.type"), attr.Key)
+			assert.NotEqual(t, otelchi.ProblemTitleKey, attr.Key)
+		}
+	}
+}
+
+func TestSDKIntegrationWithProblemDetailsCaptureTruncated(t *testing.T) {
+	// prepare router and span recorder, the body capture is enabled as well
+	router, sr := newSDKTestRouter("foobar", true,
+		otelchi.WithProblemDetailsCapture(16),
+		otelchi.WithBodyCapture(1024),
+	)
+	router.Get("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"title":"Order not found"}`))
+	})
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/orders/123", nil)})
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+
+	// the problem details exceeding the limit are not recorded, while the
+	// body capture is not affected
+	for _, attr := range recordedSpans[0].Attributes() {
+		assert.NotEqual(t, otelchi.ProblemTitleKey, attr.Key)
+	}
+	events := recordedSpans[0].Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "http.response.body", events[0].Name)
+}
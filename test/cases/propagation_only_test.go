@@ -0,0 +1,47 @@
+package otelchi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithTracePropagationOnly(t *testing.T) {
+	// prepare router and span recorder
+	propagator := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	router, sr := newSDKTestRouter("foobar", true,
+		otelchi.WithTracePropagationOnly(),
+		otelchi.WithPropagators(propagator),
+		otelchi.WithTraceResponseHeaders(otelchi.TraceHeaderConfig{EmitTraceparent: true}),
+	)
+	var called bool
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		// the incoming trace context & baggage are exposed to the handler
+		assert.Equal(t, sc, trace.SpanContextFromContext(r.Context()))
+		assert.Equal(t, "acme", baggage.FromContext(r.Context()).Member("tenant").Value())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute request with the incoming trace context
+	bag, _ := baggage.Parse("tenant=acme")
+	ctx := baggage.ContextWithBaggage(trace.ContextWithRemoteSpanContext(context.Background(), sc), bag)
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	propagator.Inject(ctx, propagation.HeaderCarrier(r.Header))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.True(t, called, "failed to run test")
+
+	// ensure no span is created while the trace headers are written
+	assert.Empty(t, sr.Ended())
+	assert.Equal(t, sc.TraceID().String(), w.Header().Get(otelchi.DefaultTraceIDResponseHeaderKey))
+	assert.Equal(t, "true", w.Header().Get(otelchi.DefaultTraceSampledResponseHeaderKey))
+	assert.Equal(t, r.Header.Get("traceparent"), w.Header().Get("traceparent"))
+}
@@ -0,0 +1,129 @@
+package otelchi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// legacySC is the span context carried in the legacy header, it conflicts
+// with the span context carried in the W3C header.
+var legacySC = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID:    [16]byte{2},
+	SpanID:     [8]byte{2},
+	Remote:     true,
+	TraceFlags: trace.FlagsSampled,
+})
+
+func TestPropagationWithOrderedPropagators(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Options     []otelchi.Option
+		WantSC      trace.SpanContext
+		WantBaggage string
+	}{
+		{
+			Name: "First Valid W3C Precedence",
+			Options: []otelchi.Option{
+				otelchi.WithPropagatorsOrdered(propagation.TraceContext{}, propagation.Baggage{}, legacyPropagator{}),
+			},
+			WantSC:      sc,
+			WantBaggage: "",
+		},
+		{
+			Name: "First Valid Legacy Precedence",
+			Options: []otelchi.Option{
+				otelchi.WithPropagatorsOrdered(legacyPropagator{}, propagation.TraceContext{}),
+			},
+			WantSC:      legacySC,
+			WantBaggage: "source=legacy",
+		},
+		{
+			Name: "Merge Baggage",
+			Options: []otelchi.Option{
+				otelchi.WithPropagationMode(otelchi.PropagationMergeBaggage),
+				otelchi.WithPropagatorsOrdered(propagation.TraceContext{}, propagation.Baggage{}, legacyPropagator{}),
+			},
+			WantSC:      sc,
+			WantBaggage: "source=w3c,user=1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// prepare request carrying both W3C & legacy headers
+			r := httptest.NewRequest("GET", "/user/123", nil)
+			ctx := trace.ContextWithRemoteSpanContext(context.Background(), sc)
+			propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(r.Header))
+			r.Header.Set("baggage", "user=1,source=w3c")
+			r.Header.Set(legacyTraceHeader, legacySC.TraceID().String()+"-"+legacySC.SpanID().String())
+
+			var called bool
+			router := chi.NewRouter()
+			router.Use(otelchi.Middleware("foobar", testCase.Options...))
+			router.HandleFunc("/user/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				assert.Equal(t, testCase.WantSC, trace.SpanFromContext(r.Context()).SpanContext())
+				assert.ElementsMatch(t, baggageMembers(testCase.WantBaggage), baggage.FromContext(r.Context()).Members())
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			router.ServeHTTP(httptest.NewRecorder(), r)
+			assert.True(t, called, "failed to run test")
+		})
+	}
+}
+
+func baggageMembers(s string) []baggage.Member {
+	bag, _ := baggage.Parse(s)
+	return bag.Members()
+}
+
+const legacyTraceHeader = "X-Legacy-Trace"
+
+// legacyPropagator extracts the span context from the `X-Legacy-Trace`
+// header formatted as `<trace-id>-<span-id>`, it also marks the baggage with
+// `source=legacy`.
+type legacyPropagator struct{}
+
+func (legacyPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	carrier.Set(legacyTraceHeader, spanCtx.TraceID().String()+"-"+spanCtx.SpanID().String())
+}
+
+func (legacyPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	traceIDStr, spanIDStr, found := strings.Cut(carrier.Get(legacyTraceHeader), "-")
+	if !found {
+		return ctx
+	}
+	traceID, err := trace.TraceIDFromHex(traceIDStr)
+	if err != nil {
+		return ctx
+	}
+	spanID, err := trace.SpanIDFromHex(spanIDStr)
+	if err != nil {
+		return ctx
+	}
+	member, _ := baggage.NewMember("source", "legacy")
+	bag, _ := baggage.New(member)
+	ctx = baggage.ContextWithBaggage(ctx, bag)
+	return trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		Remote:     true,
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
+
+func (legacyPropagator) Fields() []string {
+	return []string{legacyTraceHeader}
+}
@@ -0,0 +1,78 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithQueryRecording(t *testing.T) {
+	const target = "/search?q=shoes&page=2&access_token=s3cr3t&Sort=price&sort=name&X-Session-ID=abc&flag"
+
+	testCases := []struct {
+		Name      string
+		Options   []otelchi.Option
+		WantQuery string
+	}{
+		{
+			Name:      "Full",
+			Options:   []otelchi.Option{otelchi.WithQueryRecording(otelchi.QueryRecordingFull)},
+			WantQuery: "q=shoes&page=2&access_token=s3cr3t&Sort=price&sort=name&X-Session-ID=abc&flag",
+		},
+		{
+			Name:      "Redacted",
+			Options:   []otelchi.Option{otelchi.WithQueryRecording(otelchi.QueryRecordingRedacted)},
+			WantQuery: "q=shoes&page=2&access_token=REDACTED&Sort=price&sort=name&X-Session-ID=REDACTED&flag",
+		},
+		{
+			Name: "Redacted With Allowlist & Denylist",
+			Options: []otelchi.Option{
+				otelchi.WithQueryRecording(otelchi.QueryRecordingRedacted),
+				otelchi.WithQueryRedactionAllowlist("x-session-id"),
+				otelchi.WithQueryRedactionDenylist("SORT"),
+			},
+			WantQuery: "q=shoes&page=2&access_token=REDACTED&Sort=REDACTED&sort=REDACTED&X-Session-ID=abc&flag",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// prepare router and span recorder
+			router, sr := newSDKTestRouter("foobar", true, testCase.Options...)
+			router.HandleFunc("/search", ok)
+
+			// execute request
+			executeRequests(router, []*http.Request{httptest.NewRequest("GET", target, nil)})
+
+			// check the recorded query
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, 1)
+			assertSpan(t, recordedSpans[0], "/search", trace.SpanKindServer, codes.Unset,
+				attribute.String("url.query", testCase.WantQuery),
+			)
+		})
+	}
+}
+
+func TestSDKIntegrationWithoutQueryRecording(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true)
+	router.HandleFunc("/search", ok)
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/search?q=shoes", nil)})
+
+	// ensure the query string is not recorded by default
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	for _, attr := range recordedSpans[0].Attributes() {
+		assert.NotEqual(t, attribute.Key("url.query"), attr.Key)
+	}
+}
@@ -0,0 +1,142 @@
+package otelchi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithRequestQueueTimeMetric(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC)
+	testCases := []struct {
+		Name         string
+		HeaderValue  string
+		WantQueued   bool
+		WantDuration float64
+	}{
+		{
+			Name:         "Milliseconds",
+			HeaderValue:  strconv.FormatInt(now.Add(-250*time.Millisecond).UnixMilli(), 10),
+			WantQueued:   true,
+			WantDuration: 250,
+		},
+		{
+			Name:         "Microseconds With Prefix",
+			HeaderValue:  "t=" + strconv.FormatInt(now.Add(-1500*time.Microsecond).UnixMicro(), 10),
+			WantQueued:   true,
+			WantDuration: 1.5,
+		},
+		{
+			Name:         "Seconds With Fraction",
+			HeaderValue:  "t=1704067200.5",
+			WantQueued:   true,
+			WantDuration: 500,
+		},
+		{
+			Name:        "Future Timestamp",
+			HeaderValue: strconv.FormatInt(now.Add(time.Second).UnixMilli(), 10),
+		},
+		{
+			Name:        "Invalid Timestamp",
+			HeaderValue: "t=abc",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// prepare router, span recorder & metric reader
+			reader := sdkmetric.NewManualReader()
+			provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			router, sr := newSDKTestRouter("foobar", true,
+				otelchi.WithClock(&fakeClock{now: now}),
+				otelchi.WithMeterProvider(provider),
+				otelchi.WithRequestQueueTimeMetric(""),
+			)
+			router.HandleFunc("/user/{id}", ok)
+
+			// execute request
+			r := httptest.NewRequest("GET", "/user/123", nil)
+			r.Header.Set("X-Request-Start", testCase.HeaderValue)
+			executeRequests(router, []*http.Request{r})
+
+			// check the recorded span
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, 1)
+			var queueAttr attribute.Value
+			for _, attr := range recordedSpans[0].Attributes() {
+				if attr.Key == otelchi.QueueDurationKey {
+					queueAttr = attr.Value
+				}
+			}
+
+			// read the recorded metrics
+			var rm metricdata.ResourceMetrics
+			require.NoError(t, reader.Collect(context.Background(), &rm))
+			if !testCase.WantQueued {
+				assert.Equal(t, attribute.INVALID, queueAttr.Type())
+				if len(rm.ScopeMetrics) > 0 {
+					hist := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64])
+					assert.Empty(t, hist.DataPoints)
+				}
+				return
+			}
+			assert.InDelta(t, testCase.WantDuration, queueAttr.AsFloat64(), 0.001)
+			assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset)
+
+			require.Len(t, rm.ScopeMetrics, 1)
+			metrics := rm.ScopeMetrics[0].Metrics
+			require.Len(t, metrics, 1)
+			assert.Equal(t, "http.server.queue.duration", metrics[0].Name)
+			assert.Equal(t, "s", metrics[0].Unit)
+
+			hist, ok := metrics[0].Data.(metricdata.Histogram[float64])
+			require.True(t, ok)
+			require.Len(t, hist.DataPoints, 1)
+			dp := hist.DataPoints[0]
+			assert.Equal(t, uint64(1), dp.Count)
+			// the span attribute is in milliseconds, the histogram is in seconds
+			assert.InDelta(t, testCase.WantDuration/1000, dp.Sum, 0.000001)
+			route, _ := dp.Attributes.Value("http.route")
+			assert.Equal(t, "/user/{id}", route.AsString())
+		})
+	}
+}
+
+func TestSDKIntegrationWithRequestQueueTimeMetricInstrumentError(t *testing.T) {
+	var handledErrs []error
+	defer func(h otel.ErrorHandler) { otel.SetErrorHandler(h) }(otel.GetErrorHandler())
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		handledErrs = append(handledErrs, err)
+	}))
+
+	// ensure the middleware is created without panicking & the error is
+	// reported to the error handler
+	router, sr := newSDKTestRouter("foobar", true,
+		otelchi.WithMeterProvider(failingMeterProvider{}),
+		otelchi.WithRequestQueueTimeMetric(""),
+	)
+	router.HandleFunc("/user/{id}", ok)
+	require.Len(t, handledErrs, 1)
+	assert.EqualError(t, handledErrs[0], "unable to create http.server.queue.duration histogram: instrument limit exceeded")
+
+	// ensure the request is still traced
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	r.Header.Set(otelchi.DefaultRequestStartHeader, strconv.FormatInt(time.Now().UnixMilli(), 10))
+	executeRequests(router, []*http.Request{r})
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset)
+}
@@ -0,0 +1,77 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithRequestID(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithRequestID("", true))
+	var requestIDs, chiRequestIDs []string
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := otelchi.RequestIDFromContext(r.Context())
+		assert.True(t, ok)
+		requestIDs = append(requestIDs, id)
+		chiRequestIDs = append(chiRequestIDs, middleware.GetReqID(r.Context()))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute requests with & without the request ID
+	r1 := httptest.NewRequest("GET", "/user/123", nil)
+	r1.Header.Set("X-Request-ID", "req-1")
+	w1 := httptest.NewRecorder()
+	router.ServeHTTP(w1, r1)
+
+	r2 := httptest.NewRequest("GET", "/user/456", nil)
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, r2)
+
+	// the generated request ID is the trace ID
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	generatedID := recordedSpans[1].SpanContext().TraceID().String()
+	assert.Equal(t, []string{"req-1", generatedID}, requestIDs)
+	assert.Equal(t, requestIDs, chiRequestIDs)
+
+	// the request ID is recorded & echoed in the response
+	assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.request.id", "req-1"),
+	)
+	assertSpan(t, recordedSpans[1], "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.request.id", generatedID),
+	)
+	assert.Equal(t, "req-1", w1.Header().Get("X-Request-ID"))
+	assert.Equal(t, generatedID, w2.Header().Get("X-Request-ID"))
+}
+
+func TestSDKIntegrationWithRequestIDNotGenerated(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithRequestID("X-Correlation-ID", false))
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, ok := otelchi.RequestIDFromContext(r.Context())
+		assert.False(t, ok)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute request without the request ID
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/user/123", nil))
+
+	// ensure nothing is recorded
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	for _, attr := range recordedSpans[0].Attributes() {
+		assert.NotEqual(t, otelchi.RequestIDKey, attr.Key)
+	}
+	assert.Empty(t, w.Header().Get("X-Correlation-ID"))
+}
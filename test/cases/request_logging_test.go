@@ -0,0 +1,57 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/logtest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithRequestLogging(t *testing.T) {
+	// prepare router, span recorder, and log recorder
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	recorder := logtest.NewRecorder()
+	router, sr := newSDKTestRouter("foobar", false,
+		otelchi.WithRequestLogging(recorder),
+		otelchi.WithClock(clock),
+	)
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		clock.Advance(250 * time.Millisecond)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+	// ensure one log record is emitted for the request
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+
+	scopes := recorder.Result()
+	require.Len(t, scopes, 1)
+	assert.Equal(t, "github.com/riandyrn/otelchi", scopes[0].Name)
+	require.Len(t, scopes[0].Records, 1)
+
+	record := scopes[0].Records[0]
+	assert.Equal(t, log.SeverityWarn, record.Severity())
+	assert.Equal(t, "GET /user/123", record.Body().AsString())
+	assert.Equal(t, clock.Now(), record.Timestamp())
+	assert.Equal(t, recordedSpans[0].SpanContext(), trace.SpanContextFromContext(record.Context()))
+
+	attrs := map[string]log.Value{}
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	assert.Equal(t, "GET", attrs["http.request.method"].AsString())
+	assert.Equal(t, "/user/{id}", attrs["http.route"].AsString())
+	assert.Equal(t, int64(http.StatusNotFound), attrs["http.response.status_code"].AsInt64())
+	assert.Equal(t, 0.25, attrs["http.server.request.duration"].AsFloat64())
+}
@@ -0,0 +1,124 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestSDKIntegrationWithResponseBodyStatusFn(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		ContentType     string
+		Status          int
+		Body            string
+		WantCode        codes.Code
+		WantDescription string
+	}{
+		{
+			Name:            "JSON-RPC Error",
+			ContentType:     "application/json",
+			Status:          http.StatusOK,
+			Body:            `{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"},"id":1}`,
+			WantCode:        codes.Error,
+			WantDescription: "Method not found",
+		},
+		{
+			Name:            "JSON-RPC Batch Error",
+			ContentType:     "application/json; charset=utf-8",
+			Status:          http.StatusOK,
+			Body:            `[{"jsonrpc":"2.0","result":1,"id":1},{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params"},"id":2}]`,
+			WantCode:        codes.Error,
+			WantDescription: "Invalid params",
+		},
+		{
+			Name:            "GraphQL Errors",
+			ContentType:     "application/json",
+			Status:          http.StatusOK,
+			Body:            `{"data":null,"errors":[{"message":"Cannot query field \"foo\""}]}`,
+			WantCode:        codes.Error,
+			WantDescription: `Cannot query field "foo"`,
+		},
+		{
+			Name:        "Successful Response",
+			ContentType: "application/json",
+			Status:      http.StatusOK,
+			Body:        `{"jsonrpc":"2.0","result":1,"error":null,"id":1}`,
+			WantCode:    codes.Unset,
+		},
+		{
+			Name:        "Body Exceeding Limit",
+			ContentType: "application/json",
+			Status:      http.StatusOK,
+			Body:        `{"jsonrpc":"2.0","error":{"code":-32601,"message":"` + strings.Repeat("a", 256) + `"},"id":1}`,
+			WantCode:    codes.Unset,
+		},
+		{
+			Name:        "Content Type Not Matched",
+			ContentType: "text/plain",
+			Status:      http.StatusOK,
+			Body:        `{"error":"failed"}`,
+			WantCode:    codes.Unset,
+		},
+		{
+			Name:        "Server Error Kept",
+			ContentType: "application/json",
+			Status:      http.StatusInternalServerError,
+			Body:        `{"result":1}`,
+			WantCode:    codes.Error,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// prepare router and span recorder
+			router, sr := newSDKTestRouter("foobar", true, otelchi.WithResponseBodyStatusFn(128, otelchi.JSONErrorStatus))
+			router.HandleFunc("/rpc", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", testCase.ContentType)
+				w.WriteHeader(testCase.Status)
+				w.Write([]byte(testCase.Body))
+			})
+
+			executeRequests(router, []*http.Request{httptest.NewRequest("POST", "/rpc", nil)})
+
+			// ensure the span status is derived from the response body
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, 1)
+			assert.Equal(t, testCase.WantCode, recordedSpans[0].Status().Code)
+			assert.Equal(t, testCase.WantDescription, recordedSpans[0].Status().Description)
+		})
+	}
+}
+
+func TestSDKIntegrationWithResponseBodyStatusFnContentTypes(t *testing.T) {
+	// prepare router and span recorder, the function reports the status of
+	// the custom content type only
+	var gotStatus int
+	var gotBody string
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithResponseBodyStatusFn(
+		128,
+		func(status int, body []byte) (codes.Code, string) {
+			gotStatus, gotBody = status, string(body)
+			return codes.Error, "failed"
+		},
+		"application/vnd.api+json",
+	))
+	router.HandleFunc("/rpc", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{"ok":false}`))
+	})
+
+	executeRequests(router, []*http.Request{httptest.NewRequest("POST", "/rpc", nil)})
+
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assert.Equal(t, http.StatusOK, gotStatus)
+	assert.Equal(t, `{"ok":false}`, gotBody)
+	assert.Equal(t, codes.Error, recordedSpans[0].Status().Code)
+	assert.Equal(t, "failed", recordedSpans[0].Status().Description)
+}
@@ -0,0 +1,103 @@
+package otelchi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseInfoFromContext(t *testing.T) {
+	// prepare router with logger middleware reading the response info
+	var (
+		info   otelchi.ResponseInfo
+		infoOK bool
+	)
+	router := chi.NewRouter()
+	router.Use(
+		otelchi.Middleware("foobar", otelchi.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/skip"
+		})),
+		func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, r)
+				info, infoOK = otelchi.ResponseInfoFromContext(r.Context())
+			})
+		},
+	)
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	})
+	router.HandleFunc("/skip", ok)
+
+	// execute traced request
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/user/123", nil))
+	require.True(t, infoOK)
+	assert.Equal(t, otelchi.ResponseInfo{
+		StatusCode:   http.StatusCreated,
+		BytesWritten: 5,
+		Written:      true,
+	}, info)
+
+	// execute filtered request
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/skip", nil))
+	require.False(t, infoOK)
+}
+
+func TestResponseInfoFromContextAfterRequestCompleted(t *testing.T) {
+	// prepare router whose handler reads the response info from a goroutine
+	// outliving the request, this test is meant to be run with `-race`
+	const numRequests = 50
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		release = make(chan struct{})
+		infos   = map[int]otelchi.ResponseInfo{}
+	)
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware("foobar"))
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+		w.WriteHeader(http.StatusOK + id%2)
+		_, _ = w.Write(make([]byte, id))
+
+		ctx := context.WithoutCancel(r.Context())
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// wait until every request is completed, so the pooled writers
+			// have been reused by the later requests
+			<-release
+			info, ok := otelchi.ResponseInfoFromContext(ctx)
+			assert.True(t, ok)
+			mu.Lock()
+			infos[id] = info
+			mu.Unlock()
+		}()
+	})
+
+	// execute requests
+	for i := 0; i < numRequests; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/user/"+strconv.Itoa(i), nil))
+	}
+	close(release)
+	wg.Wait()
+
+	// ensure every goroutine reads the info of its own request
+	require.Len(t, infos, numRequests)
+	for id, info := range infos {
+		assert.Equal(t, otelchi.ResponseInfo{
+			StatusCode:   http.StatusOK + id%2,
+			BytesWritten: int64(id),
+			Written:      true,
+		}, info)
+	}
+}
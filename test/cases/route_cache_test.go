@@ -0,0 +1,52 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithRouteCache(t *testing.T) {
+	// prepare router and span recorder
+	cache := otelchi.NewRouteCache(2)
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithRouteCache(cache))
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute requests, the unmatched request is cached as well
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/user/123", nil),
+		httptest.NewRequest("GET", "/user/123", nil),
+		httptest.NewRequest("GET", "/book/123", nil),
+	})
+	assert.Equal(t, 2, cache.Len())
+
+	// the cache is purged once it is full
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/456", nil)})
+	assert.Equal(t, 1, cache.Len())
+
+	// the cached result is kept until the cache is invalidated
+	router.HandleFunc("/book/{title}", ok)
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/456", nil)})
+	cache.Invalidate()
+	assert.Equal(t, 0, cache.Len())
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/book/123", nil)})
+
+	// check the recorded spans
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 6)
+	for _, span := range recordedSpans[:2] {
+		assertSpan(t, span, "/user/{id}", trace.SpanKindServer, codes.Unset,
+			attribute.String("http.route", "/user/{id}"),
+		)
+	}
+	assertSpan(t, recordedSpans[5], "/book/{title}", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.route", "/book/{title}"),
+	)
+}
@@ -0,0 +1,112 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithRouteConfig(t *testing.T) {
+	for _, withChiRoutes := range []bool{true, false} {
+		name := "Without Chi Routes"
+		if withChiRoutes {
+			name = "With Chi Routes"
+		}
+		t.Run(name, func(t *testing.T) {
+			// prepare router and span recorder
+			router, sr := newSDKTestRouter(
+				"foobar",
+				withChiRoutes,
+				otelchi.WithRouteConfig("/healthz", otelchi.RouteDisableTracing()),
+				otelchi.WithRouteConfig("/admin/*", otelchi.RouteAttributes(attribute.String("api.tier", "internal"))),
+				otelchi.WithRouteConfig("/admin/users/{id}", otelchi.RouteSpanName("admin get user")),
+			)
+			router.HandleFunc("/healthz", ok)
+			router.HandleFunc("/admin/users/{id}", ok)
+			router.HandleFunc("/admin/settings", ok)
+
+			// execute requests
+			executeRequests(router, []*http.Request{
+				httptest.NewRequest("GET", "/healthz", nil),
+				httptest.NewRequest("GET", "/admin/users/123", nil),
+				httptest.NewRequest("GET", "/admin/settings", nil),
+			})
+
+			// health check is only skipped when the route is resolved beforehand
+			recordedSpans := sr.Ended()
+			if withChiRoutes {
+				require.Len(t, recordedSpans, 2)
+			} else {
+				require.Len(t, recordedSpans, 3)
+				recordedSpans = recordedSpans[1:]
+			}
+
+			// the exact match takes precedence over the prefix match
+			assertSpan(t, recordedSpans[0], "admin get user", trace.SpanKindServer, codes.Unset,
+				attribute.String("http.route", "/admin/users/{id}"),
+			)
+			assertSpan(t, recordedSpans[1], "/admin/settings", trace.SpanKindServer, codes.Unset,
+				attribute.String("http.route", "/admin/settings"),
+				attribute.String("api.tier", "internal"),
+			)
+		})
+	}
+}
+
+func TestSDKIntegrationWithRouteAttributes(t *testing.T) {
+	for _, withChiRoutes := range []bool{true, false} {
+		name := "Without Chi Routes"
+		if withChiRoutes {
+			name = "With Chi Routes"
+		}
+		t.Run(name, func(t *testing.T) {
+			// prepare router and span recorder
+			router, sr := newSDKTestRouter(
+				"foobar",
+				withChiRoutes,
+				otelchi.WithRouteAttributes(map[string][]attribute.KeyValue{
+					"/admin/*":     {attribute.String("team", "platform")},
+					"/admin/audit": {attribute.String("team", "security")},
+				}),
+				otelchi.WithRouteAttributes(map[string][]attribute.KeyValue{
+					"/v1/users/{id}": {attribute.Bool("api.deprecated", true)},
+				}),
+			)
+			router.HandleFunc("/admin/users/{id}", ok)
+			router.HandleFunc("/admin/audit", ok)
+			router.HandleFunc("/v1/users/{id}", ok)
+			router.HandleFunc("/v2/users/{id}", ok)
+
+			// execute requests
+			executeRequests(router, []*http.Request{
+				httptest.NewRequest("GET", "/admin/users/1", nil),
+				httptest.NewRequest("GET", "/admin/audit", nil),
+				httptest.NewRequest("GET", "/v1/users/1", nil),
+				httptest.NewRequest("GET", "/v2/users/1", nil),
+			})
+
+			// ensure the attributes are added to the matching routes
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, 4)
+			assertSpan(t, recordedSpans[0], "/admin/users/{id}", trace.SpanKindServer, codes.Unset,
+				attribute.String("team", "platform"),
+			)
+			assertSpan(t, recordedSpans[1], "/admin/audit", trace.SpanKindServer, codes.Unset,
+				attribute.String("team", "security"),
+			)
+			assertSpan(t, recordedSpans[2], "/v1/users/{id}", trace.SpanKindServer, codes.Unset,
+				attribute.Bool("api.deprecated", true),
+			)
+			for _, attr := range recordedSpans[3].Attributes() {
+				require.NotEqual(t, attribute.Key("api.deprecated"), attr.Key)
+				require.NotEqual(t, attribute.Key("team"), attr.Key)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDKIntegrationWithRouteFilter(t *testing.T) {
+	// prepare router and span recorder
+	var unmatchedRoute *string
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithRouteFilter(func(route string, r *http.Request) bool {
+		if r.URL.Path == "/unknown" {
+			unmatchedRoute = &route
+		}
+		return route != "/metrics" && !strings.HasPrefix(route, "/debug/")
+	}))
+	router.HandleFunc("/user/{id}", ok)
+	router.HandleFunc("/metrics", ok)
+	router.HandleFunc("/debug/*", ok)
+
+	// execute requests
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/user/123", nil),
+		httptest.NewRequest("GET", "/metrics", nil),
+		httptest.NewRequest("GET", "/debug/pprof/heap", nil),
+		httptest.NewRequest("GET", "/unknown", nil),
+	})
+
+	// ensure the requests are filtered by their route
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	assert.Equal(t, "/user/{id}", recordedSpans[0].Name())
+	assert.Equal(t, "/", recordedSpans[1].Name())
+	require.NotNil(t, unmatchedRoute)
+	assert.Empty(t, *unmatchedRoute)
+}
+
+func TestRouteFilterWithoutChiRoutes(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", false, otelchi.WithRouteFilter(func(route string, r *http.Request) bool {
+		return false
+	}))
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+	// ensure route filter is ignored since the route cannot be resolved
+	// before the span is started
+	require.Len(t, sr.Ended(), 1)
+}
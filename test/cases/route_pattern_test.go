@@ -0,0 +1,142 @@
+package otelchi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRoutePattern(t *testing.T) {
+	for _, withChiRoutes := range []bool{true, false} {
+		// prepare router with logger middleware reading the route pattern
+		var (
+			beforeRoute, inHandler, afterHandler       string
+			beforeRouteOK, inHandlerOK, afterHandlerOK bool
+		)
+		router, _ := newSDKTestRouter("foobar", withChiRoutes, otelchi.WithMaxRouteCardinality(1, ""))
+		router.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				beforeRoute, beforeRouteOK = otelchi.RoutePattern(r.Context())
+				next.ServeHTTP(w, r)
+				afterHandler, afterHandlerOK = otelchi.RoutePattern(r.Context())
+			})
+		})
+		router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+			inHandler, inHandlerOK = otelchi.RoutePattern(r.Context())
+		})
+		router.HandleFunc("/book/{title}", ok)
+
+		// execute request
+		executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+		// ensure the route pattern is available
+		assert.Equal(t, withChiRoutes, beforeRouteOK)
+		if withChiRoutes {
+			assert.Equal(t, "/user/{id}", beforeRoute)
+		}
+		assert.True(t, inHandlerOK)
+		assert.Equal(t, "/user/{id}", inHandler)
+		assert.True(t, afterHandlerOK)
+		assert.Equal(t, "/user/{id}", afterHandler)
+
+		// ensure the route pattern follows the cardinality limit
+		executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/book/foo", nil)})
+		assert.True(t, afterHandlerOK)
+		assert.Equal(t, otelchi.DefaultRouteCardinalityFallback, afterHandler)
+	}
+
+	// ensure the route pattern is not available outside the middleware
+	_, ok := otelchi.RoutePattern(context.Background())
+	assert.False(t, ok)
+}
+
+func TestRoutePatternMatchesSpanRoute(t *testing.T) {
+	// prepare router without chi routes, so the route is resolved by the
+	// middleware only after the handler returns
+	var (
+		inHandler   []string
+		inHandlerOK []bool
+	)
+	readRoute := func(w http.ResponseWriter, r *http.Request) {
+		route, ok := otelchi.RoutePattern(r.Context())
+		inHandler = append(inHandler, route)
+		inHandlerOK = append(inHandlerOK, ok)
+		w.WriteHeader(http.StatusNotFound)
+	}
+	router, sr := newSDKTestRouter("foobar", false,
+		otelchi.WithMountPrefix("/api"),
+		otelchi.WithNotFoundRouteLabel("{not_found}"),
+	)
+	router.HandleFunc("/user/{id}", readRoute)
+	router.NotFound(readRoute)
+
+	// execute requests
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/user/123", nil),
+		httptest.NewRequest("GET", "/unknown", nil),
+	})
+
+	// ensure the route read in the handler is the same as the span route
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	assert.Equal(t, []bool{true, true}, inHandlerOK)
+	assert.Equal(t, []string{"/api/user/{id}", "{not_found}"}, inHandler)
+	for i, span := range recordedSpans {
+		assertSpan(t, span, inHandler[i], trace.SpanKindServer, codes.Unset,
+			attribute.String("http.route", inHandler[i]),
+		)
+	}
+}
+
+func TestStripRouteRegexps(t *testing.T) {
+	testCases := map[string]string{
+		"/users/{id:[0-9]+}":          "/users/{id}",
+		"/codes/{code:[A-Z]{3}}/{id}": "/codes/{code}/{id}",
+		"/files/{name:^[a-z:]+$}/raw": "/files/{name}/raw",
+		"/users/{id}":                 "/users/{id}",
+		"/static/*":                   "/static/*",
+	}
+	for pattern, expected := range testCases {
+		assert.Equal(t, expected, otelchi.StripRouteRegexps(pattern), pattern)
+	}
+}
+
+func TestSDKIntegrationWithRoutePatternNormalizer(t *testing.T) {
+	for _, withChiRoutes := range []bool{true, false} {
+		// prepare router & span recorder
+		router, sr := newSDKTestRouter("foobar", withChiRoutes,
+			otelchi.WithRequestMethodInSpanName(true),
+			otelchi.WithRoutePatternNormalizer(otelchi.StripRouteRegexps),
+		)
+		router.HandleFunc("/users/{id:[0-9]+}", ok)
+		router.HandleFunc("/books/{title}", ok)
+
+		// execute requests
+		executeRequests(router, []*http.Request{
+			httptest.NewRequest("GET", "/users/123", nil),
+			httptest.NewRequest("GET", "/books/foo", nil),
+		})
+
+		// ensure the normalized route is recorded & the original one is kept
+		recordedSpans := sr.Ended()
+		require.Len(t, recordedSpans, 2)
+		assertSpan(t, recordedSpans[0], "GET /users/{id}", trace.SpanKindServer, codes.Unset,
+			attribute.String("http.route", "/users/{id}"),
+			otelchi.RouteOriginalKey.String("/users/{id:[0-9]+}"),
+		)
+		assertSpan(t, recordedSpans[1], "GET /books/{title}", trace.SpanKindServer, codes.Unset,
+			attribute.String("http.route", "/books/{title}"),
+		)
+		for _, attr := range recordedSpans[1].Attributes() {
+			assert.NotEqual(t, otelchi.RouteOriginalKey, attr.Key)
+		}
+	}
+}
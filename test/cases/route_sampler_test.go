@@ -0,0 +1,57 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/riandyrn/otelchi/sampling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRouteSampler(t *testing.T) {
+	// prepare router sampling the checkout route only, the unknown routes
+	// are dropped by the fallback
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sampling.RouteSampler(map[string]float64{
+			"/checkout": 1,
+			"/assets/*": 0,
+		}, sdktrace.NeverSample())),
+		sdktrace.WithSpanProcessor(spanRecorder),
+	)
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware("foobar",
+		otelchi.WithChiRoutes(router),
+		otelchi.WithTracerProvider(tracerProvider),
+	))
+	router.HandleFunc("/checkout", ok)
+	router.HandleFunc("/assets/*", ok)
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute requests
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/assets/app.js", nil),
+		httptest.NewRequest("GET", "/checkout", nil),
+		httptest.NewRequest("GET", "/user/123", nil),
+		httptest.NewRequest("GET", "/assets/app.css", nil),
+	})
+
+	// ensure only the checkout request is sampled
+	recordedSpans := spanRecorder.Ended()
+	require.Len(t, recordedSpans, 1)
+	assert.Equal(t, "/checkout", recordedSpans[0].Name())
+}
+
+func TestRouteSamplerDefaultFallback(t *testing.T) {
+	sampler := sampling.RouteSampler(map[string]float64{"/assets/*": 0.01}, nil)
+	assert.Equal(t, "RouteSampler{/assets/*:TraceIDRatioBased{0.01},fallback:AlwaysOnSampler}", sampler.Description())
+
+	result := sampler.ShouldSample(sdktrace.SamplingParameters{Name: "/user/{id}"})
+	assert.Equal(t, sdktrace.RecordAndSample, result.Decision)
+}
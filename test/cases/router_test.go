@@ -0,0 +1,49 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewRouter(t *testing.T) {
+	// prepare span recorder
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	provider.RegisterSpanProcessor(sr)
+
+	// prepare router with additional middleware
+	var routeInMiddleware string
+	router := otelchi.NewRouter(
+		"foobar",
+		otelchi.WithTracerProvider(provider),
+		otelchi.WithRouterMiddlewares(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// the span is already started with the resolved route
+				routeInMiddleware = sr.Started()[0].Name()
+				next.ServeHTTP(w, r)
+			})
+		}),
+	)
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+	// ensure the request is traced
+	assert.Equal(t, "/user/{id}", routeInMiddleware)
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.route", "/user/{id}"),
+	)
+}
@@ -0,0 +1,53 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestSDKIntegrationInstrumentationScope(t *testing.T) {
+	// define test cases
+	testCases := []struct {
+		Name         string
+		Opts         []otelchi.Option
+		ExpSchemaURL string
+		ExpAttrs     attribute.Set
+	}{
+		{
+			Name:         "Default Schema URL",
+			ExpSchemaURL: "https://opentelemetry.io/schemas/1.20.0",
+			ExpAttrs:     attribute.NewSet(),
+		},
+		{
+			Name: "Custom Schema URL & Scope Attributes",
+			Opts: []otelchi.Option{
+				otelchi.WithSchemaURL("https://opentelemetry.io/schemas/1.26.0"),
+				otelchi.WithScopeAttributes(attribute.String("team", "platform")),
+			},
+			ExpSchemaURL: "https://opentelemetry.io/schemas/1.26.0",
+			ExpAttrs:     attribute.NewSet(attribute.String("team", "platform")),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			router, sr := newSDKTestRouter("foobar", true, testCase.Opts...)
+			router.HandleFunc("/user/{id}", ok)
+			executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, 1)
+
+			scope := recordedSpans[0].InstrumentationScope()
+			assert.Equal(t, "github.com/riandyrn/otelchi", scope.Name)
+			assert.Equal(t, otelchi.Version(), scope.Version)
+			assert.Equal(t, testCase.ExpSchemaURL, scope.SchemaURL)
+			assert.Equal(t, testCase.ExpAttrs, scope.Attributes)
+		})
+	}
+}
@@ -11,7 +11,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/gorilla/websocket"
 	"github.com/riandyrn/otelchi"
-	"github.com/stretchr/testify/assert"
+	"github.com/riandyrn/otelchi/otelchitest"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -711,20 +711,12 @@ func TestWithPublicEndpointFn(t *testing.T) {
 func assertSpan(t *testing.T, span sdktrace.ReadOnlySpan, name string, kind trace.SpanKind, status codes.Code, attrs ...attribute.KeyValue) {
 	t.Helper()
 
-	assert.Equal(t, name, span.Name())
-	assert.Equal(t, kind, span.SpanKind())
-	assert.Equal(t, status, span.Status().Code)
-
-	got := make(map[attribute.Key]attribute.Value, len(span.Attributes()))
-	for _, a := range span.Attributes() {
-		got[a.Key] = a.Value
-	}
-	for _, want := range attrs {
-		if !assert.Contains(t, got, want.Key) {
-			continue
-		}
-		assert.Equal(t, want.Value, got[want.Key])
-	}
+	otelchitest.AssertSpan(t, span, otelchitest.SpanWant{
+		Name:       name,
+		Kind:       kind,
+		Status:     status,
+		Attributes: attrs,
+	})
 }
 
 func ok(w http.ResponseWriter, _ *http.Request) {
@@ -841,3 +833,35 @@ func TestSDKIntegrationWithWebsocket(t *testing.T) {
 		},
 	})
 }
+
+func TestSDKIntegrationOverrideSpanNameWithoutChiRoutes(t *testing.T) {
+	// prepare test router and span recorder
+	router, sr := newSDKTestRouter("foobar", false, otelchi.WithRequestMethodInSpanName(true))
+
+	// define route
+	var startName string
+	router.HandleFunc("/user/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		span := trace.SpanFromContext(r.Context())
+		span.SetName("overriden span name")
+		w.WriteHeader(http.StatusOK)
+	})
+	router.HandleFunc("/book/{title}", func(w http.ResponseWriter, r *http.Request) {
+		startName = sr.Started()[len(sr.Started())-1].Name()
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute requests
+	reqs := []*http.Request{
+		httptest.NewRequest("GET", "/user/123", nil),
+		httptest.NewRequest("GET", "/book/foo", nil),
+	}
+	executeRequests(router, reqs)
+
+	// ensure the span is named by the raw path until the route is resolved,
+	// the name given by the handler is kept
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, len(reqs))
+	require.Equal(t, "overriden span name", recordedSpans[0].Name())
+	require.Equal(t, "GET /book/foo", startName)
+	require.Equal(t, "GET /book/{title}", recordedSpans[1].Name())
+}
@@ -0,0 +1,62 @@
+package otelchi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithSecondaryTracerProvider(t *testing.T) {
+	// prepare router and span recorders
+	secondaryRecorder := tracetest.NewSpanRecorder()
+	secondaryProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanProcessor(secondaryRecorder),
+	)
+	router, sr := newSDKTestRouter("foobar", true,
+		otelchi.WithSecondaryTracerProvider(secondaryProvider),
+		otelchi.WithPropagators(propagation.TraceContext{}),
+		otelchi.WithWrappedHandlerSpan(true),
+	)
+	router.With(otelchi.WrapHandler).HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		trace.SpanFromContext(r.Context()).AddEvent("cache_miss")
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	// execute request with the incoming trace context
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), sc)
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(r.Header))
+	executeRequests(router, []*http.Request{r})
+
+	// ensure the spans are recorded into both tracer providers
+	primarySpans := sr.Ended()
+	secondarySpans := secondaryRecorder.Ended()
+	require.Len(t, primarySpans, 2)
+	require.Len(t, secondarySpans, 2)
+	for _, spans := range [][]sdktrace.ReadOnlySpan{primarySpans, secondarySpans} {
+		handlerSpan, serverSpan := spans[0], spans[1]
+		assertSpan(t, serverSpan, "/user/{id}", trace.SpanKindServer, codes.Error,
+			attribute.String("http.route", "/user/{id}"),
+			attribute.Int("http.status_code", http.StatusInternalServerError),
+		)
+		assert.Equal(t, sc.TraceID(), serverSpan.SpanContext().TraceID())
+		assert.Equal(t, sc.SpanID(), serverSpan.Parent().SpanID())
+		assert.Equal(t, serverSpan.SpanContext().SpanID(), handlerSpan.Parent().SpanID())
+
+		// ensure the event added by the handler is recorded as well
+		require.Len(t, handlerSpan.Events(), 1)
+		assert.Equal(t, "cache_miss", handlerSpan.Events()[0].Name)
+	}
+}
@@ -0,0 +1,96 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func getStableSemanticAttributes(serverName string, httpStatusCode int, httpMethod, httpRoute string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("server.address", serverName),
+		attribute.String("http.request.method", httpMethod),
+		attribute.String("url.scheme", "http"),
+		attribute.String("network.protocol.name", "http"),
+		attribute.String("network.protocol.version", "1.1"),
+		attribute.String("client.address", "192.0.2.1"),
+		attribute.String("network.peer.address", "192.0.2.1"),
+		attribute.String("http.route", httpRoute),
+		attribute.Int("http.response.status_code", httpStatusCode),
+	}
+}
+
+func TestSDKIntegrationWithSemconvVersion(t *testing.T) {
+	// define test cases
+	testCases := []struct {
+		Name        string
+		Opts        []otelchi.Option
+		Env         string
+		ExpAttrs    []attribute.KeyValue
+		NotExpAttrs []attribute.Key
+	}{
+		{
+			Name:        "Default Old",
+			ExpAttrs:    getSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}"),
+			NotExpAttrs: []attribute.Key{"http.request.method", "http.response.status_code"},
+		},
+		{
+			Name:        "New",
+			Opts:        []otelchi.Option{otelchi.WithSemconvVersion(otelchi.SemconvNew)},
+			ExpAttrs:    getStableSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}"),
+			NotExpAttrs: []attribute.Key{"http.method", "http.status_code", "net.host.name"},
+		},
+		{
+			Name: "Dual",
+			Opts: []otelchi.Option{otelchi.WithSemconvVersion(otelchi.SemconvDual)},
+			ExpAttrs: append(
+				getSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}"),
+				getStableSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}")...,
+			),
+		},
+		{
+			Name:        "Env New",
+			Env:         "http",
+			ExpAttrs:    getStableSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}"),
+			NotExpAttrs: []attribute.Key{"http.method", "http.status_code", "net.host.name"},
+		},
+		{
+			Name: "Env Dual",
+			Env:  "http/dup",
+			ExpAttrs: append(
+				getSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}"),
+				getStableSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}")...,
+			),
+		},
+		{
+			Name:        "Option Overrides Env",
+			Env:         "http",
+			Opts:        []otelchi.Option{otelchi.WithSemconvVersion(otelchi.SemconvOld)},
+			ExpAttrs:    getSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}"),
+			NotExpAttrs: []attribute.Key{"http.request.method", "http.response.status_code"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			t.Setenv("OTEL_SEMCONV_STABILITY_OPT_IN", testCase.Env)
+
+			router, sr := newSDKTestRouter("foobar", true, testCase.Opts...)
+			router.HandleFunc("/user/{id}", ok)
+			executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, 1)
+			assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset, testCase.ExpAttrs...)
+			for _, attr := range recordedSpans[0].Attributes() {
+				assert.NotContains(t, testCase.NotExpAttrs, attr.Key)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithDynamicServerName(t *testing.T) {
+	// prepare router and span recorder
+	serverName := otelchi.NewServerName("foobar")
+	router, sr := newSDKTestRouter("ignored", true, otelchi.WithDynamicServerName(serverName))
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute requests before and after the server name is updated
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+	serverName.Set("api.example.com")
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/456", nil)})
+
+	// ensure the server name follows the update
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	checkSpans(t, recordedSpans, []spanValueCheck{
+		{
+			Name:       "/user/{id}",
+			Kind:       trace.SpanKindServer,
+			Status:     codes.Unset,
+			Attributes: getSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}"),
+		},
+		{
+			Name:       "/user/{id}",
+			Kind:       trace.SpanKindServer,
+			Status:     codes.Unset,
+			Attributes: getSemanticAttributes("api.example.com", http.StatusOK, "GET", "/user/{id}"),
+		},
+	})
+}
+
+func TestSDKIntegrationWithServerNameFn(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithServerNameFn(func(r *http.Request) string {
+		if r.Host == "example.com" {
+			return ""
+		}
+		return r.Host
+	}))
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute requests for different hosts
+	r0 := httptest.NewRequest("GET", "/user/123", nil)
+	r0.Host = "tenant-a.example.com"
+	r1 := httptest.NewRequest("GET", "/user/456", nil)
+	executeRequests(router, []*http.Request{r0, r1})
+
+	// ensure the server name is derived per request
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	checkSpans(t, recordedSpans, []spanValueCheck{
+		{
+			Name:       "/user/{id}",
+			Kind:       trace.SpanKindServer,
+			Status:     codes.Unset,
+			Attributes: getSemanticAttributes("tenant-a.example.com", http.StatusOK, "GET", "/user/{id}"),
+		},
+		{
+			Name:       "/user/{id}",
+			Kind:       trace.SpanKindServer,
+			Status:     codes.Unset,
+			Attributes: getSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}"),
+		},
+	})
+}
@@ -0,0 +1,49 @@
+package otelchi_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDKIntegrationWithServerTimingHeader(t *testing.T) {
+	// prepare router and span recorder
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithServerTimingHeader(), otelchi.WithClock(clock))
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		clock.Advance(12345 * time.Microsecond)
+		w.WriteHeader(http.StatusOK)
+		// the time spent after the header is written is not included
+		clock.Advance(time.Second)
+	})
+	router.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {
+		clock.Advance(5 * time.Millisecond)
+	})
+
+	// execute requests
+	w1 := httptest.NewRecorder()
+	router.ServeHTTP(w1, httptest.NewRequest("GET", "/user/123", nil))
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, httptest.NewRequest("GET", "/empty", nil))
+
+	// ensure the header reflects the duration & the span context
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	for i, testCase := range []struct {
+		w   *httptest.ResponseRecorder
+		dur string
+	}{
+		{w: w1, dur: "12.345"},
+		{w: w2, dur: "5.000"},
+	} {
+		spanCtx := recordedSpans[i].SpanContext()
+		exp := fmt.Sprintf(`traceparent;desc="00-%s-%s-01", app;dur=%s`, spanCtx.TraceID(), spanCtx.SpanID(), testCase.dur)
+		assert.Equal(t, exp, testCase.w.Header().Get("Server-Timing"))
+	}
+}
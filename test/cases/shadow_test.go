@@ -0,0 +1,36 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithShadowTraffic(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithShadowTraffic(nil))
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute both production & shadow requests
+	r0 := httptest.NewRequest("GET", "/user/123", nil)
+	r1 := httptest.NewRequest("GET", "/user/123", nil)
+	r1.Header.Set(otelchi.DefaultShadowRequestHeader, "true")
+	executeRequests(router, []*http.Request{r0, r1})
+
+	// ensure only the shadow request is tagged
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	for _, attr := range recordedSpans[0].Attributes() {
+		assert.NotEqual(t, otelchi.ShadowRequestKey, attr.Key)
+	}
+	assertSpan(t, recordedSpans[1], "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.Bool("request.shadow", true),
+	)
+}
@@ -0,0 +1,72 @@
+package otelchi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDKIntegrationWithSlogLogger(t *testing.T) {
+	// prepare router & span recorder
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	router, sr := newSDKTestRouter("foobar", false, otelchi.WithSlogLogger(logger))
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		// log without the request context
+		otelchi.LoggerFromContext(r.Context()).Info("user fetched", "id", "123")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+	// ensure the log record is annotated with the trace & route information
+	require.Len(t, sr.Ended(), 1)
+	spanCtx := sr.Ended()[0].SpanContext()
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "user fetched", record["msg"])
+	assert.Equal(t, "123", record["id"])
+	assert.Equal(t, spanCtx.TraceID().String(), record[otelchi.SlogTraceIDKey])
+	assert.Equal(t, spanCtx.SpanID().String(), record[otelchi.SlogSpanIDKey])
+	assert.Equal(t, "/user/{id}", record[otelchi.SlogRouteKey])
+}
+
+func TestSDKIntegrationWithSlogHandler(t *testing.T) {
+	// prepare router & span recorder
+	var buf bytes.Buffer
+	logger := slog.New(otelchi.SlogHandler(slog.NewJSONHandler(&buf, nil)))
+	router, sr := newSDKTestRouter("foobar", true)
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "user fetched")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+	// ensure the log record is annotated with the trace & route information
+	require.Len(t, sr.Ended(), 1)
+	spanCtx := sr.Ended()[0].SpanContext()
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, spanCtx.TraceID().String(), record[otelchi.SlogTraceIDKey])
+	assert.Equal(t, spanCtx.SpanID().String(), record[otelchi.SlogSpanIDKey])
+	assert.Equal(t, "/user/{id}", record[otelchi.SlogRouteKey])
+
+	// ensure the record outside the request is not annotated
+	buf.Reset()
+	logger.InfoContext(context.Background(), "started")
+	record = nil
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.NotContains(t, record, otelchi.SlogTraceIDKey)
+	assert.NotContains(t, record, otelchi.SlogRouteKey)
+}
@@ -0,0 +1,90 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithInternalRequestFn(t *testing.T) {
+	// prepare router and span recorder, request having `X-Mesh-Internal`
+	// header is considered as internal request
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithInternalRequestFn(func(r *http.Request) bool {
+		return r.Header.Get("X-Mesh-Internal") == "true"
+	}))
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute requests
+	r0 := httptest.NewRequest("GET", "/user/123", nil)
+	r1 := httptest.NewRequest("GET", "/user/456", nil)
+	r1.Header.Set("X-Mesh-Internal", "true")
+	executeRequests(router, []*http.Request{r0, r1})
+
+	// ensure span kinds
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	checkSpans(t, recordedSpans, []spanValueCheck{
+		{
+			Name:       "/user/{id}",
+			Kind:       trace.SpanKindServer,
+			Status:     codes.Unset,
+			Attributes: getSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}"),
+		},
+		{
+			Name:       "/user/{id}",
+			Kind:       trace.SpanKindInternal,
+			Status:     codes.Unset,
+			Attributes: getSemanticAttributes("foobar", http.StatusOK, "GET", "/user/{id}"),
+		},
+	})
+}
+
+func TestSDKIntegrationWithSpanKindFn(t *testing.T) {
+	// prepare router and span recorder, the internal endpoints are recorded
+	// as internal spans while the unspecified kind falls back to the
+	// internal request function
+	router, sr := newSDKTestRouter("foobar", true,
+		otelchi.WithSpanKindFn(func(r *http.Request) trace.SpanKind {
+			if strings.HasPrefix(r.URL.Path, "/internal/") {
+				return trace.SpanKindInternal
+			}
+			if r.Header.Get("X-Mesh-Internal") == "true" {
+				return trace.SpanKindServer
+			}
+			return trace.SpanKindUnspecified
+		}),
+		otelchi.WithInternalRequestFn(func(r *http.Request) bool {
+			return r.Header.Get("X-Mesh-Internal") == "true" || r.Header.Get("X-Forwarded") == "true"
+		}),
+	)
+	router.HandleFunc("/user/{id}", ok)
+	router.HandleFunc("/internal/cache", ok)
+
+	// execute requests
+	r0 := httptest.NewRequest("GET", "/internal/cache", nil)
+	r1 := httptest.NewRequest("GET", "/user/123", nil)
+	r1.Header.Set("X-Mesh-Internal", "true")
+	r2 := httptest.NewRequest("GET", "/user/456", nil)
+	r2.Header.Set("X-Forwarded", "true")
+	r3 := httptest.NewRequest("GET", "/user/789", nil)
+	executeRequests(router, []*http.Request{r0, r1, r2, r3})
+
+	// ensure span kinds
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 4)
+	expKinds := []trace.SpanKind{
+		trace.SpanKindInternal,
+		trace.SpanKindServer,
+		trace.SpanKindInternal,
+		trace.SpanKindServer,
+	}
+	for i, span := range recordedSpans {
+		require.Equal(t, expKinds[i], span.SpanKind(), "span %d", i)
+	}
+}
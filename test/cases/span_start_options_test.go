@@ -0,0 +1,53 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithSpanStartOptionsFn(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true,
+		otelchi.WithSpanStartOptionsFn(func(r *http.Request) []trace.SpanStartOption {
+			// use the request start time set by the load balancer
+			opts := []trace.SpanStartOption{
+				trace.WithLinks(trace.Link{SpanContext: sc}),
+				trace.WithAttributes(attribute.String("tenant.id", r.Header.Get("X-Tenant-ID"))),
+			}
+			if ms, err := strconv.ParseInt(r.Header.Get("X-Request-Start"), 10, 64); err == nil {
+				opts = append(opts, trace.WithTimestamp(time.UnixMilli(ms)))
+			}
+			return opts
+		}),
+	)
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute request
+	requestStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := httptest.NewRequest("GET", "/user/123", nil)
+	r.Header.Set("X-Request-Start", strconv.FormatInt(requestStart.UnixMilli(), 10))
+	r.Header.Set("X-Tenant-ID", "acme")
+	executeRequests(router, []*http.Request{r})
+
+	// check the recorded span
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	span := recordedSpans[0]
+	assertSpan(t, span, "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("tenant.id", "acme"),
+		attribute.String("http.route", "/user/{id}"),
+	)
+	assert.True(t, requestStart.Equal(span.StartTime()))
+	require.Len(t, span.Links(), 1)
+	assert.Equal(t, sc, span.Links()[0].SpanContext)
+}
@@ -0,0 +1,55 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithSSEInstrumentation(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithSSEInstrumentation())
+	router.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		for _, msg := range []string{"data: a\n\n", "data: bc\n\n"} {
+			_, _ = w.Write([]byte(msg))
+			w.(http.Flusher).Flush()
+		}
+	})
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+		w.(http.Flusher).Flush()
+	})
+
+	// execute both event stream & regular requests
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/events", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/user/123", nil))
+
+	// ensure the event stream span records every flush
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	assertSpan(t, recordedSpans[0], "/events", trace.SpanKindServer, codes.Unset,
+		attribute.Int("http.response.body.size", 19),
+		attribute.Int64("sse.flush_count", 2),
+	)
+	events := recordedSpans[0].Events()
+	require.Len(t, events, 2)
+	for i, bytesSent := range []int64{9, 19} {
+		assert.Equal(t, otelchi.SSEFlushEventName, events[i].Name)
+		assert.Contains(t, events[i].Attributes, otelchi.SSEBytesSentKey.Int64(bytesSent))
+		assert.Contains(t, events[i].Attributes, otelchi.SSEFlushCountKey.Int64(int64(i+1)))
+	}
+
+	// ensure regular response is not affected
+	assert.Empty(t, recordedSpans[1].Events())
+	for _, attr := range recordedSpans[1].Attributes() {
+		assert.NotEqual(t, otelchi.SSEFlushCountKey, attr.Key)
+	}
+}
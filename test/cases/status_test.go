@@ -0,0 +1,82 @@
+package otelchi_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationDefaultSpanStatus(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true)
+	router.HandleFunc("/status/{code}", writeStatusFromURL)
+
+	// execute requests
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/status/200", nil),
+		httptest.NewRequest("GET", "/status/404", nil),
+		httptest.NewRequest("GET", "/status/503", nil),
+	})
+
+	// ensure only 5xx is marked as error
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 3)
+	assert.Equal(t, codes.Unset, recordedSpans[0].Status().Code)
+	assert.Equal(t, codes.Unset, recordedSpans[1].Status().Code)
+	assert.Equal(t, codes.Error, recordedSpans[2].Status().Code)
+}
+
+func TestSDKIntegrationWithSpanStatusFnAndErrorHook(t *testing.T) {
+	// prepare router and span recorder, mark 4xx as error too
+	var hookStatuses []int
+	router, sr := newSDKTestRouter(
+		"foobar",
+		true,
+		otelchi.WithSpanStatusFn(func(statusCode int) (codes.Code, string) {
+			if statusCode >= http.StatusBadRequest {
+				return codes.Error, http.StatusText(statusCode)
+			}
+			return codes.Unset, ""
+		}),
+		otelchi.WithErrorHook(func(span trace.Span, r *http.Request, statusCode int) {
+			hookStatuses = append(hookStatuses, statusCode)
+			span.RecordError(errors.New("domain error"))
+		}),
+	)
+	router.HandleFunc("/status/{code}", writeStatusFromURL)
+
+	// execute requests
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/status/200", nil),
+		httptest.NewRequest("GET", "/status/404", nil),
+	})
+
+	// ensure the custom status mapping & error hook are applied
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	assert.Equal(t, codes.Unset, recordedSpans[0].Status().Code)
+	assert.Empty(t, recordedSpans[0].Events())
+	assert.Equal(t, codes.Error, recordedSpans[1].Status().Code)
+	assert.Equal(t, "Not Found", recordedSpans[1].Status().Description)
+	require.Len(t, recordedSpans[1].Events(), 1)
+	assert.Equal(t, "exception", recordedSpans[1].Events()[0].Name)
+	assert.Equal(t, []int{http.StatusNotFound}, hookStatuses)
+}
+
+func writeStatusFromURL(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/status/404":
+		w.WriteHeader(http.StatusNotFound)
+	case "/status/503":
+		w.WriteHeader(http.StatusServiceUnavailable)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
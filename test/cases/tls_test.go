@@ -0,0 +1,54 @@
+package otelchi_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithTLSClientIdentity(t *testing.T) {
+	// prepare router and span recorder, redact the namespace part of the
+	// spiffe identity
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithTLSClientIdentity(func(value string) string {
+		return strings.Replace(value, "/ns/payment", "/ns/***", 1)
+	}))
+	router.HandleFunc("/user/{id}", ok)
+
+	// prepare request with verified client certificate
+	spiffeID, err := url.Parse("spiffe://cluster.local/ns/payment/sa/billing")
+	require.NoError(t, err)
+	cert := &x509.Certificate{
+		Subject: pkix.Name{CommonName: "billing", Organization: []string{"acme"}},
+		URIs:    []*url.URL{spiffeID},
+	}
+	r0 := httptest.NewRequest("GET", "/user/123", nil)
+	r0.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+
+	// prepare request without client certificate
+	r1 := httptest.NewRequest("GET", "/user/456", nil)
+	executeRequests(router, []*http.Request{r0, r1})
+
+	// ensure identity only recorded for the request with client certificate
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	assertSpan(t, recordedSpans[0], "/user/{id}", trace.SpanKindServer, codes.Unset,
+		attribute.String("tls.client.subject", "CN=billing,O=acme"),
+		attribute.String("tls.client.subject.common_name", "billing"),
+		attribute.StringSlice("tls.client.subject.alt_names", []string{"spiffe://cluster.local/ns/***/sa/billing"}),
+	)
+	for _, attr := range recordedSpans[1].Attributes() {
+		assert.False(t, strings.HasPrefix(string(attr.Key), "tls.client"), "unexpected attribute: %s", attr.Key)
+	}
+}
@@ -0,0 +1,104 @@
+package otelchi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithTraceparentResponseHeader(t *testing.T) {
+	// prepare incoming span context having trace state
+	traceState, err := trace.ParseTraceState("vendor=value")
+	require.NoError(t, err)
+	remoteSpanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x01},
+		TraceFlags: trace.FlagsSampled,
+		TraceState: traceState,
+		Remote:     true,
+	})
+
+	// define test cases
+	testCases := []struct {
+		Name          string
+		Config        otelchi.TraceHeaderConfig
+		ExpTracestate string
+	}{
+		{
+			Name:          "Traceparent Only",
+			Config:        otelchi.TraceHeaderConfig{EmitTraceparent: true},
+			ExpTracestate: "",
+		},
+		{
+			Name:          "Traceparent & Tracestate",
+			Config:        otelchi.TraceHeaderConfig{EmitTraceparent: true, EmitTracestate: true},
+			ExpTracestate: "vendor=value",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			router, sr := newSDKTestRouter(
+				"foobar",
+				true,
+				otelchi.WithPropagators(propagation.TraceContext{}),
+				otelchi.WithTraceResponseHeaders(testCase.Config),
+			)
+			router.HandleFunc("/user/{id}", ok)
+
+			// execute request
+			req := httptest.NewRequest("GET", "/user/123", nil)
+			ctx := trace.ContextWithRemoteSpanContext(context.Background(), remoteSpanCtx)
+			propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// ensure the response headers refer to the server span
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, 1)
+			spanCtx := recordedSpans[0].SpanContext()
+			assert.Equal(t, fmt.Sprintf("00-%s-%s-01", spanCtx.TraceID(), spanCtx.SpanID()), w.Header().Get("traceparent"))
+			assert.Equal(t, testCase.ExpTracestate, w.Header().Get("tracestate"))
+			assert.Equal(t, spanCtx.TraceID().String(), w.Header().Get(otelchi.DefaultTraceIDResponseHeaderKey))
+		})
+	}
+}
+
+func TestSDKIntegrationWithConditionalTraceResponseHeaders(t *testing.T) {
+	// prepare router, the trace headers are only written for the requests
+	// having the debug header
+	router, sr := newSDKTestRouter(
+		"foobar",
+		true,
+		otelchi.WithTraceResponseHeaders(otelchi.TraceHeaderConfig{
+			EmitTraceparent: true,
+			SetHeaderFn: func(r *http.Request) bool {
+				return r.Header.Get("X-Debug") == "1"
+			},
+		}),
+	)
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute requests
+	public := httptest.NewRecorder()
+	router.ServeHTTP(public, httptest.NewRequest("GET", "/user/123", nil))
+	debugReq := httptest.NewRequest("GET", "/user/123", nil)
+	debugReq.Header.Set("X-Debug", "1")
+	debug := httptest.NewRecorder()
+	router.ServeHTTP(debug, debugReq)
+
+	// ensure only the debug request receives the trace headers
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 2)
+	assert.Empty(t, public.Header().Get(otelchi.DefaultTraceIDResponseHeaderKey))
+	assert.Empty(t, public.Header().Get("traceparent"))
+	assert.Equal(t, recordedSpans[1].SpanContext().TraceID().String(), debug.Header().Get(otelchi.DefaultTraceIDResponseHeaderKey))
+	assert.NotEmpty(t, debug.Header().Get("traceparent"))
+}
@@ -0,0 +1,90 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestDynamicTracerProvider(t *testing.T) {
+	defer func(provider trace.TracerProvider) {
+		otel.SetTracerProvider(provider)
+	}(otel.GetTracerProvider())
+
+	// prepare router before the SDK is initialized
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware("foobar", otelchi.WithDynamicTracerProvider()))
+	router.HandleFunc("/user/{id}", ok)
+
+	// swap the global tracer provider between requests
+	newRecordingProvider := func() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+		sr := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+		provider.RegisterSpanProcessor(sr)
+		return provider, sr
+	}
+	provider0, sr0 := newRecordingProvider()
+	otel.SetTracerProvider(provider0)
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+	provider1, sr1 := newRecordingProvider()
+	otel.SetTracerProvider(provider1)
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/456", nil)})
+
+	// ensure each request is traced by the current global tracer provider
+	require.Len(t, sr0.Ended(), 1)
+	require.Len(t, sr1.Ended(), 1)
+	assert.Equal(t, "/user/{id}", sr1.Ended()[0].Name())
+	assert.Equal(t, "github.com/riandyrn/otelchi", sr1.Ended()[0].InstrumentationScope().Name)
+}
+
+func TestSDKIntegrationWithTracerName(t *testing.T) {
+	// prepare router & span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithTracerName("example.com/platform/http"))
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+	// ensure the span is attributed to the given scope
+	require.Len(t, sr.Ended(), 1)
+	scope := sr.Ended()[0].InstrumentationScope()
+	assert.Equal(t, "example.com/platform/http", scope.Name)
+	assert.Equal(t, otelchi.Version(), scope.Version)
+}
+
+func TestSDKIntegrationWithTracer(t *testing.T) {
+	// prepare tracer created with custom options
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	provider.RegisterSpanProcessor(sr)
+	tracer := provider.Tracer("example.com/platform/http", trace.WithInstrumentationVersion("v1.2.3"))
+
+	// prepare router, the tracer takes precedence over the tracer provider
+	router := chi.NewRouter()
+	router.Use(otelchi.Middleware("foobar",
+		otelchi.WithTracer(tracer),
+		otelchi.WithTracerProvider(noop.NewTracerProvider()),
+	))
+	router.HandleFunc("/user/{id}", ok)
+
+	// execute request
+	executeRequests(router, []*http.Request{httptest.NewRequest("GET", "/user/123", nil)})
+
+	// ensure the span is created by the given tracer
+	require.Len(t, sr.Ended(), 1)
+	scope := sr.Ended()[0].InstrumentationScope()
+	assert.Equal(t, "example.com/platform/http", scope.Name)
+	assert.Equal(t, "v1.2.3", scope.Version)
+	assert.Equal(t, "/user/{id}", sr.Ended()[0].Name())
+}
@@ -0,0 +1,62 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithCapturedResponseTrailers(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter(
+		"foobar",
+		false,
+		otelchi.WithCapturedResponseTrailers("grpc-status", "Grpc-Message", "X-Checksum"),
+	)
+	router.HandleFunc("/declared", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "X-Checksum, Grpc-Status")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+		w.Header().Set("X-Checksum", "abc")
+		w.Header().Set("Grpc-Status", "0")
+	})
+	router.HandleFunc("/prefixed", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Header().Set(http.TrailerPrefix+"Grpc-Status", "5")
+		w.Header().Set(http.TrailerPrefix+"Grpc-Message", "user not found")
+	})
+	router.HandleFunc("/undeclared", func(w http.ResponseWriter, r *http.Request) {
+		// header, not trailer, since it is not declared
+		w.Header().Set("X-Checksum", "abc")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// execute requests, each one has its own recorder so the trailers don't
+	// leak between them
+	for _, path := range []string{"/declared", "/prefixed", "/undeclared"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", path, nil))
+	}
+
+	// ensure the trailers are captured & failed gRPC call is marked as error
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 3)
+	assertSpan(t, recordedSpans[0], "/declared", trace.SpanKindServer, codes.Unset,
+		attribute.StringSlice("http.response.trailer.x-checksum", []string{"abc"}),
+		attribute.StringSlice("http.response.trailer.grpc-status", []string{"0"}),
+	)
+	assertSpan(t, recordedSpans[1], "/prefixed", trace.SpanKindServer, codes.Error,
+		attribute.StringSlice("http.response.trailer.grpc-status", []string{"5"}),
+		attribute.StringSlice("http.response.trailer.grpc-message", []string{"user not found"}),
+	)
+	assert.Equal(t, "user not found", recordedSpans[1].Status().Description)
+	for _, attr := range recordedSpans[2].Attributes() {
+		assert.NotEqual(t, attribute.Key("http.response.trailer.x-checksum"), attr.Key)
+	}
+}
@@ -0,0 +1,36 @@
+package otelchi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithURLParamsAsAttributes(t *testing.T) {
+	// prepare router and span recorder
+	router, sr := newSDKTestRouter("foobar", true, otelchi.WithURLParamsAsAttributes("org", "project"))
+	router.HandleFunc("/orgs/{org}/projects/{project}/tokens/{token}", ok)
+
+	// execute request
+	executeRequests(router, []*http.Request{
+		httptest.NewRequest("GET", "/orgs/acme/projects/rocket/tokens/s3cr3t", nil),
+	})
+
+	// ensure only the allowed params are recorded
+	recordedSpans := sr.Ended()
+	require.Len(t, recordedSpans, 1)
+	assertSpan(t, recordedSpans[0], "/orgs/{org}/projects/{project}/tokens/{token}", trace.SpanKindServer, codes.Unset,
+		attribute.String("http.route.param.org", "acme"),
+		attribute.String("http.route.param.project", "rocket"),
+	)
+	for _, attr := range recordedSpans[0].Attributes() {
+		assert.NotEqual(t, attribute.Key("http.route.param.token"), attr.Key)
+	}
+}
@@ -18,3 +18,7 @@ func TestVersionSemver(t *testing.T) {
 	v := otelchi.Version()
 	assert.NotNil(t, versionRegex.FindStringSubmatch(v), "version is not semver: %s", v)
 }
+
+func TestSemVersion(t *testing.T) {
+	assert.Equal(t, "semver:"+otelchi.Version(), otelchi.SemVersion())
+}
@@ -0,0 +1,99 @@
+package otelchi_test
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/riandyrn/otelchi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSDKIntegrationWithWebsocketSpanMode(t *testing.T) {
+	testCases := []struct {
+		Name    string
+		Options []otelchi.Option
+		// Handler receives the hijacked connection & the span recorder
+		Handler func(t *testing.T, conn net.Conn, sr *tracetest.SpanRecorder)
+		// EndedAfterHandler is the number of ended spans after the handler
+		// returns
+		EndedAfterHandler int
+	}{
+		{
+			Name: "Default",
+			Handler: func(t *testing.T, conn net.Conn, sr *tracetest.SpanRecorder) {
+				assert.Empty(t, sr.Ended())
+				conn.Close()
+			},
+			EndedAfterHandler: 1,
+		},
+		{
+			Name:    "End On Upgrade",
+			Options: []otelchi.Option{otelchi.WithWebsocketSpanMode(otelchi.WebsocketEndOnUpgrade)},
+			Handler: func(t *testing.T, conn net.Conn, sr *tracetest.SpanRecorder) {
+				assert.Len(t, sr.Ended(), 1)
+				conn.Close()
+			},
+			EndedAfterHandler: 1,
+		},
+		{
+			Name:    "End On Close",
+			Options: []otelchi.Option{otelchi.WithWebsocketSpanMode(otelchi.WebsocketEndOnClose)},
+			Handler: func(t *testing.T, conn net.Conn, sr *tracetest.SpanRecorder) {
+				// the connection is served outside of the handler
+				t.Cleanup(func() {
+					assert.Empty(t, sr.Ended())
+					conn.Close()
+					assert.Len(t, sr.Ended(), 1)
+				})
+			},
+			EndedAfterHandler: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// prepare router and span recorder
+			router, sr := newSDKTestRouter("foobar", false, testCase.Options...)
+			router.HandleFunc("/ws/{id}", func(w http.ResponseWriter, r *http.Request) {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				require.NoError(t, err)
+				testCase.Handler(t, conn, sr)
+			})
+
+			// execute WebSocket upgrade request
+			r := httptest.NewRequest("GET", "/ws/123", nil)
+			r.Header.Set("Connection", "Upgrade")
+			r.Header.Set("Upgrade", "websocket")
+			router.ServeHTTP(&hijackableResponseWriter{ResponseRecorder: httptest.NewRecorder()}, r)
+
+			// ensure the span is ended according to the mode
+			recordedSpans := sr.Ended()
+			require.Len(t, recordedSpans, testCase.EndedAfterHandler)
+			for _, span := range recordedSpans {
+				assertSpan(t, span, "/ws/{id}", trace.SpanKindServer, codes.Unset,
+					attribute.String("http.route", "/ws/{id}"),
+					attribute.Bool("http.websocket.upgrade", true),
+				)
+			}
+		})
+	}
+}
+
+type hijackableResponseWriter struct {
+	*httptest.ResponseRecorder
+}
+
+// implement Hijacker
+func (rw *hijackableResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, peer := net.Pipe()
+	peer.Close()
+	return conn, bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn)), nil
+}
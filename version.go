@@ -1,6 +0,0 @@
-package otelchi
-
-// Version is the current release version of otelchi in use.
-func Version() string {
-	return "0.11.0"
-}